@@ -0,0 +1,357 @@
+// Command mockoidc - Identity provider OpenID Connect lokal untuk mencoba login OIDC tanpa IdP sungguhan.
+// Halaman /authorize menampilkan form (email, nama, grup, email terverifikasi) sebagai pengganti login IdP,
+// token endpoint memeriksa client & PKCE S256 lalu menerbitkan ID token RS256. Semua data di memori.
+//
+//	MOCK_OIDC_ADDR=:9000 MOCK_OIDC_CLIENT_ID=marketplace MOCK_OIDC_CLIENT_SECRET=secret go run ./cmd/mockoidc
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"technical-test-backend/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	codeTTL  = 2 * time.Minute
+	tokenTTL = time.Hour
+)
+
+// grant - Authorization code yang belum ditukar
+type grant struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	claims        jwt.MapClaims
+	expiresAt     time.Time
+}
+
+// accessToken - Access token untuk userinfo endpoint
+type accessToken struct {
+	claims    jwt.MapClaims
+	expiresAt time.Time
+}
+
+type provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	key          *rsa.PrivateKey
+	jwk          utils.JWK
+
+	mu     sync.Mutex
+	codes  map[string]grant
+	tokens map[string]accessToken
+}
+
+var authorizePage = template.Must(template.New("authorize").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Mock OIDC Login</title></head>
+<body style="font-family: sans-serif; max-width: 420px; margin: 40px auto">
+<h2>Mock OIDC Login</h2>
+<p>Client: <b>{{.ClientID}}</b></p>
+<form method="post" action="/authorize">
+{{range $name, $value := .Params}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}
+<p><label>Email<br><input type="email" name="email" required style="width: 100%"></label></p>
+<p><label>Nama<br><input type="text" name="name" style="width: 100%"></label></p>
+<p><label>Grup (dipisah koma)<br><input type="text" name="groups" style="width: 100%"></label></p>
+<p><label><input type="checkbox" name="email_verified" value="true" checked> Email terverifikasi</label></p>
+<p><button type="submit" name="action" value="login">Login</button> <button type="submit" name="action" value="deny">Tolak</button></p>
+</form>
+</body>
+</html>`))
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func randomString() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// oauthError - Error response token / userinfo endpoint (RFC 6749 5.2)
+func oauthError(w http.ResponseWriter, status int, code string, description string) {
+	writeJSON(w, status, map[string]string{"error": code, "error_description": description})
+}
+
+func main() {
+	addr := getEnv("MOCK_OIDC_ADDR", ":9000")
+	host := addr
+	if strings.HasPrefix(host, ":") {
+		host = "localhost" + host
+	}
+	issuer := getEnv("MOCK_OIDC_ISSUER", "http://"+host)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal(err)
+	}
+	jwk, err := utils.PublicJWK(&key.PublicKey, "RS256", "")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p := &provider{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientID:     getEnv("MOCK_OIDC_CLIENT_ID", "marketplace"),
+		clientSecret: os.Getenv("MOCK_OIDC_CLIENT_SECRET"),
+		key:          key,
+		jwk:          jwk,
+		codes:        map[string]grant{},
+		tokens:       map[string]accessToken{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("GET /jwks", p.jwks)
+	mux.HandleFunc("GET /authorize", p.authorizeForm)
+	mux.HandleFunc("POST /authorize", p.authorize)
+	mux.HandleFunc("POST /token", p.token)
+	mux.HandleFunc("GET /userinfo", p.userinfo)
+
+	log.Printf("Mock OIDC provider issuer=%s client_id=%s", p.issuer, p.clientID)
+	log.Fatal(http.ListenAndServe(addr, mux))
+}
+
+func (p *provider) discovery(w http.ResponseWriter, r *http.Request) {
+	authMethods := []string{"none"}
+	if p.clientSecret != "" {
+		authMethods = []string{"client_secret_basic", "client_secret_post"}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"userinfo_endpoint":                     p.issuer + "/userinfo",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"scopes_supported":                      []string{"openid", "email", "profile", "groups"},
+		"token_endpoint_auth_methods_supported": authMethods,
+		"code_challenge_methods_supported":      []string{"S256"},
+		"claims_supported":                      []string{"sub", "email", "email_verified", "name", "groups"},
+	})
+}
+
+func (p *provider) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, utils.JWKSet{Keys: []utils.JWK{p.jwk}})
+}
+
+// authorizeParams - Validasi parameter authorization request, PKCE S256 wajib
+func (p *provider) authorizeParams(values url.Values) (map[string]string, string) {
+	params := map[string]string{}
+	for _, name := range []string{"response_type", "client_id", "redirect_uri", "scope", "state", "nonce", "code_challenge", "code_challenge_method"} {
+		params[name] = values.Get(name)
+	}
+	switch {
+	case params["client_id"] != p.clientID:
+		return nil, "unknown client_id"
+	case params["redirect_uri"] == "":
+		return nil, "redirect_uri is required"
+	case params["response_type"] != "code":
+		return nil, "response_type must be code"
+	case !strings.Contains(" "+params["scope"]+" ", " openid "):
+		return nil, "scope must contain openid"
+	case params["code_challenge"] == "" || params["code_challenge_method"] != "S256":
+		return nil, "PKCE with code_challenge_method=S256 is required"
+	}
+	return params, ""
+}
+
+func (p *provider) authorizeForm(w http.ResponseWriter, r *http.Request) {
+	params, problem := p.authorizeParams(r.URL.Query())
+	if problem != "" {
+		http.Error(w, problem, http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	authorizePage.Execute(w, map[string]interface{}{"ClientID": p.clientID, "Params": params})
+}
+
+func (p *provider) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params, problem := p.authorizeParams(r.PostForm)
+	if problem != "" {
+		http.Error(w, problem, http.StatusBadRequest)
+		return
+	}
+
+	redirect, err := url.Parse(params["redirect_uri"])
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	query := redirect.Query()
+	query.Set("state", params["state"])
+
+	if r.PostForm.Get("action") == "deny" {
+		query.Set("error", "access_denied")
+		query.Set("error_description", "User menolak login")
+	} else {
+		email := strings.ToLower(strings.TrimSpace(r.PostForm.Get("email")))
+		if email == "" {
+			http.Error(w, "email is required", http.StatusBadRequest)
+			return
+		}
+		groups := []string{}
+		for _, group := range strings.Split(r.PostForm.Get("groups"), ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				groups = append(groups, group)
+			}
+		}
+		// sub tetap untuk email yang sama, seperti IdP sungguhan
+		sum := sha256.Sum256([]byte(email))
+		claims := jwt.MapClaims{
+			"sub":            base64.RawURLEncoding.EncodeToString(sum[:16]),
+			"email":          email,
+			"email_verified": r.PostForm.Get("email_verified") == "true",
+			"name":           strings.TrimSpace(r.PostForm.Get("name")),
+			"groups":         groups,
+		}
+
+		code := randomString()
+		p.mu.Lock()
+		p.codes[code] = grant{
+			clientID:      params["client_id"],
+			redirectURI:   params["redirect_uri"],
+			nonce:         params["nonce"],
+			codeChallenge: params["code_challenge"],
+			claims:        claims,
+			expiresAt:     time.Now().Add(codeTTL),
+		}
+		p.mu.Unlock()
+		query.Set("code", code)
+	}
+
+	redirect.RawQuery = query.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// authenticateClient - client_secret_basic / client_secret_post, atau public client jika secret tidak diset
+func (p *provider) authenticateClient(r *http.Request) bool {
+	clientID, secret, basic := r.BasicAuth()
+	if basic {
+		clientID, _ = url.QueryUnescape(clientID)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		clientID, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != p.clientID {
+		return false
+	}
+	return p.clientSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(p.clientSecret)) == 1
+}
+
+func (p *provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		oauthError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if !p.authenticateClient(r) {
+		oauthError(w, http.StatusUnauthorized, "invalid_client", "client authentication failed")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		oauthError(w, http.StatusBadRequest, "unsupported_grant_type", "only authorization_code is supported")
+		return
+	}
+
+	// Code sekali pakai
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	g, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+	if !ok || time.Now().After(g.expiresAt) {
+		oauthError(w, http.StatusBadRequest, "invalid_grant", "invalid or expired code")
+		return
+	}
+	if g.clientID != p.clientID || g.redirectURI != r.PostForm.Get("redirect_uri") {
+		oauthError(w, http.StatusBadRequest, "invalid_grant", "redirect_uri mismatch")
+		return
+	}
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(verifier[:]) != g.codeChallenge {
+		oauthError(w, http.StatusBadRequest, "invalid_grant", "PKCE verification failed")
+		return
+	}
+
+	now := time.Now()
+	idClaims := jwt.MapClaims{
+		"iss": p.issuer,
+		"aud": p.clientID,
+		"iat": now.Unix(),
+		"exp": now.Add(tokenTTL).Unix(),
+	}
+	if g.nonce != "" {
+		idClaims["nonce"] = g.nonce
+	}
+	for name, value := range g.claims {
+		idClaims[name] = value
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, idClaims)
+	token.Header["kid"] = p.jwk.Kid
+	idToken, err := token.SignedString(p.key)
+	if err != nil {
+		oauthError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+
+	access := randomString()
+	p.mu.Lock()
+	p.tokens[access] = accessToken{claims: g.claims, expiresAt: now.Add(tokenTTL)}
+	p.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": access,
+		"token_type":   "Bearer",
+		"expires_in":   int(tokenTTL.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+func (p *provider) userinfo(w http.ResponseWriter, r *http.Request) {
+	access, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	p.mu.Lock()
+	t, ok := p.tokens[access]
+	p.mu.Unlock()
+	if !found || !ok || time.Now().After(t.expiresAt) {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		oauthError(w, http.StatusUnauthorized, "invalid_token", "invalid or expired access token")
+		return
+	}
+	writeJSON(w, http.StatusOK, t.claims)
+}
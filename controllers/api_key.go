@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"technical-test-backend/models"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var apiKeyService = services.APIKeyService{}
+
+// respondAPIKeyError - Mapping error API key ke HTTP status
+func respondAPIKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnknownAPIKeyScope):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAPIKeyScopeNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTooManyAPIKeys):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// GetAPIKeyScopes godoc
+// @Summary Daftar Scope API Key
+// @Description Scope yang bisa dipilih saat membuat API key beserta permission role yang dibutuhkan
+// @Tags API Keys
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /seller/api-keys/scopes [get]
+func GetAPIKeyScopes(c *gin.Context) {
+	scopes := make([]gin.H, 0, len(models.APIKeyScopes))
+	for _, scope := range models.APIKeyScopes {
+		scopes = append(scopes, gin.H{"name": scope.Name, "permission": scope.Permission, "description": scope.Description})
+	}
+	c.JSON(http.StatusOK, gin.H{"data": scopes})
+}
+
+// GetAPIKeys godoc
+// @Summary Daftar API Key
+// @Description API key milik user (aktif & yang sudah dicabut) tanpa secret: prefix, scope, masa berlaku, terakhir dipakai
+// @Tags API Keys
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} services.APIKeyInfo
+// @Router /seller/api-keys [get]
+func GetAPIKeys(c *gin.Context) {
+	keys, err := apiKeyService.List(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": keys})
+}
+
+// CreateAPIKey godoc
+// @Summary Buat API Key
+// @Description API key untuk integrasi sistem (ERP / POS), dikirim di header X-API-Key. Key hanya ditampilkan sekali di response ini.
+// @Description Scope: catalog:read, catalog:write, orders:read, orders:confirm (harus diizinkan role). Maks 10 key aktif.
+// @Tags API Keys
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body services.CreateAPIKeyInput true "Nama, Scope, Masa Berlaku"
+// @Success 201 {object} services.CreatedAPIKey
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /seller/api-keys [post]
+func CreateAPIKey(c *gin.Context) {
+	var input services.CreateAPIKeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, err := apiKeyService.Create(input, auditContext(c))
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "API key dibuat, simpan key di tempat aman (tidak ditampilkan lagi)", "data": key})
+}
+
+// RevokeAPIKey godoc
+// @Summary Cabut API Key
+// @Description Request berikutnya dengan key ini langsung ditolak (401). Dicatat di audit log.
+// @Tags API Keys
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "API Key ID"
+// @Success 200 {object} services.APIKeyInfo
+// @Failure 404 {object} map[string]string
+// @Router /seller/api-keys/{id} [delete]
+func RevokeAPIKey(c *gin.Context) {
+	key, err := apiKeyService.Revoke(c.Param("id"), auditContext(c))
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "API key dicabut", "data": key})
+}
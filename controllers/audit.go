@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"technical-test-backend/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var auditService = services.AuditService{}
+
+// auditContext - AuditContext request ini (dibuat middleware AuditTrail) dengan pelaku dari access token.
+// Dibuat baru jika middleware tidak terpasang.
+func auditContext(c *gin.Context) *services.AuditContext {
+	audit, ok := c.Value("audit").(*services.AuditContext)
+	if !ok {
+		audit = &services.AuditContext{IP: c.ClientIP(), RequestID: c.GetString("requestID")}
+		c.Set("audit", audit)
+	}
+	audit.SetActor(c.GetString("userID"))
+	return audit
+}
+
+// auditLogFilter - Filter dari query string
+func auditLogFilter(c *gin.Context) services.AuditLogFilter {
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	return services.AuditLogFilter{
+		ActorID:    c.Query("actor_id"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+		RequestID:  c.Query("request_id"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+		Page:       page,
+		Limit:      limit,
+	}
+}
+
+// GetAuditLogs godoc
+// @Summary Audit Log (Admin)
+// @Description Catatan aksi admin, seller, dan order (terbaru dulu): pelaku, aksi, entitas, before/after, IP, request ID.
+// @Description Aksi dengan perubahan lengkap: PRODUCT_UPDATED, USER_UPDATED, ORDER_CONFIRMED, dll. Request lain = API_REQUEST.
+// @Tags Audit Log
+// @Security BearerAuth
+// @Produce json
+// @Param actor_id query string false "User ID pelaku"
+// @Param action query string false "Jenis aksi (misal PRODUCT_UPDATED)"
+// @Param target_type query string false "Jenis entitas (misal product, user, transaction)"
+// @Param target_id query string false "ID entitas"
+// @Param request_id query string false "X-Request-ID"
+// @Param from query string false "Dari (RFC3339 / YYYY-MM-DD)"
+// @Param to query string false "Sampai (RFC3339 / YYYY-MM-DD, inklusif)"
+// @Param page query int false "Halaman (default 1)"
+// @Param limit query int false "Jumlah per halaman (default 50, maks 200)"
+// @Success 200 {object} services.AuditLogPage
+// @Failure 400 {object} map[string]string
+// @Router /audit-logs [get]
+func GetAuditLogs(c *gin.Context) {
+	result, err := auditService.List(auditLogFilter(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// ExportAuditLogs godoc
+// @Summary Export Audit Log ke CSV/XLSX (Admin)
+// @Description Filter sama dengan GET /audit-logs tanpa pagination (maks 50.000 baris, terbaru dulu). Kolom data = JSON.
+// @Tags Audit Log
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param format query string false "csv / xlsx (default: csv)"
+// @Param actor_id query string false "User ID pelaku"
+// @Param action query string false "Jenis aksi"
+// @Param target_type query string false "Jenis entitas"
+// @Param target_id query string false "ID entitas"
+// @Param request_id query string false "X-Request-ID"
+// @Param from query string false "Dari (RFC3339 / YYYY-MM-DD)"
+// @Param to query string false "Sampai (RFC3339 / YYYY-MM-DD, inklusif)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Router /audit-logs/export [get]
+func ExportAuditLogs(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "xlsx" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or xlsx"})
+		return
+	}
+
+	rows, err := auditService.Export(auditLogFilter(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType := "text/csv"
+	if format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	filename := fmt.Sprintf("audit-logs-%s.%s", time.Now().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	if err := services.WriteTableFile(c.Writer, format, "Audit Log", rows); err != nil {
+		c.Error(err)
+	}
+}
@@ -1,16 +1,24 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"technical-test-backend/services"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 var authService = services.AuthService{}
 
+// clientMeta - IP & User-Agent klien untuk dicatat bersama refresh token
+func clientMeta(c *gin.Context) services.ClientMeta {
+	return services.ClientMeta{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
 // @Summary Register User Baru
-// @Description Mendaftarkan pengguna baru (Role ID dikirim sebagai String UUID)
+// @Description Mendaftarkan pengguna baru (Role ID dikirim sebagai String UUID). Link verifikasi dikirim ke email.
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -32,17 +40,22 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Registrasi berhasil!"})
+	c.JSON(http.StatusCreated, gin.H{"message": "Registrasi berhasil! Cek email untuk verifikasi akun."})
 }
 
 // @Summary Login User
-// @Description Masuk menggunakan Email & Password untuk dapat Token JWT
+// @Description Masuk menggunakan Email & Password untuk dapat access token JWT (berumur pendek, ACCESS_TOKEN_TTL)
+// @Description dan refresh token untuk memperpanjang sesi lewat /auth/refresh. Field "token" = access_token (kompatibilitas).
+// @Description Login gagal berulang per akun / IP dikenai jeda eksponensial lalu dikunci sementara (429).
+// @Description Jika 2FA aktif / wajib untuk role, response berisi two_factor_required + challenge_token (lanjut ke /auth/login/2fa).
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param input body services.LoginInput true "Input Data"
 // @Success 200 {object} map[string]interface{}
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string "Email belum diverifikasi (REQUIRE_EMAIL_VERIFICATION=true) / akun ditangguhkan / nonaktif"
+// @Failure 429 {object} map[string]string "Terlalu banyak login gagal (header Retry-After)"
 // @Router /auth/login [post]
 func Login(c *gin.Context) {
 	var input services.LoginInput
@@ -54,22 +67,66 @@ func Login(c *gin.Context) {
 	}
 
 	// Panggil Service
-	result, err := authService.LoginUser(input)
+	result, err := authService.LoginUser(input, clientMeta(c))
+	if respondLoginThrottled(c, err) {
+		return
+	}
+	if errors.Is(err, services.ErrEmailNotVerified) || errors.Is(err, services.ErrAccountSuspended) ||
+		errors.Is(err, services.ErrAccountDeactivated) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		// Jika error (misal password salah), return 401 Unauthorized
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
+	respondLogin(c, result)
+}
+
+// respondLoginThrottled - 429 + header Retry-After jika login sedang di-backoff / dikunci
+func respondLoginThrottled(c *gin.Context, err error) bool {
+	var throttled *services.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+	c.Header("Retry-After", strconv.Itoa(int(throttled.RetryAfter.Seconds())))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	return true
+}
+
+// respondLogin - Response login: challenge 2FA, atau token + data user
+func respondLogin(c *gin.Context, result services.LoginResponse) {
+	if result.Challenge != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"two_factor_required": true,
+			"setup_required":      result.Challenge.SetupRequired,
+			"challenge_token":     result.Challenge.ChallengeToken,
+			"expires_in":          result.Challenge.ExpiresIn,
+		})
+		return
+	}
+
 	// Response Sukses (Token + Data User)
-	c.JSON(http.StatusOK, gin.H{
-		"token": result.Token,
+	response := gin.H{
+		"token":              result.Tokens.AccessToken,
+		"access_token":       result.Tokens.AccessToken,
+		"token_type":         result.Tokens.TokenType,
+		"expires_in":         result.Tokens.ExpiresIn,
+		"refresh_token":      result.Tokens.RefreshToken,
+		"refresh_expires_at": result.Tokens.RefreshExpiresAt,
 		"user": gin.H{
 			"id":   result.User.ID,        // UUID otomatis terkonversi jadi string di JSON
 			"name": result.User.Name,
 			"role": result.User.Role.Name, // Mengambil nama role dari relasi
+			"email_verified": result.User.EmailVerifiedAt != nil,
 		},
-	})
+	}
+	if len(result.RecoveryCodes) > 0 {
+		response["recovery_codes"] = result.RecoveryCodes
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // @Summary Get Available Roles
@@ -88,4 +145,170 @@ func GetRoles(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"roles": roles,
 	})
-}
\ No newline at end of file
+}
+
+// @Summary Refresh Token
+// @Description Tukar refresh token dengan access token + refresh token baru (rotasi). Refresh token lama langsung tidak berlaku.
+// @Description Memakai ulang refresh token yang sudah ditukar mencabut seluruh sesi tersebut (401), user harus login ulang.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body services.RefreshInput true "Refresh Token"
+// @Success 200 {object} services.TokenPair
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string "Akun ditangguhkan / nonaktif"
+// @Router /auth/refresh [post]
+func RefreshToken(c *gin.Context) {
+	var input services.RefreshInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := authService.RefreshTokens(input, clientMeta(c))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenReuse) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrAccountSuspended) || errors.Is(err, services.ErrAccountDeactivated) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary Logout
+// @Description Mencabut access token yang dipakai (jti denylist) dan sesi refresh token yang dikirim.
+// @Description all_devices = true mencabut semua refresh token user.
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body services.LogoutInput false "Refresh Token (opsional)"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/logout [post]
+func Logout(c *gin.Context) {
+	var input services.LogoutInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	expiresAt, _ := c.Get("tokenExpiresAt")
+	accessExpiresAt, _ := expiresAt.(time.Time)
+	if err := authService.Logout(c.GetString("userID"), c.GetString("jti"), accessExpiresAt, input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logout berhasil"})
+}
+
+// @Summary Forgot Password
+// @Description Kirim link reset password ke email (berlaku PASSWORD_RESET_TTL, sekali pakai).
+// @Description Response selalu sama walaupun email tidak terdaftar.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body services.ForgotPasswordInput true "Email"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/forgot-password [post]
+func ForgotPassword(c *gin.Context) {
+	var input services.ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := authService.RequestPasswordReset(input); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses permintaan reset password"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Jika email terdaftar, link reset password sudah dikirim"})
+}
+
+// @Summary Reset Password
+// @Description Set password baru dengan token dari email reset password. Semua sesi (refresh token) user dicabut.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body services.ResetPasswordInput true "Token & Password Baru"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/reset-password [post]
+func ResetPassword(c *gin.Context) {
+	var input services.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := authService.ResetPassword(input); err != nil {
+		if errors.Is(err, services.ErrInvalidUserToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Password berhasil direset, silakan login ulang"})
+}
+
+// @Summary Verify Email
+// @Description Konfirmasi email dengan token dari email verifikasi (registrasi atau ganti email di profil).
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body services.VerifyEmailInput true "Token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /auth/verify-email [post]
+func VerifyEmail(c *gin.Context) {
+	var input services.VerifyEmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := authService.VerifyEmail(input); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidUserToken):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrEmailTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Email berhasil diverifikasi"})
+}
+
+// @Summary Resend Verification Email
+// @Description Kirim ulang link verifikasi ke email baru yang menunggu konfirmasi, atau ke email akun yang belum diverifikasi.
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /auth/resend-verification [post]
+func ResendVerification(c *gin.Context) {
+	if err := authService.ResendVerification(c.GetString("userID")); err != nil {
+		if errors.Is(err, services.ErrEmailAlreadyVerified) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengirim email verifikasi"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Link verifikasi sudah dikirim"})
+}
@@ -1,24 +1,29 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"technical-test-backend/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 var catService = services.CatalogService{}
 
 // AddToEtalase godoc
 // @Summary (Seller) Pajang Barang & Markup Harga
-// @Description Seller memilih barang dari gudang admin dan menentukan harga jual sendiri
+// @Description Seller memilih barang dari gudang admin dan menentukan harga jual sendiri.
+// @Description Jika produk sudah pernah dipajang, etalase yang sama diaktifkan kembali / harganya diperbarui (200, action REACTIVATED / UPDATED).
 // @Tags Seller Catalog
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param input body services.AddToEtalaseInput true "Data Markup"
 // @Success 201 {object} map[string]interface{}
+// @Success 200 {object} map[string]interface{}
 // @Router /seller/products [post]
 func AddToEtalase(c *gin.Context) {
 	var input services.AddToEtalaseInput
@@ -31,14 +36,18 @@ func AddToEtalase(c *gin.Context) {
 	// Ambil UserID dari Middleware
 	userID := c.GetString("userID")
 	
-	res, err := catService.AddToEtalase(userID, input)
+	res, action, err := catService.AddToEtalase(userID, input)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	
-	// Gunakan http.StatusCreated alih-alih 201
-	c.JSON(http.StatusCreated, gin.H{"data": res})
+	// Gunakan http.StatusCreated alih-alih 201, etalase lama yang di-upsert = 200
+	status := http.StatusCreated
+	if action != services.EtalaseCreated {
+		status = http.StatusOK
+	}
+	c.JSON(status, gin.H{"data": res, "action": action})
 }
 
 // GetMarketplace godoc
@@ -50,6 +59,8 @@ func AddToEtalase(c *gin.Context) {
 // @Param category query string false "Category/Product Type ID"
 // @Param min_price query number false "Minimum price"
 // @Param max_price query number false "Maximum price"
+// @Param group_by query string false "product = satu baris per produk master dengan buy box & rentang harga"
+// @Param include_out_of_stock query bool false "true = tampilkan juga produk stok habis (in_stock = false)"
 // @Success 200 {object} map[string]interface{}
 // @Router /marketplace [get]
 func GetMarketplace(c *gin.Context) {
@@ -58,6 +69,7 @@ func GetMarketplace(c *gin.Context) {
 	categoryID := c.Query("category")
 	minPrice := c.DefaultQuery("min_price", "0")
 	maxPrice := c.DefaultQuery("max_price", "0")
+	includeOutOfStock := c.Query("include_out_of_stock") == "true"
 	
 	// Convert price strings to float64
 	var minPriceFloat, maxPriceFloat float64
@@ -72,7 +84,18 @@ func GetMarketplace(c *gin.Context) {
 		}
 	}
 	
-	items, err := catService.GetMarketplaceItems(search, categoryID, minPriceFloat, maxPriceFloat)
+	// Mode grouped: penawaran beberapa seller untuk produk yang sama digabung
+	if c.Query("group_by") == "product" {
+		groups, err := catService.GetMarketplaceGroups(search, categoryID, minPriceFloat, maxPriceFloat, includeOutOfStock)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": groups})
+		return
+	}
+
+	items, err := catService.GetMarketplaceItems(search, categoryID, minPriceFloat, maxPriceFloat, includeOutOfStock)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -80,11 +103,36 @@ func GetMarketplace(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": items})
 }
 
+// GetProductOffers godoc
+// @Summary (Pembeli) Bandingkan Penawaran Seller
+// @Description Semua penawaran seller aktif untuk satu produk master, diurutkan dari harga efektif termurah
+// @Description lalu rating seller, lalu jumlah order COMPLETED seller. Penawaran pertama adalah buy box (is_buy_box = true).
+// @Tags Marketplace
+// @Security BearerAuth
+// @Produce json
+// @Param productId path string true "Product ID (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /marketplace/products/{productId}/offers [get]
+func GetProductOffers(c *gin.Context) {
+	offers, err := catService.GetProductOffers(c.Param("productId"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": offers})
+}
+
 // GetSellerProducts godoc
 // @Summary (Seller) Lihat Daftar Produk Sendiri
 // @Description Melihat daftar produk yang dijual oleh seller yang sedang login
 // @Tags Seller Catalog
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Success 200 {object} map[string]interface{}
 // @Router /seller/products [get]
 func GetSellerProducts(c *gin.Context) {
@@ -104,6 +152,7 @@ func GetSellerProducts(c *gin.Context) {
 // @Description Seller dapat memperbarui harga jual atau status aktif produk mereka
 // @Tags Seller Catalog
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Seller Product ID (UUID)"
@@ -121,7 +170,7 @@ func UpdateSellerProduct(c *gin.Context) {
 	sellerID := c.GetString("userID")
 	sellerProductID := c.Param("id")
 	
-	product, err := catService.UpdateSellerProduct(sellerProductID, sellerID, input)
+	product, err := catService.UpdateSellerProduct(sellerProductID, sellerID, input, auditContext(c))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -135,6 +184,7 @@ func UpdateSellerProduct(c *gin.Context) {
 // @Description Seller menonaktifkan produk mereka dari marketplace (soft delete)
 // @Tags Seller Catalog
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path string true "Seller Product ID (UUID)"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
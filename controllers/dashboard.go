@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"technical-test-backend/models"
 	"technical-test-backend/services"
 	"github.com/gin-gonic/gin"
 )
@@ -10,7 +11,8 @@ var dashService = services.DashboardService{}
 
 // GetDashboard godoc
 // @Summary Dashboard Statistik (Multi-Role)
-// @Description Menampilkan statistik keuangan dan transaksi berdasarkan Role User yang login
+// @Description Menampilkan statistik keuangan dan transaksi berdasarkan permission role user yang login
+// @Description (dashboard.admin -> platform, dashboard.seller -> penjualan, dashboard.customer -> belanja)
 // @Tags Dashboard
 // @Security BearerAuth
 // @Success 200 {object} map[string]interface{}
@@ -18,15 +20,17 @@ var dashService = services.DashboardService{}
 func GetDashboard(c *gin.Context) {
 	role := c.GetString("role")
 	uid := c.GetString("userID")
+	permissions := c.GetStringSlice("permissions")
 
-	switch role {
-	case "Pelanggan":
-		c.JSON(200, gin.H{"role": role, "data": dashService.GetBuyerStats(uid)})
-	case "Seller":
-		c.JSON(200, gin.H{"role": role, "data": dashService.GetSellerStats(uid)})
-	case "Admin":
+	// Role dengan beberapa permission dashboard mendapat dashboard paling luas
+	switch {
+	case services.HasPermission(permissions, models.PermDashboardAdmin):
 		c.JSON(200, gin.H{"role": role, "data": dashService.GetAdminStats()})
+	case services.HasPermission(permissions, models.PermDashboardSeller):
+		c.JSON(200, gin.H{"role": role, "data": dashService.GetSellerStats(uid)})
+	case services.HasPermission(permissions, models.PermDashboardCustomer):
+		c.JSON(200, gin.H{"role": role, "data": dashService.GetBuyerStats(uid)})
 	default:
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unknown role"})
+		c.JSON(http.StatusForbidden, gin.H{"error": "Role tidak memiliki akses dashboard"})
 	}
 }
\ No newline at end of file
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var flashSaleService = services.FlashSaleService{}
+
+// respondFlashSaleError - 404 jika tidak ditemukan, 409 untuk bentrok / status tidak sesuai
+func respondFlashSaleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Flash sale not found"})
+	case errors.Is(err, services.ErrFlashSaleConflict), errors.Is(err, services.ErrInvalidFlashSaleStatus):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// GetRunningFlashSales godoc
+// @Summary Flash Sale yang Sedang Berjalan
+// @Description Daftar flash sale aktif beserta harga promo, sisa kuota, dan countdown (ends_in_seconds)
+// @Tags Flash Sale
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /flash-sales/active [get]
+func GetRunningFlashSales(c *gin.Context) {
+	sales, err := flashSaleService.GetRunning()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": sales})
+}
+
+// GetFlashSales godoc
+// @Summary Lihat Daftar Flash Sale (Admin)
+// @Tags Flash Sale
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "SCHEDULED / RUNNING / ENDED"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /flash-sales [get]
+func GetFlashSales(c *gin.Context) {
+	sales, err := flashSaleService.GetAll(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": sales})
+}
+
+// GetFlashSaleByID godoc
+// @Summary Detail Flash Sale (Admin)
+// @Tags Flash Sale
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Flash Sale ID (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /flash-sales/{id} [get]
+func GetFlashSaleByID(c *gin.Context) {
+	sale, err := flashSaleService.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Flash sale not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": sale})
+}
+
+// CreateFlashSale godoc
+// @Summary Buat Flash Sale (Admin)
+// @Description Tiap item berisi etalase seller, salah satu promo_price / discount_percent, dan kuota stok.
+// @Description Harga promo tidak boleh di bawah harga modal. Etalase yang sudah ikut flash sale lain di jendela waktu yang bentrok ditolak (409).
+// @Tags Flash Sale
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body services.FlashSaleInput true "Data Flash Sale"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /flash-sales [post]
+func CreateFlashSale(c *gin.Context) {
+	var input services.FlashSaleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sale, err := flashSaleService.Create(c.GetString("userID"), input)
+	if err != nil {
+		respondFlashSaleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": sale})
+}
+
+// UpdateFlashSale godoc
+// @Summary Update Flash Sale (Admin)
+// @Description Hanya flash sale yang belum dimulai (SCHEDULED), item diganti seluruhnya
+// @Tags Flash Sale
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Flash Sale ID (UUID)"
+// @Param input body services.FlashSaleInput true "Data Flash Sale"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /flash-sales/{id} [put]
+func UpdateFlashSale(c *gin.Context) {
+	var input services.FlashSaleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sale, err := flashSaleService.Update(c.Param("id"), input)
+	if err != nil {
+		respondFlashSaleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": sale})
+}
+
+// DeleteFlashSale godoc
+// @Summary Hapus Flash Sale (Admin)
+// @Description Hanya flash sale yang belum dimulai. Flash sale yang sedang berjalan dihentikan dengan /stop.
+// @Tags Flash Sale
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Flash Sale ID (UUID)"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /flash-sales/{id} [delete]
+func DeleteFlashSale(c *gin.Context) {
+	if err := flashSaleService.Delete(c.Param("id")); err != nil {
+		respondFlashSaleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Flash sale deleted"})
+}
+
+// StopFlashSale godoc
+// @Summary Hentikan Flash Sale (Admin)
+// @Description Flash sale yang sedang berjalan langsung berakhir, order yang sudah dibuat tetap memakai harga promo
+// @Tags Flash Sale
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Flash Sale ID (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /flash-sales/{id}/stop [post]
+func StopFlashSale(c *gin.Context) {
+	sale, err := flashSaleService.Stop(c.Param("id"))
+	if err != nil {
+		respondFlashSaleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": sale})
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetJWKS godoc
+// @Summary JSON Web Key Set
+// @Description Public key untuk verifikasi access token (RS256 / EdDSA) oleh service lain tanpa secret.
+// @Description Pilih key berdasarkan header "kid" JWT, ambil ulang JWKS jika kid belum dikenal (kunci baru setelah rotasi).
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} utils.JWKSet
+// @Router /.well-known/jwks.json [get]
+func GetJWKS(c *gin.Context) {
+	set, err := authService.JWKS()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, set)
+}
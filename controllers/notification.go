@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var notificationService = services.NotificationService{}
+
+// GetNotifications godoc
+// @Summary Lihat Notifikasi Saya
+// @Description Notifikasi in-app user yang login (terbaru dulu), contoh: perubahan harga modal untuk seller
+// @Tags Notification
+// @Security BearerAuth
+// @Produce json
+// @Param unread query bool false "Hanya yang belum dibaca"
+// @Param limit query int false "Jumlah maksimal (default 50)"
+// @Success 200 {object} map[string]interface{}
+// @Router /notifications [get]
+func GetNotifications(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	notifications, unread, err := notificationService.GetMyNotifications(c.GetString("userID"), c.Query("unread") == "true", limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"unread_count": unread, "data": notifications})
+}
+
+// MarkNotificationRead godoc
+// @Summary Tandai Notifikasi Sudah Dibaca
+// @Tags Notification
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Notification ID (UUID)"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /notifications/{id}/read [post]
+func MarkNotificationRead(c *gin.Context) {
+	if err := notificationService.MarkAsRead(c.GetString("userID"), c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Tandai Semua Notifikasi Sudah Dibaca
+// @Tags Notification
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /notifications/read-all [post]
+func MarkAllNotificationsRead(c *gin.Context) {
+	updated, err := notificationService.MarkAllAsRead(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "All notifications marked as read", "updated": updated})
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var oidcService = services.OIDCService{}
+
+// respondOIDCError - Mapping error login OIDC ke HTTP status
+func respondOIDCError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrOIDCProviderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOIDCInvalidState):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOIDCAuthorizationFailed), errors.Is(err, services.ErrOIDCInvalidIDToken):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOIDCEmailNotVerified),
+		errors.Is(err, services.ErrOIDCNoRole),
+		errors.Is(err, services.ErrOIDCSignupDisabled),
+		errors.Is(err, services.ErrAccountSuspended),
+		errors.Is(err, services.ErrAccountDeactivated):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOIDCIdentityConflict), errors.Is(err, services.ErrLastRoleManager):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOIDCProviderError):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GetOIDCProviders godoc
+// @Summary Daftar Identity Provider (OIDC)
+// @Description Provider dari OIDC_PROVIDERS yang bisa dipakai login (untuk tombol "Login dengan ..." di frontend)
+// @Tags Auth
+// @Produce json
+// @Success 200 {array} services.OIDCProviderInfo
+// @Router /auth/oidc/providers [get]
+func GetOIDCProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": oidcService.Providers()})
+}
+
+// StartOIDCLogin godoc
+// @Summary Login dengan Identity Provider (OIDC)
+// @Description Redirect (302) ke halaman login IdP (authorization code + PKCE S256, state & nonce sekali pakai).
+// @Description redirect=false mengembalikan authorization_url dalam JSON (untuk SPA yang mengarahkan browser sendiri).
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "Nama provider (OIDC_PROVIDERS)"
+// @Param redirect query bool false "false = kembalikan authorization_url (default true = 302)"
+// @Success 302
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /auth/oidc/{provider}/login [get]
+func StartOIDCLogin(c *gin.Context) {
+	authorizationURL, err := oidcService.AuthorizationURL(c.Param("provider"))
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+	if c.Query("redirect") == "false" {
+		c.JSON(http.StatusOK, gin.H{"authorization_url": authorizationURL})
+		return
+	}
+	c.Redirect(http.StatusFound, authorizationURL)
+}
+
+// OIDCCallback godoc
+// @Summary Callback Login OIDC
+// @Description Redirect URI yang didaftarkan di IdP (atau dipanggil frontend dengan code & state dari IdP).
+// @Description Akun dicari dari identitas IdP, lalu email terverifikasi (akun lama dihubungkan), atau dibuat baru (ALLOW_SIGNUP).
+// @Description Role diselaraskan dengan grup IdP (ROLE_MAPPING). Response sama dengan /auth/login (termasuk challenge 2FA lokal).
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "Nama provider"
+// @Param code query string false "Authorization code"
+// @Param state query string true "State dari /auth/oidc/{provider}/login"
+// @Param error query string false "Error dari IdP"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string "State tidak valid / kadaluarsa / sudah dipakai"
+// @Failure 401 {object} map[string]string "Ditolak IdP / ID token tidak valid"
+// @Failure 403 {object} map[string]string "Email belum terverifikasi di IdP / tidak ada role untuk grup / signup dimatikan / akun nonaktif"
+// @Failure 409 {object} map[string]string "Akun sudah terhubung ke identitas lain di provider ini / email akun lokal belum diverifikasi"
+// @Failure 502 {object} map[string]string "IdP tidak bisa dihubungi / response tidak valid"
+// @Router /auth/oidc/{provider}/callback [get]
+func OIDCCallback(c *gin.Context) {
+	var input services.OIDCCallbackInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := oidcService.Callback(c.Param("provider"), input, clientMeta(c), auditContext(c))
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+	respondLogin(c, result)
+}
@@ -1,10 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"strconv"
 	"technical-test-backend/models"
 	"technical-test-backend/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 var prodService = services.ProductService{}
@@ -25,8 +27,13 @@ func CreateProduct(c *gin.Context) {
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(400, gin.H{"error": err.Error()}); return
 	}
-	res, err := prodService.Create(input)
-	if err != nil { c.JSON(400, gin.H{"error": err.Error()}); return }
+	res, err := prodService.Create(input, c.GetString("userID"))
+	if err != nil {
+		if errors.Is(err, services.ErrDuplicateSKU) || errors.Is(err, services.ErrDuplicateBarcode) {
+			c.JSON(409, gin.H{"error": err.Error()}); return
+		}
+		c.JSON(400, gin.H{"error": err.Error()}); return
+	}
 	c.JSON(201, gin.H{"data": res})
 }
 
@@ -35,7 +42,8 @@ func CreateProduct(c *gin.Context) {
 // @Description Melihat semua master produk (Admin & Seller bisa lihat)
 // @Tags Product Master (Gudang)
 // @Security BearerAuth
-// @Param search query string false "Search product name"
+// @Security ApiKeyAuth
+// @Param search query string false "Search product name or SKU"
 // @Param product_type_id query string false "Product Type ID filter"
 // @Success 200 {object} map[string]interface{}
 // @Router /products [get]
@@ -62,20 +70,33 @@ func FindAllProducts(c *gin.Context) {
 
 // DeleteProduct godoc
 // @Summary Hapus Barang Gudang (Admin)
+// @Description Soft delete produk master. Ditolak (409) jika masih dipajang aktif oleh seller atau ada transaksi PENDING.
 // @Tags Product Master (Gudang)
 // @Security BearerAuth
 // @Param id path string true "Product ID"
 // @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /products/{id} [delete]
 func DeleteProduct(c *gin.Context) {
-	if err := prodService.Delete(c.Param("id")); err != nil {
-		c.JSON(400, gin.H{"error": "Failed delete"}); return
+	if err := prodService.Delete(c.Param("id"), auditContext(c)); err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductInUse):
+			c.JSON(409, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(404, gin.H{"error": "Product not found"})
+		default:
+			c.JSON(400, gin.H{"error": "Failed delete"})
+		}
+		return
 	}
 	c.JSON(200, gin.H{"message": "Deleted"})
 }
 // UpdateProduct godoc
 // @Summary Update Barang Gudang (Admin)
-// @Description Admin dapat memperbarui data produk master (nama, stock, harga, kategori)
+// @Description Admin dapat memperbarui data produk master (nama, stock, harga, kategori). Perubahan stock dicatat sebagai movement ADJUSTMENT.
+// @Description stock tanpa warehouse_id = total stok produk (selisih dibukukan di gudang default, 400 jika pengurangan melebihi stok gudang default), stock dengan warehouse_id = stok baru di gudang tersebut.
+// @Description Jika harga modal naik di atas harga jual etalase seller, etalase ditangani sesuai price_policy (DEACTIVATE, REPRICE, NOTIFY) dan seller mendapat notifikasi.
 // @Tags Product Master (Gudang)
 // @Security BearerAuth
 // @Accept json
@@ -92,39 +113,50 @@ func UpdateProduct(c *gin.Context) {
 		return
 	}
 	
-	product, err := prodService.Update(c.Param("id"), input)
+	product, priceChange, err := prodService.Update(c.Param("id"), c.GetString("userID"), input, auditContext(c))
 	if err != nil {
+		if errors.Is(err, services.ErrDuplicateSKU) || errors.Is(err, services.ErrDuplicateBarcode) {
+			c.JSON(409, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 	
-	c.JSON(200, gin.H{"data": product})
+	response := gin.H{"data": product}
+	if priceChange != nil {
+		response["price_change"] = priceChange
+	}
+	c.JSON(200, response)
 }
 
 // GetLowStock godoc
 // @Summary Get Low Stock Products (Admin)
-// @Description Get products with stock below threshold
+// @Description Get products with stock below threshold, lengkap dengan rincian stok per gudang
 // @Tags Product Master (Gudang)
 // @Security BearerAuth
-// @Param threshold query int false "Stock threshold (default: 10)"
+// @Param threshold query int false "Stock threshold. Jika kosong, pakai reorder point per produk (default 10 jika belum dihitung)"
+// @Param warehouse_id query string false "Bandingkan threshold dengan stok di gudang ini"
 // @Success 200 {object} map[string]interface{}
 // @Router /products/low-stock [get]
 func GetLowStock(c *gin.Context) {
-	threshold := 10 // default
+	var threshold *int
+	var thresholdLabel interface{} = "reorder_point"
 	if t := c.Query("threshold"); t != "" {
 		if val, err := strconv.Atoi(t); err == nil {
-			threshold = val
+			threshold = &val
+			thresholdLabel = val
 		}
 	}
 	
-	products, err := prodService.GetLowStock(threshold)
+	products, err := prodService.GetLowStock(threshold, c.Query("warehouse_id"))
 	if err != nil {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 	
 	c.JSON(200, gin.H{
-		"threshold": threshold,
+		"threshold": thresholdLabel,
 		"count":     len(products),
 		"data":      products,
 	})
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetProductByCode godoc
+// @Summary Cari Produk dari SKU / Barcode
+// @Description Lookup hasil scan. Cocokkan barcode (EAN-8/UPC-A/EAN-13, UPC-A juga cocok dengan EAN-13 berawalan 0) lalu SKU (case-insensitive). Termasuk stok per gudang.
+// @Tags Product Master (Gudang)
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param code path string true "SKU atau barcode"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /products/by-code/{code} [get]
+func GetProductByCode(c *gin.Context) {
+	product, err := prodService.GetByCode(c.Param("code"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": product})
+}
+
+// GetProductLabel godoc
+// @Summary Label Barcode Produk (Admin)
+// @Description Generate gambar label barcode untuk dicetak. code128 berisi SKU, ean13 berisi barcode produk.
+// @Tags Product Master (Gudang)
+// @Security BearerAuth
+// @Produce png
+// @Produce image/svg+xml
+// @Param id path string true "Product ID (UUID)"
+// @Param format query string false "png / svg (default: png)"
+// @Param symbology query string false "code128 / ean13 (default: ean13 jika produk punya barcode EAN-13/UPC-A, selain itu code128)"
+// @Param scale query int false "Lebar 1 modul dalam pixel, 1-10 (default: 2)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /products/{id}/label [get]
+func GetProductLabel(c *gin.Context) {
+	scale, _ := strconv.Atoi(c.Query("scale"))
+
+	label, err := prodService.GenerateLabel(c.Param("id"), c.Query("symbology"), c.DefaultQuery("format", "png"), scale)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", label.Filename))
+	c.Data(http.StatusOK, label.ContentType, label.Data)
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"technical-test-backend/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize - Batas ukuran file import (10 MB)
+const maxImportFileSize = 10 << 20
+
+// ImportProducts godoc
+// @Summary Import Produk dari CSV/XLSX (Admin)
+// @Description Kolom: sku, name, product_type, price, stock, warehouse_code. dry_run=true hanya memvalidasi dan mengembalikan error per baris. Tanpa dry_run, semua baris disimpan dalam satu transaksi (create atau upsert by SKU); jika ada satu baris invalid tidak ada yang disimpan (422).
+// @Tags Product Master (Gudang)
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "File CSV atau XLSX"
+// @Param dry_run query bool false "Validasi saja tanpa menyimpan"
+// @Param format query string false "csv / xlsx (default: dari ekstensi file)"
+// @Param price_policy query string false "DEACTIVATE / REPRICE / NOTIFY untuk etalase seller jika harga modal naik (default: MASTER_PRICE_POLICY)"
+// @Success 200 {object} services.ImportReport
+// @Failure 400 {object} map[string]string
+// @Failure 422 {object} services.ImportReport
+// @Router /products/import [post]
+func ImportProducts(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is too large (max 10 MB)"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows, err := services.ParseProductFile(fileHeader.Filename, c.Query("format"), data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+	report, err := prodService.ImportProducts(rows, dryRun, c.GetString("userID"), c.Query("price_policy"))
+	if err != nil {
+		if errors.Is(err, services.ErrImportInvalid) {
+			if dryRun {
+				c.JSON(http.StatusOK, report)
+				return
+			}
+			c.JSON(http.StatusUnprocessableEntity, report)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportProducts godoc
+// @Summary Export Produk ke CSV/XLSX (Admin)
+// @Description Format sama dengan import. Kolom stock = stok di gudang warehouse_code (default: gudang default).
+// @Tags Product Master (Gudang)
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param format query string false "csv / xlsx (default: csv)"
+// @Param warehouse_code query string false "Kode gudang untuk kolom stock"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Router /products/export [get]
+func ExportProducts(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "xlsx" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or xlsx"})
+		return
+	}
+
+	rows, err := prodService.ExportProducts(c.Query("warehouse_code"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType := "text/csv"
+	if format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	filename := fmt.Sprintf("products-%s.%s", time.Now().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	if err := services.WriteProductFile(c.Writer, format, rows); err != nil {
+		c.Error(err)
+	}
+}
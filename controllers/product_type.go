@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"errors"
 	"technical-test-backend/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 var typeService = services.ProductTypeService{}
@@ -56,7 +58,7 @@ func UpdateType(c *gin.Context) {
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(400, gin.H{"error": err.Error()}); return
 	}
-	res, err := typeService.Update(id, input.Name)
+	res, err := typeService.Update(id, input.Name, auditContext(c))
 	if err != nil {
 		c.JSON(404, gin.H{"error": "Product type not found"}); return
 	}
@@ -65,15 +67,49 @@ func UpdateType(c *gin.Context) {
 
 // DeleteType godoc
 // @Summary Hapus Kategori (Admin)
+// @Description Soft delete kategori. Ditolak (409) jika masih ada produk yang memakai kategori ini.
 // @Tags Product Type
 // @Security BearerAuth
 // @Param id path string true "Product Type ID"
 // @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /product-types/{id} [delete]
 func DeleteType(c *gin.Context) {
 	id := c.Param("id")
-	if err := typeService.Delete(id); err != nil {
+	if err := typeService.Delete(id, auditContext(c)); err != nil {
+		if errors.Is(err, services.ErrProductTypeInUse) {
+			c.JSON(409, gin.H{"error": err.Error()}); return
+		}
 		c.JSON(404, gin.H{"error": "Product type not found"}); return
 	}
 	c.JSON(200, gin.H{"message": "Product type deleted"})
+}
+
+// MergeType godoc
+// @Summary Merge / Reassign Kategori (Admin)
+// @Description Memindahkan semua produk dari kategori ini ke kategori target, opsional sekaligus menghapus kategori asal
+// @Tags Product Type
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Source Product Type ID"
+// @Param input body services.MergeProductTypeInput true "Kategori Target"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /product-types/{id}/merge [post]
+func MergeType(c *gin.Context) {
+	var input services.MergeProductTypeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()}); return
+	}
+	res, err := typeService.Merge(c.Param("id"), input, auditContext(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(404, gin.H{"error": "Product type not found"}); return
+		}
+		c.JSON(400, gin.H{"error": err.Error()}); return
+	}
+	c.JSON(200, gin.H{"data": res})
 }
\ No newline at end of file
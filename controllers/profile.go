@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"errors"
 	"technical-test-backend/database"
 	"technical-test-backend/models"
+	"technical-test-backend/services"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
@@ -29,21 +31,34 @@ func GetProfile(c *gin.Context) {
 			"name":  user.Name,
 			"email": user.Email,
 			"role":  user.Role.Name,
+			"address":   user.Address,
+			"city":      user.City,
+			"latitude":  user.Latitude,
+			"longitude": user.Longitude,
+			"email_verified_at": user.EmailVerifiedAt,
+			"pending_email":     user.PendingEmail,
 		},
 	})
 }
 
 type UpdateProfileInput struct {
 	Name  string `json:"name"`
-	Email string `json:"email"`
+	Email string `json:"email" binding:"omitempty,email"` // Email baru berlaku setelah diverifikasi lewat link di email
+	// Alamat pengiriman (dipakai untuk memilih gudang terdekat)
+	Address   string   `json:"address"`
+	City      string   `json:"city"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
 }
 
 // UpdateProfile godoc
 // @Summary Update User Profile
 // @Tags Profile
 // @Security BearerAuth
+// @Description Email baru tidak langsung dipakai: disimpan sebagai pending_email dan link verifikasi dikirim ke alamat baru.
 // @Param input body UpdateProfileInput true "Profile Data"
 // @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string "Email sudah dipakai"
 // @Router /profile [put]
 func UpdateProfile(c *gin.Context) {
 	userID := c.MustGet("userID").(string)
@@ -65,21 +80,52 @@ func UpdateProfile(c *gin.Context) {
 	if input.Name != "" {
 		updates["name"] = input.Name
 	}
-	if input.Email != "" {
-		updates["email"] = input.Email
+	if input.Address != "" {
+		updates["address"] = input.Address
+	}
+	if input.City != "" {
+		updates["city"] = input.City
+	}
+	if input.Latitude != nil && input.Longitude != nil {
+		updates["latitude"] = *input.Latitude
+		updates["longitude"] = *input.Longitude
 	}
 	
 	if err := database.DB.Model(&user).Updates(updates).Error; err != nil {
 		c.JSON(400, gin.H{"error": "Failed to update profile"})
 		return
 	}
+
+	// Ganti email: simpan sebagai pending_email dan kirim link verifikasi ke alamat baru.
+	// Mengirim email yang sama dengan email sekarang membatalkan penggantian yang tertunda.
+	message := "Profile updated successfully"
+	if input.Email != "" {
+		if err := authService.RequestEmailChange(user, input.Email); err != nil {
+			if errors.Is(err, services.ErrEmailTaken) {
+				c.JSON(409, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(500, gin.H{"error": "Failed to send verification email"})
+			return
+		}
+		user.PendingEmail = ""
+		if input.Email != user.Email {
+			user.PendingEmail = input.Email
+			message = "Profile updated, check your new email address to confirm the change"
+		}
+	}
 	
 	c.JSON(200, gin.H{
-		"message": "Profile updated successfully",
+		"message": message,
 		"data": gin.H{
 			"id":    user.ID,
 			"name":  user.Name,
 			"email": user.Email,
+			"address":   user.Address,
+			"city":      user.City,
+			"latitude":  user.Latitude,
+			"longitude": user.Longitude,
+			"pending_email": user.PendingEmail,
 		},
 	})
 }
@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var purchaseOrderService = services.PurchaseOrderService{}
+
+// respondPurchaseOrderError - Mapping error PO ke HTTP status
+func respondPurchaseOrderError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidPOStatus):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase order not found"})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// GetPurchaseOrders godoc
+// @Summary Lihat Daftar Purchase Order (Admin)
+// @Tags Purchase Order
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filter status (DRAFT, SENT, PARTIALLY_RECEIVED, RECEIVED, CLOSED)"
+// @Param supplier_id query string false "Filter supplier"
+// @Success 200 {object} map[string]interface{}
+// @Router /purchase-orders [get]
+func GetPurchaseOrders(c *gin.Context) {
+	orders, err := purchaseOrderService.GetAll(c.Query("status"), c.Query("supplier_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": orders})
+}
+
+// GetPurchaseOrderByID godoc
+// @Summary Detail Purchase Order (Admin)
+// @Tags Purchase Order
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Purchase Order ID (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /purchase-orders/{id} [get]
+func GetPurchaseOrderByID(c *gin.Context) {
+	order, err := purchaseOrderService.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase order not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": order})
+}
+
+// CreatePurchaseOrder godoc
+// @Summary Buat Purchase Order (Admin)
+// @Description Membuat PO berstatus DRAFT. warehouse_id kosong = gudang default.
+// @Tags Purchase Order
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body services.PurchaseOrderInput true "Data PO"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /purchase-orders [post]
+func CreatePurchaseOrder(c *gin.Context) {
+	var input services.PurchaseOrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := purchaseOrderService.Create(c.GetString("userID"), input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": order})
+}
+
+// UpdatePurchaseOrder godoc
+// @Summary Update Purchase Order (Admin)
+// @Description Hanya untuk PO berstatus DRAFT. Seluruh baris diganti dengan lines yang dikirim.
+// @Tags Purchase Order
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Purchase Order ID (UUID)"
+// @Param input body services.PurchaseOrderInput true "Data PO"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /purchase-orders/{id} [put]
+func UpdatePurchaseOrder(c *gin.Context) {
+	var input services.PurchaseOrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := purchaseOrderService.Update(c.Param("id"), input)
+	if err != nil {
+		respondPurchaseOrderError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": order})
+}
+
+// DeletePurchaseOrder godoc
+// @Summary Hapus Purchase Order (Admin)
+// @Description Hanya PO berstatus DRAFT yang bisa dihapus
+// @Tags Purchase Order
+// @Security BearerAuth
+// @Param id path string true "Purchase Order ID (UUID)"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /purchase-orders/{id} [delete]
+func DeletePurchaseOrder(c *gin.Context) {
+	if err := purchaseOrderService.Delete(c.Param("id")); err != nil {
+		respondPurchaseOrderError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Purchase order deleted"})
+}
+
+// SendPurchaseOrder godoc
+// @Summary Kirim Purchase Order ke Supplier (Admin)
+// @Description DRAFT -> SENT
+// @Tags Purchase Order
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Purchase Order ID (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /purchase-orders/{id}/send [post]
+func SendPurchaseOrder(c *gin.Context) {
+	order, err := purchaseOrderService.Send(c.Param("id"))
+	if err != nil {
+		respondPurchaseOrderError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": order})
+}
+
+// ReceivePurchaseOrder godoc
+// @Summary Terima Barang dari Purchase Order (Admin)
+// @Description Menambah stok di gudang tujuan PO dan mengupdate harga modal produk dengan rata-rata tertimbang. Boleh diterima sebagian.
+// @Tags Purchase Order
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Purchase Order ID (UUID)"
+// @Param input body services.ReceivePurchaseOrderInput true "Barang yang diterima"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /purchase-orders/{id}/receive [post]
+func ReceivePurchaseOrder(c *gin.Context) {
+	var input services.ReceivePurchaseOrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := purchaseOrderService.Receive(c.Param("id"), c.GetString("userID"), input)
+	if err != nil {
+		respondPurchaseOrderError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": order})
+}
+
+// ClosePurchaseOrder godoc
+// @Summary Tutup Purchase Order (Admin)
+// @Description Menutup PO. Sisa barang yang belum diterima tidak lagi ditunggu.
+// @Tags Purchase Order
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Purchase Order ID (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /purchase-orders/{id}/close [post]
+func ClosePurchaseOrder(c *gin.Context) {
+	order, err := purchaseOrderService.Close(c.Param("id"))
+	if err != nil {
+		respondPurchaseOrderError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": order})
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var reorderService = services.ReorderService{}
+
+// GetReorderSuggestions godoc
+// @Summary Rekomendasi Reorder (Admin)
+// @Description Reorder point, safety stock, dan jumlah yang disarankan untuk dipesan berdasarkan penjualan COMPLETED N hari terakhir dan lead time supplier
+// @Tags Reports
+// @Security BearerAuth
+// @Produce json
+// @Param lookback_days query int false "Jumlah hari histori penjualan (default: REORDER_LOOKBACK_DAYS / 30)"
+// @Param all query bool false "Tampilkan semua produk, bukan hanya yang perlu reorder"
+// @Success 200 {object} services.ReorderReport
+// @Router /reports/reorder-suggestions [get]
+func GetReorderSuggestions(c *gin.Context) {
+	settings := services.LoadReorderSettings()
+	if d := c.Query("lookback_days"); d != "" {
+		if val, err := strconv.Atoi(d); err == nil && val > 0 {
+			settings.LookbackDays = val
+		}
+	}
+
+	report, err := reorderService.GetSuggestions(settings, c.Query("all") == "true")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// RunReorderCheck godoc
+// @Summary Jalankan Cek Reorder Sekarang (Admin)
+// @Description Menjalankan job harian secara manual: simpan reorder point & safety stock ke produk dan tandai produk yang diproyeksikan habis dalam lead time
+// @Tags Reports
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /reports/reorder-suggestions/run [post]
+func RunReorderCheck(c *gin.Context) {
+	result, err := reorderService.Recalculate(services.LoadReorderSettings())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var reviewService = services.ReviewService{}
+
+// respondReviewError - 404 jika tidak ditemukan / bukan milik user, 409 untuk duplikat / status transaksi
+func respondReviewError(c *gin.Context, err error, notFound string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+	case errors.Is(err, services.ErrAlreadyReviewed), errors.Is(err, services.ErrTransactionNotCompleted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// CreateReview godoc
+// @Summary (Pelanggan) Beri Rating & Ulasan
+// @Description Rating 1-5 untuk etalase seller dari transaksi COMPLETED milik sendiri, satu ulasan per transaksi (409 jika sudah diulas)
+// @Tags Review
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Transaction ID (UUID)"
+// @Param input body services.ReviewInput true "Rating & Komentar"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /transactions/{id}/review [post]
+func CreateReview(c *gin.Context) {
+	var input services.ReviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := reviewService.Create(c.Param("id"), c.GetString("userID"), input)
+	if err != nil {
+		respondReviewError(c, err, "Transaction not found or unauthorized")
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": review})
+}
+
+// GetListingReviews godoc
+// @Summary Ulasan Etalase Seller
+// @Description Rata-rata rating, jumlah ulasan per bintang, dan ulasan yang tampil untuk satu etalase
+// @Tags Review
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Seller Product ID (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /marketplace/listings/{id}/reviews [get]
+func GetListingReviews(c *gin.Context) {
+	reviews, err := reviewService.GetListingReviews(c.Param("id"))
+	if err != nil {
+		respondReviewError(c, err, "Seller product not found")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": reviews})
+}
+
+// GetSellerReviews godoc
+// @Summary (Seller) Ulasan untuk Etalase Saya
+// @Description Termasuk ulasan yang disembunyikan admin (lihat status)
+// @Tags Review
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /seller/reviews [get]
+func GetSellerReviews(c *gin.Context) {
+	reviews, err := reviewService.GetSellerReviews(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": reviews})
+}
+
+// ReplyReview godoc
+// @Summary (Seller) Balas Ulasan
+// @Description Membalas ulasan di etalase sendiri, balasan sebelumnya ditimpa. Pembeli mendapat notifikasi.
+// @Tags Review
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID (UUID)"
+// @Param input body services.ReviewReplyInput true "Balasan"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /seller/reviews/{id}/reply [post]
+func ReplyReview(c *gin.Context) {
+	var input services.ReviewReplyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := reviewService.Reply(c.Param("id"), c.GetString("userID"), input)
+	if err != nil {
+		respondReviewError(c, err, "Review not found or unauthorized")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": review})
+}
+
+// GetReviews godoc
+// @Summary Lihat Semua Ulasan (Admin)
+// @Tags Review
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "VISIBLE / HIDDEN"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /reviews [get]
+func GetReviews(c *gin.Context) {
+	reviews, err := reviewService.GetAll(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": reviews})
+}
+
+// ModerateReview godoc
+// @Summary Moderasi Ulasan (Admin)
+// @Description HIDDEN: ulasan tidak tampil di marketplace dan tidak dihitung di rating. VISIBLE: tampilkan kembali.
+// @Tags Review
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID (UUID)"
+// @Param input body services.ReviewModerationInput true "Status & Alasan"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /reviews/{id}/moderate [post]
+func ModerateReview(c *gin.Context) {
+	var input services.ReviewModerationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := reviewService.Moderate(c.Param("id"), c.GetString("userID"), input)
+	if err != nil {
+		respondReviewError(c, err, "Review not found")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": review})
+}
@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var roleService = services.RoleService{}
+
+// respondRoleError - Mapping error role & permission ke HTTP status
+func respondRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnknownPermission):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrRoleNameTaken),
+		errors.Is(err, services.ErrSystemRole),
+		errors.Is(err, services.ErrRoleInUse),
+		errors.Is(err, services.ErrLastRoleManager):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// GetPermissions godoc
+// @Summary Lihat Daftar Permission
+// @Description Semua permission yang bisa di-assign ke role (format <resource>.<aksi>, contoh products.write)
+// @Tags Role Management
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /permissions [get]
+func GetPermissions(c *gin.Context) {
+	permissions, err := roleService.ListPermissions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": permissions})
+}
+
+// GetRoleList godoc
+// @Summary Lihat Daftar Role
+// @Description Semua role beserta permission dan jumlah user
+// @Tags Role Management
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} services.RoleDetail
+// @Router /roles [get]
+func GetRoleList(c *gin.Context) {
+	roles, err := roleService.ListRoles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": roles})
+}
+
+// GetRoleDetail godoc
+// @Summary Detail Role
+// @Tags Role Management
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Role ID (UUID)"
+// @Success 200 {object} services.RoleDetail
+// @Failure 404 {object} map[string]string
+// @Router /roles/{id} [get]
+func GetRoleDetail(c *gin.Context) {
+	role, err := roleService.GetRole(c.Param("id"))
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": role})
+}
+
+// CreateRole godoc
+// @Summary Tambah Role
+// @Description Role baru dengan daftar permission. registrable = true membuat role bisa dipilih saat registrasi publik. Dicatat di audit log.
+// @Tags Role Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body services.RoleInput true "Data Role"
+// @Success 201 {object} services.RoleDetail
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /roles [post]
+func CreateRole(c *gin.Context) {
+	var input services.RoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	role, err := roleService.CreateRole(input, auditContext(c))
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": role})
+}
+
+// UpdateRole godoc
+// @Summary Update Role & Permission
+// @Description Ganti data role dan seluruh permission-nya. Role bawaan tidak bisa diganti nama.
+// @Description Ditolak (409) jika tidak ada lagi user dengan roles.manage. Berlaku untuk access token berikutnya (maks. ACCESS_TOKEN_TTL).
+// @Tags Role Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID (UUID)"
+// @Param input body services.RoleInput true "Data Role"
+// @Success 200 {object} services.RoleDetail
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /roles/{id} [put]
+func UpdateRole(c *gin.Context) {
+	var input services.RoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	role, err := roleService.UpdateRole(c.Param("id"), input, auditContext(c))
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": role})
+}
+
+// DeleteRole godoc
+// @Summary Hapus Role
+// @Description Hapus permanen role. Ditolak (409) untuk role bawaan atau role yang masih dipakai user.
+// @Tags Role Management
+// @Security BearerAuth
+// @Param id path string true "Role ID (UUID)"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /roles/{id} [delete]
+func DeleteRole(c *gin.Context) {
+	if err := roleService.DeleteRole(c.Param("id"), auditContext(c)); err != nil {
+		respondRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Role deleted"})
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var sellerPriceService = services.SellerPriceService{}
+
+// GetPriceHistory godoc
+// @Summary (Seller) Histori Harga Jual Produk
+// @Description Semua perubahan harga jual etalase (manual, jadwal, penyesuaian harga modal), terbaru dulu
+// @Tags Seller Catalog
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Seller Product ID (UUID)"
+// @Param limit query int false "Jumlah maksimal (default 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /seller/products/{id}/price-history [get]
+func GetPriceHistory(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	history, err := sellerPriceService.GetPriceHistory(c.Param("id"), c.GetString("userID"), limit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": history})
+}
+
+// GetPriceSchedules godoc
+// @Summary (Seller) Lihat Jadwal Harga Produk
+// @Tags Seller Catalog
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Seller Product ID (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /seller/products/{id}/price-schedules [get]
+func GetPriceSchedules(c *gin.Context) {
+	schedules, err := sellerPriceService.GetPriceSchedules(c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": schedules})
+}
+
+// CreatePriceSchedule godoc
+// @Summary (Seller) Jadwalkan Perubahan Harga
+// @Description Harga jual berubah otomatis pada start_at. Jika end_at diisi, harga dikembalikan ke harga sebelumnya saat end_at (promo).
+// @Description Jadwal yang bentrok dengan jadwal PENDING/ACTIVE lain ditolak (409).
+// @Tags Seller Catalog
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Seller Product ID (UUID)"
+// @Param input body services.PriceScheduleInput true "Jadwal Harga"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /seller/products/{id}/price-schedules [post]
+func CreatePriceSchedule(c *gin.Context) {
+	var input services.PriceScheduleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := sellerPriceService.CreatePriceSchedule(c.Param("id"), c.GetString("userID"), input)
+	if err != nil {
+		if errors.Is(err, services.ErrPriceScheduleOverlap) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": schedule})
+}
+
+// CancelPriceSchedule godoc
+// @Summary (Seller) Batalkan Jadwal Harga
+// @Description Jadwal PENDING dibatalkan; jadwal ACTIVE diakhiri sekarang dan harga dikembalikan
+// @Tags Seller Catalog
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Seller Product ID (UUID)"
+// @Param scheduleId path string true "Schedule ID (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /seller/products/{id}/price-schedules/{scheduleId} [delete]
+func CancelPriceSchedule(c *gin.Context) {
+	schedule, err := sellerPriceService.CancelPriceSchedule(c.Param("id"), c.Param("scheduleId"), c.GetString("userID"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidPriceScheduleStatus):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Price schedule not found"})
+		default:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": schedule})
+}
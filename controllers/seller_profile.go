@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var sellerProfileService = services.SellerProfileService{}
+
+// GetSellerProfile godoc
+// @Summary (Seller) Lihat Profil Toko
+// @Description Profil toko seller yang login, dibuat otomatis dari nama user jika belum ada
+// @Tags Shop
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /seller/profile [get]
+func GetSellerProfile(c *gin.Context) {
+	profile, err := sellerProfileService.GetMyProfile(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": profile})
+}
+
+// UpdateSellerProfile godoc
+// @Summary (Seller) Update Profil Toko
+// @Description Nama toko, slug, deskripsi, logo, kota, jam operasional, dan mode libur. Hanya field yang dikirim yang diubah.
+// @Description Saat mode libur aktif, etalase tidak tampil di marketplace dan order baru ditolak. Slug yang sudah dipakai ditolak (409).
+// @Tags Shop
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body services.UpdateSellerProfileInput true "Profil Toko"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /seller/profile [put]
+func UpdateSellerProfile(c *gin.Context) {
+	var input services.UpdateSellerProfileInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := sellerProfileService.UpdateMyProfile(c.GetString("userID"), input)
+	if err != nil {
+		if errors.Is(err, services.ErrSlugTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": profile})
+}
+
+// GetShop godoc
+// @Summary Halaman Toko (Public)
+// @Description Profil toko, rating, jumlah penjualan, dan etalase aktif (paginated). Etalase kosong saat toko sedang libur.
+// @Tags Shop
+// @Produce json
+// @Param slug path string true "Shop slug"
+// @Param page query int false "Halaman (default 1)"
+// @Param limit query int false "Jumlah per halaman (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /shops/{slug} [get]
+func GetShop(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	shop, err := sellerProfileService.GetShop(c.Param("slug"), page, limit)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Shop not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": shop})
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var stockService = services.StockService{}
+
+// RecordStockMovement godoc
+// @Summary Catat Pergerakan Stok (Admin)
+// @Description Admin mencatat barang masuk, retur, rusak, koreksi manual, atau hasil stock opname. Stok produk ikut berubah.
+// @Tags Stock Ledger
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID (UUID)"
+// @Param input body services.RecordMovementInput true "Data Movement"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /products/{id}/stock-movements [post]
+func RecordStockMovement(c *gin.Context) {
+	var input services.RecordMovementInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	movement, err := stockService.RecordMovement(c.Param("id"), c.GetString("userID"), input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": movement})
+}
+
+// GetStockHistory godoc
+// @Summary Histori Stok Produk (Admin)
+// @Description Melihat ledger pergerakan stok satu produk beserta saldo berjalan
+// @Tags Stock Ledger
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Product ID (UUID)"
+// @Param warehouse_id query string false "Filter gudang"
+// @Param type query string false "Filter jenis movement (PURCHASE_RECEIPT, SALE, RETURN, ADJUSTMENT, DAMAGE, STOCKTAKE, TRANSFER_OUT, TRANSFER_IN)"
+// @Param limit query int false "Limit (default: 50)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /products/{id}/stock-history [get]
+func GetStockHistory(c *gin.Context) {
+	limit := 50
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil {
+			limit = val
+		}
+	}
+
+	history, err := stockService.GetStockHistory(c.Param("id"), c.Query("warehouse_id"), c.Query("type"), limit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": history})
+}
+
+// CheckStockConsistency godoc
+// @Summary Cek Konsistensi Stok vs Ledger (Admin)
+// @Description Membandingkan Product.Stock dengan total movement di ledger. Default hanya menampilkan yang tidak konsisten.
+// @Tags Stock Ledger
+// @Security BearerAuth
+// @Produce json
+// @Param all query bool false "Tampilkan semua produk"
+// @Success 200 {object} map[string]interface{}
+// @Router /products/stock-consistency [get]
+func CheckStockConsistency(c *gin.Context) {
+	showAll := c.Query("all") == "true"
+
+	report, err := stockService.CheckConsistency(showAll)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	inconsistent := 0
+	for _, item := range report {
+		if !item.IsConsistent {
+			inconsistent++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"inconsistent_count": inconsistent,
+		"data":               report,
+	})
+}
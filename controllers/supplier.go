@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var supplierService = services.SupplierService{}
+
+// GetSuppliers godoc
+// @Summary Lihat Daftar Supplier (Admin)
+// @Tags Supplier
+// @Security BearerAuth
+// @Produce json
+// @Param search query string false "Cari nama supplier"
+// @Success 200 {object} map[string]interface{}
+// @Router /suppliers [get]
+func GetSuppliers(c *gin.Context) {
+	suppliers, err := supplierService.GetAll(c.Query("search"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": suppliers})
+}
+
+// GetSupplierByID godoc
+// @Summary Detail Supplier (Admin)
+// @Tags Supplier
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Supplier ID (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /suppliers/{id} [get]
+func GetSupplierByID(c *gin.Context) {
+	supplier, err := supplierService.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Supplier not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": supplier})
+}
+
+// CreateSupplier godoc
+// @Summary Tambah Supplier (Admin)
+// @Description lead_time_days default 7 hari jika tidak diisi
+// @Tags Supplier
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body services.SupplierInput true "Data Supplier"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /suppliers [post]
+func CreateSupplier(c *gin.Context) {
+	var input services.SupplierInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	supplier, err := supplierService.Create(input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": supplier})
+}
+
+// UpdateSupplier godoc
+// @Summary Update Supplier (Admin)
+// @Tags Supplier
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Supplier ID (UUID)"
+// @Param input body services.SupplierInput true "Data Supplier"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /suppliers/{id} [put]
+func UpdateSupplier(c *gin.Context) {
+	var input services.SupplierInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	supplier, err := supplierService.Update(c.Param("id"), input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Supplier not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": supplier})
+}
+
+// DeleteSupplier godoc
+// @Summary Hapus Supplier (Admin)
+// @Description Soft delete supplier. Ditolak (409) jika masih ada purchase order yang belum selesai.
+// @Tags Supplier
+// @Security BearerAuth
+// @Param id path string true "Supplier ID (UUID)"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /suppliers/{id} [delete]
+func DeleteSupplier(c *gin.Context) {
+	if err := supplierService.Delete(c.Param("id")); err != nil {
+		switch {
+		case errors.Is(err, services.ErrSupplierInUse):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Supplier not found"})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Supplier deleted"})
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http" 
 	"technical-test-backend/services"
 
@@ -12,6 +13,8 @@ var trxService = services.TransactionService{}
 // CreateOrder godoc
 // @Summary (Pembeli) Buat Pesanan
 // @Description Pembeli membuat order ke lapak seller (Status: PENDING)
+// @Description Jika etalase sedang flash sale, harga promo dipakai dan kuota dipotong (409 jika sisa kuota kurang)
+// @Description Order ke seller yang sedang libur (vacation mode) ditolak (409)
 // @Tags Transaction
 // @Security BearerAuth
 // @Accept json
@@ -19,6 +22,7 @@ var trxService = services.TransactionService{}
 // @Param input body services.CreateOrderInput true "Data Order"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /transactions [post]
 func CreateOrder(c *gin.Context) {
 	var input services.CreateOrderInput
@@ -31,6 +35,11 @@ func CreateOrder(c *gin.Context) {
 	
 	trx, err := trxService.CreateOrder(input)
 	if err != nil {
+		if errors.Is(err, services.ErrFlashSaleQuotaExceeded) || errors.Is(err, services.ErrSellerOnVacation) ||
+			errors.Is(err, services.ErrSellerUnavailable) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -43,6 +52,7 @@ func CreateOrder(c *gin.Context) {
 // @Description Seller memproses order pending. Stok gudang admin akan berkurang di sini.
 // @Tags Transaction
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path string true "Transaction ID (UUID)"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
@@ -51,7 +61,7 @@ func ConfirmOrder(c *gin.Context) {
 	id := c.Param("id")
 	sellerID := c.GetString("userID")
 
-	if err := trxService.ConfirmOrder(id, sellerID); err != nil {
+	if err := trxService.ConfirmOrder(id, sellerID, auditContext(c)); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -64,6 +74,7 @@ func ConfirmOrder(c *gin.Context) {
 // @Description Seller melihat semua transaksi dari produk mereka dengan detail buyer dan profit
 // @Tags Transaction
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce json
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
@@ -130,7 +141,7 @@ func CancelTransaction(c *gin.Context) {
 	transactionID := c.Param("id")
 	userID := c.GetString("userID")
 
-	err := trxService.CancelTransaction(transactionID, userID)
+	err := trxService.CancelTransaction(transactionID, userID, auditContext(c))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
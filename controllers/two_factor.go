@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"technical-test-backend/services"
+	"technical-test-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var twoFactorService = services.TwoFactorService{}
+
+// respondTwoFactorError - Mapping error 2FA ke HTTP status.
+// invalidCodeStatus: 401 untuk langkah login, 400 untuk pengelolaan 2FA di profil.
+func respondTwoFactorError(c *gin.Context, err error, invalidCodeStatus int) {
+	if respondLoginThrottled(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrInvalidTwoFactorCode):
+		c.JSON(invalidCodeStatus, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidLoginChallenge), errors.Is(err, services.ErrInvalidPassword):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTwoFactorNotAllowed),
+		errors.Is(err, services.ErrAccountSuspended),
+		errors.Is(err, services.ErrAccountDeactivated):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, services.ErrTwoFactorNotEnabled),
+		errors.Is(err, services.ErrTwoFactorRequired):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, utils.ErrEncryptionKeyMissing):
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "2FA belum dikonfigurasi di server (APP_ENCRYPTION_KEY)"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// @Summary Login Step 2 (2FA)
+// @Description Tukar challenge_token dari /auth/login + kode TOTP 6 digit (atau recovery code) dengan access token & refresh token.
+// @Description Kode salah dihitung sebagai login gagal (backoff & lockout sama dengan password salah).
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body services.TwoFactorLoginInput true "Challenge Token & Kode"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/login/2fa [post]
+func VerifyTwoFactorLogin(c *gin.Context) {
+	var input services.TwoFactorLoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := authService.VerifyTwoFactorLogin(input, clientMeta(c))
+	if err != nil {
+		respondTwoFactorError(c, err, http.StatusUnauthorized)
+		return
+	}
+	respondLogin(c, result)
+}
+
+// @Summary Login 2FA Setup (Wajib)
+// @Description Untuk challenge dengan setup_required = true (role wajib 2FA, belum enrolment): buat secret & provisioning URI (QR code).
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body services.LoginChallengeInput true "Challenge Token"
+// @Success 200 {object} services.TwoFactorSetup
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /auth/login/2fa/setup [post]
+func StartLoginTwoFactorSetup(c *gin.Context) {
+	var input services.LoginChallengeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	setup, err := authService.StartChallengeSetup(input)
+	if err != nil {
+		respondTwoFactorError(c, err, http.StatusUnauthorized)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": setup})
+}
+
+// @Summary Login 2FA Setup Confirm (Wajib)
+// @Description Konfirmasi enrolment dengan kode pertama dari authenticator. Login selesai: token + recovery_codes (tampil sekali).
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body services.TwoFactorLoginInput true "Challenge Token & Kode"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/login/2fa/setup/confirm [post]
+func ConfirmLoginTwoFactorSetup(c *gin.Context) {
+	var input services.TwoFactorLoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := authService.ConfirmChallengeSetup(input, clientMeta(c))
+	if err != nil {
+		respondTwoFactorError(c, err, http.StatusUnauthorized)
+		return
+	}
+	respondLogin(c, result)
+}
+
+// @Summary Get 2FA Status
+// @Description Status 2FA user: aktif, boleh dipakai role (TWO_FACTOR_ROLES), wajib (TWO_FACTOR_REQUIRED_ROLES), sisa recovery code
+// @Tags Profile
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} services.TwoFactorStatus
+// @Router /profile/2fa [get]
+func GetTwoFactorStatus(c *gin.Context) {
+	status, err := twoFactorService.GetStatus(c.GetString("userID"))
+	if err != nil {
+		respondTwoFactorError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+// @Summary Start 2FA Enrolment
+// @Description Buat secret TOTP baru & provisioning URI (otpauth://, tampilkan sebagai QR code). 2FA aktif setelah /profile/2fa/enable.
+// @Tags Profile
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} services.TwoFactorSetup
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /profile/2fa/setup [post]
+func SetupTwoFactor(c *gin.Context) {
+	setup, err := twoFactorService.Setup(c.GetString("userID"))
+	if err != nil {
+		respondTwoFactorError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": setup})
+}
+
+// @Summary Enable 2FA
+// @Description Konfirmasi enrolment dengan kode dari authenticator. Response berisi recovery code (hanya ditampilkan sekali).
+// @Tags Profile
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body services.TwoFactorCodeInput true "Kode TOTP"
+// @Success 200 {object} services.RecoveryCodesResult
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /profile/2fa/enable [post]
+func EnableTwoFactor(c *gin.Context) {
+	var input services.TwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := twoFactorService.Enable(c.GetString("userID"), input, c.ClientIP())
+	if err != nil {
+		respondTwoFactorError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "2FA aktif, simpan recovery code di tempat aman", "data": result})
+}
+
+// @Summary Disable 2FA
+// @Description Matikan 2FA dengan password + kode TOTP / recovery code. Ditolak (409) untuk role yang wajib 2FA.
+// @Tags Profile
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body services.DisableTwoFactorInput true "Password & Kode"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /profile/2fa/disable [post]
+func DisableTwoFactor(c *gin.Context) {
+	var input services.DisableTwoFactorInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := twoFactorService.Disable(c.GetString("userID"), input, c.ClientIP()); err != nil {
+		respondTwoFactorError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "2FA dinonaktifkan"})
+}
+
+// @Summary Regenerate Recovery Codes
+// @Description Ganti semua recovery code (yang lama langsung tidak berlaku). Butuh kode TOTP.
+// @Tags Profile
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body services.TwoFactorCodeInput true "Kode TOTP"
+// @Success 200 {object} services.RecoveryCodesResult
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /profile/2fa/recovery-codes [post]
+func RegenerateRecoveryCodes(c *gin.Context) {
+	var input services.TwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := twoFactorService.RegenerateRecoveryCodes(c.GetString("userID"), input, c.ClientIP())
+	if err != nil {
+		respondTwoFactorError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// ResetUserTwoFactor godoc
+// @Summary Reset 2FA User (Admin)
+// @Description Hapus 2FA & recovery code user yang kehilangan perangkat. Sesi user dicabut, dicatat di audit log.
+// @Tags User Management
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/{id}/2fa [delete]
+func ResetUserTwoFactor(c *gin.Context) {
+	if err := twoFactorService.ResetForUser(c.Param("id"), auditContext(c)); err != nil {
+		respondTwoFactorError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "2FA user direset"})
+}
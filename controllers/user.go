@@ -1,15 +1,17 @@
 package controllers
 
 import (
+	"errors"
 	"technical-test-backend/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 var userService = services.UserService{}
 
 // CreateAdmin godoc
 // @Summary Tambah Admin Baru (Super Admin Only)
-// @Description Menambahkan user dengan role Admin
+// @Description Menambahkan user dengan role Admin: role_id jika dikirim, selain itu role (sistem) yang memiliki permission users.manage
 // @Tags User Management
 // @Security BearerAuth
 // @Accept json
@@ -29,22 +31,118 @@ func CreateAdmin(c *gin.Context) {
 	c.JSON(201, gin.H{"message": "Admin created"})
 }
 
+// respondAccountStatusError - Mapping error suspend / reactivate / deactivate / delete user ke HTTP status
+func respondAccountStatusError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(404, gin.H{"error": "User not found"})
+	case errors.Is(err, services.ErrCannotChangeOwnStatus),
+		errors.Is(err, services.ErrAccountAnonymized),
+		errors.Is(err, services.ErrAccountDeactivated),
+		errors.Is(err, services.ErrLastRoleManager):
+		c.JSON(409, gin.H{"error": err.Error()})
+	default:
+		c.JSON(400, gin.H{"error": err.Error()})
+	}
+}
+
 // DeleteUser godoc
 // @Summary Hapus User
-// @Description Menghapus user berdasarkan ID (Hard Delete)
+// @Description Menghapus akun tanpa hard delete: data pribadi (nama, email, alamat) dianonimkan, kredensial & sesi dihapus,
+// @Description etalase seller dinonaktifkan. Transaksi & review tetap utuh untuk laporan keuangan. Dicatat di audit log.
 // @Tags User Management
 // @Security BearerAuth
 // @Param id path string true "User ID (UUID)"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /users/{id} [delete]
 func DeleteUser(c *gin.Context) {
-	if err := userService.DeleteUser(c.Param("id")); err != nil {
-		c.JSON(400, gin.H{"error": "Failed to delete"}); return
+	if err := userService.DeleteUser(c.Param("id"), auditContext(c)); err != nil {
+		respondAccountStatusError(c, err); return
 	}
 	c.JSON(200, gin.H{"message": "User deleted"})
 }
 
+// SuspendUser godoc
+// @Summary Suspend User (Admin)
+// @Description Tangguhkan akun dengan alasan, until opsional (kosong = sampai diaktifkan kembali). Token yang masih berlaku langsung ditolak,
+// @Description semua sesi dicabut, etalase seller hilang dari marketplace. Dicatat di audit log.
+// @Tags User Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param input body services.SuspendUserInput true "Alasan & Batas Waktu"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/{id}/suspend [post]
+func SuspendUser(c *gin.Context) {
+	var input services.SuspendUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := userService.SuspendUser(c.Param("id"), input, auditContext(c))
+	if err != nil {
+		respondAccountStatusError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "User suspended", "data": user})
+}
+
+// ReactivateUser godoc
+// @Summary Reactivate User (Admin)
+// @Description Cabut suspensi atau aktifkan kembali akun yang dinonaktifkan. Akun yang sudah dihapus (dianonimkan) tidak bisa diaktifkan.
+// @Tags User Management
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/{id}/reactivate [post]
+func ReactivateUser(c *gin.Context) {
+	user, err := userService.ReactivateUser(c.Param("id"), auditContext(c))
+	if err != nil {
+		respondAccountStatusError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "User reactivated", "data": user})
+}
+
+// DeactivateUser godoc
+// @Summary Deactivate User (Admin)
+// @Description Tutup akun tanpa menghapus data (bisa diaktifkan kembali). Semua sesi dicabut, dicatat di audit log.
+// @Tags User Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param input body services.DeactivateUserInput true "Alasan"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/{id}/deactivate [post]
+func DeactivateUser(c *gin.Context) {
+	var input services.DeactivateUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := userService.DeactivateUser(c.Param("id"), input, auditContext(c))
+	if err != nil {
+		respondAccountStatusError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "User deactivated", "data": user})
+}
+
 // FindUsers godoc
 // @Summary Lihat Daftar Semua User
 // @Description Mengambil list semua user beserta rolenya
@@ -80,6 +178,7 @@ func GetUserDetail(c *gin.Context) {
 // @Param id path string true "User ID"
 // @Param input body services.UpdateUserInput true "Update Data"
 // @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string
 // @Router /users/{id} [put]
 func UpdateUser(c *gin.Context) {
 	var input services.UpdateUserInput
@@ -88,8 +187,12 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
-	user, err := userService.UpdateUser(c.Param("id"), input)
+	user, err := userService.UpdateUser(c.Param("id"), input, auditContext(c))
 	if err != nil {
+		if errors.Is(err, services.ErrLastRoleManager) {
+			c.JSON(409, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(400, gin.H{"error": "Failed to update user"})
 		return
 	}
@@ -99,3 +202,37 @@ func UpdateUser(c *gin.Context) {
 		"data":    user,
 	})
 }
+
+// UnlockUserLogin godoc
+// @Summary Unlock Login User (Admin)
+// @Description Membuka kunci / backoff login akun karena terlalu banyak login gagal. IP opsional ikut dibuka. Dicatat di audit log.
+// @Tags User Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param input body services.UnlockLoginInput false "IP (opsional)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/unlock-login [post]
+func UnlockUserLogin(c *gin.Context) {
+	var input services.UnlockLoginInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	result, err := userService.UnlockLogin(c.Param("id"), auditContext(c), input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(404, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"message": "Login unlocked", "data": result})
+}
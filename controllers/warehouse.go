@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var warehouseService = services.WarehouseService{}
+
+// GetWarehouses godoc
+// @Summary Lihat Daftar Gudang (Admin)
+// @Tags Warehouse
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /warehouses [get]
+func GetWarehouses(c *gin.Context) {
+	warehouses, err := warehouseService.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": warehouses})
+}
+
+// CreateWarehouse godoc
+// @Summary Tambah Gudang (Admin)
+// @Description Menambahkan lokasi gudang baru. is_default=true akan memindahkan status default dari gudang lain.
+// @Tags Warehouse
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body services.WarehouseInput true "Data Gudang"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /warehouses [post]
+func CreateWarehouse(c *gin.Context) {
+	var input services.WarehouseInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	warehouse, err := warehouseService.Create(input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": warehouse})
+}
+
+// UpdateWarehouse godoc
+// @Summary Update Gudang (Admin)
+// @Tags Warehouse
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Warehouse ID (UUID)"
+// @Param input body services.WarehouseInput true "Data Gudang"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /warehouses/{id} [put]
+func UpdateWarehouse(c *gin.Context) {
+	var input services.WarehouseInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	warehouse, err := warehouseService.Update(c.Param("id"), input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Warehouse not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": warehouse})
+}
+
+// DeleteWarehouse godoc
+// @Summary Hapus Gudang (Admin)
+// @Description Soft delete gudang. Ditolak (409) jika gudang default atau masih menyimpan stok.
+// @Tags Warehouse
+// @Security BearerAuth
+// @Param id path string true "Warehouse ID (UUID)"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /warehouses/{id} [delete]
+func DeleteWarehouse(c *gin.Context) {
+	if err := warehouseService.Delete(c.Param("id")); err != nil {
+		switch {
+		case errors.Is(err, services.ErrWarehouseInUse):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Warehouse not found"})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Warehouse deleted"})
+}
+
+// GetWarehouseStock godoc
+// @Summary Stok per Gudang (Admin)
+// @Description Melihat stok semua produk di satu gudang
+// @Tags Warehouse
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Warehouse ID (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /warehouses/{id}/stock [get]
+func GetWarehouseStock(c *gin.Context) {
+	levels, err := warehouseService.GetStock(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Warehouse not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": levels})
+}
+
+// TransferStock godoc
+// @Summary Transfer Stok Antar Gudang (Admin)
+// @Description Memindahkan stok satu produk dari satu gudang ke gudang lain. Total stok produk tidak berubah.
+// @Tags Warehouse
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body services.TransferInput true "Data Transfer"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /warehouses/transfers [post]
+func TransferStock(c *gin.Context) {
+	var input services.TransferInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transfer, err := warehouseService.Transfer(c.GetString("userID"), input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": transfer})
+}
+
+// GetStockTransfers godoc
+// @Summary Histori Transfer Antar Gudang (Admin)
+// @Tags Warehouse
+// @Security BearerAuth
+// @Produce json
+// @Param warehouse_id query string false "Filter gudang asal/tujuan"
+// @Param product_id query string false "Filter produk"
+// @Success 200 {object} map[string]interface{}
+// @Router /warehouses/transfers [get]
+func GetStockTransfers(c *gin.Context) {
+	transfers, err := warehouseService.GetTransfers(c.Query("warehouse_id"), c.Query("product_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": transfers})
+}
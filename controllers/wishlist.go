@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var wishlistService = services.WishlistService{}
+
+// GetWishlist godoc
+// @Summary (Pelanggan) Lihat Wishlist
+// @Description Etalase yang disimpan beserta harga sekarang, harga saat disimpan, dan status stok (termasuk yang stoknya habis)
+// @Tags Wishlist
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /customer/wishlist [get]
+func GetWishlist(c *gin.Context) {
+	items, err := wishlistService.GetWishlist(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+// AddToWishlist godoc
+// @Summary (Pelanggan) Simpan ke Wishlist
+// @Description Menyimpan etalase aktif. Notifikasi dikirim saat harga jual turun atau produk kembali tersedia.
+// @Description Etalase yang sudah ada di wishlist mengembalikan 200 dengan entri yang sama.
+// @Tags Wishlist
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body services.AddToWishlistInput true "Etalase"
+// @Success 201 {object} map[string]interface{}
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /customer/wishlist [post]
+func AddToWishlist(c *gin.Context) {
+	var input services.AddToWishlistInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, created, err := wishlistService.Add(c.GetString("userID"), input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+	c.JSON(status, gin.H{"data": item})
+}
+
+// RemoveFromWishlist godoc
+// @Summary (Pelanggan) Hapus dari Wishlist
+// @Tags Wishlist
+// @Security BearerAuth
+// @Produce json
+// @Param sellerProductId path string true "Seller Product ID (UUID)"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /customer/wishlist/{sellerProductId} [delete]
+func RemoveFromWishlist(c *gin.Context) {
+	if err := wishlistService.Remove(c.GetString("userID"), c.Param("sellerProductId")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Wishlist item not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Removed from wishlist"})
+}
@@ -0,0 +1,397 @@
+package database
+
+import (
+	"fmt"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// runPreMigrations - Perbaikan data yang harus selesai sebelum AutoMigrate,
+// misalnya sebelum unique index baru dibuat. Setiap langkah harus idempotent.
+func runPreMigrations(db *gorm.DB) error {
+	if err := mergeDuplicateSellerProducts(db); err != nil {
+		return fmt.Errorf("merge duplicate seller products: %w", err)
+	}
+	if err := addEmailVerifiedAt(db); err != nil {
+		return fmt.Errorf("add users.email_verified_at: %w", err)
+	}
+	if err := addRoleFlags(db); err != nil {
+		return fmt.Errorf("add roles flags: %w", err)
+	}
+	return nil
+}
+
+// addRoleFlags - Tambah kolom roles.registrable & roles.is_system sekali saja. Role bawaan
+// ditandai system, Seller & Pelanggan tetap bisa dipilih saat registrasi seperti sebelumnya.
+// Setelahnya nilai kolom diatur lewat API role (tidak ditimpa lagi saat startup).
+func addRoleFlags(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Role{}) {
+		return nil
+	}
+	for _, field := range []string{"Registrable", "IsSystem"} {
+		if db.Migrator().HasColumn(&models.Role{}, field) {
+			continue
+		}
+		if err := db.Migrator().AddColumn(&models.Role{}, field); err != nil {
+			return err
+		}
+		switch field {
+		case "Registrable":
+			if err := db.Exec("UPDATE roles SET registrable = true WHERE name IN ?", []string{"Seller", "Pelanggan"}).Error; err != nil {
+				return err
+			}
+		case "IsSystem":
+			if err := db.Exec("UPDATE roles SET is_system = true WHERE name IN ?", []string{"Admin", "Seller", "Pelanggan"}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addEmailVerifiedAt - Tambah kolom users.email_verified_at sekali saja, user yang terdaftar
+// sebelum verifikasi email diterapkan dianggap sudah terverifikasi. User baru setelahnya
+// tetap NULL sampai membuka link verifikasi.
+func addEmailVerifiedAt(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.User{}) || db.Migrator().HasColumn(&models.User{}, "EmailVerifiedAt") {
+		return nil
+	}
+	if err := db.Migrator().AddColumn(&models.User{}, "EmailVerifiedAt"); err != nil {
+		return err
+	}
+	return db.Exec("UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL").Error
+}
+
+// mergeDuplicateSellerProducts - Gabungkan etalase ganda (seller & produk yang sama)
+// sebelum unique index idx_seller_products_seller_product dibuat.
+// Etalase yang dipertahankan: yang aktif, lalu yang paling lama. Referensi transaksi,
+// histori harga, jadwal harga, flash sale, dan ulasan dipindahkan ke etalase tersebut,
+// lalu etalase duplikat di-soft delete.
+// Database lama tanpa kolom deleted_at (ditambah AutoMigrate setelah langkah ini): semua baris
+// dihitung dan etalase duplikat dihapus permanen (referensinya sudah dipindahkan).
+func mergeDuplicateSellerProducts(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.SellerProduct{}) {
+		return nil
+	}
+	softDelete := db.Migrator().HasColumn(&models.SellerProduct{}, "DeletedAt")
+
+	var groups []struct {
+		SellerID  string
+		ProductID string
+	}
+	activeFilter := ""
+	if softDelete {
+		activeFilter = "WHERE deleted_at IS NULL"
+	}
+	err := db.Raw(`
+		SELECT seller_id, product_id FROM seller_products
+		` + activeFilter + `
+		GROUP BY seller_id, product_id
+		HAVING COUNT(*) > 1`).Scan(&groups).Error
+	if err != nil || len(groups) == 0 {
+		return err
+	}
+
+	// Tabel yang mereferensikan seller_products.id (tabel baru mungkin belum ada di database lama)
+	referencing := []string{"transactions", "seller_product_price_histories", "seller_product_price_schedules", "flash_sale_items", "reviews"}
+
+	merged := 0
+	err = db.Transaction(func(tx *gorm.DB) error {
+		listingDB := tx
+		if !softDelete {
+			listingDB = tx.Unscoped()
+		}
+		for _, group := range groups {
+			var listings []models.SellerProduct
+			if err := listingDB.Where("seller_id = ? AND product_id = ?", group.SellerID, group.ProductID).
+				Order("is_active DESC, created_at ASC").
+				Find(&listings).Error; err != nil {
+				return err
+			}
+			if len(listings) < 2 {
+				continue
+			}
+
+			keep := listings[0]
+			duplicateIDs := make([]string, 0, len(listings)-1)
+			for _, dup := range listings[1:] {
+				duplicateIDs = append(duplicateIDs, dup.ID.String())
+			}
+
+			for _, table := range referencing {
+				if !tx.Migrator().HasTable(table) {
+					continue
+				}
+				if err := tx.Table(table).Where("seller_product_id IN ?", duplicateIDs).
+					Update("seller_product_id", keep.ID).Error; err != nil {
+					return fmt.Errorf("%s: %w", table, err)
+				}
+			}
+			if err := listingDB.Where("id IN ?", duplicateIDs).Delete(&models.SellerProduct{}).Error; err != nil {
+				return err
+			}
+			merged += len(duplicateIDs)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ %d etalase duplikat digabung ke %d etalase\n", merged, len(groups))
+	return nil
+}
+
+// runMigrations - Migrasi tambahan yang tidak bisa ditangani AutoMigrate
+// Dijalankan setelah AutoMigrate, setiap langkah harus idempotent
+func runMigrations(db *gorm.DB) error {
+	if err := refreshForeignKeys(db); err != nil {
+		return err
+	}
+	if err := protectAuditLogs(db); err != nil {
+		return fmt.Errorf("audit_logs append-only trigger: %w", err)
+	}
+	return nil
+}
+
+// protectAuditLogs - Audit log hanya boleh ditambah: trigger menolak UPDATE & DELETE
+// (termasuk soft delete) dari aplikasi maupun query manual. Idempotent.
+func protectAuditLogs(db *gorm.DB) error {
+	if err := db.Exec(`CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_logs is append-only (% not allowed)', TG_OP;
+END;
+$$ LANGUAGE plpgsql`).Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs").Error; err != nil {
+		return err
+	}
+	return db.Exec(`CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs
+	FOR EACH ROW EXECUTE PROCEDURE audit_logs_append_only()`).Error
+}
+
+// refreshForeignKeys - Ganti aturan ON DELETE pada foreign key lama
+// AutoMigrate tidak pernah mengubah constraint yang sudah ada, sehingga
+// database lama masih memakai SET NULL / CASCADE dari versi sebelumnya.
+func refreshForeignKeys(db *gorm.DB) error {
+	constraints := []struct {
+		model    interface{}
+		relation string
+		name     string
+	}{
+		{&models.Product{}, "ProductType", "fk_products_product_type"},
+		{&models.SellerProduct{}, "Product", "fk_seller_products_product"},
+	}
+
+	for _, c := range constraints {
+		var deleteRule string
+		db.Raw(
+			"SELECT delete_rule FROM information_schema.referential_constraints WHERE constraint_name = ?",
+			c.name,
+		).Scan(&deleteRule)
+
+		if deleteRule == "RESTRICT" {
+			continue
+		}
+
+		if deleteRule != "" {
+			if err := db.Migrator().DropConstraint(c.model, c.name); err != nil {
+				return fmt.Errorf("drop constraint %s: %w", c.name, err)
+			}
+		}
+		if err := db.Migrator().CreateConstraint(c.model, c.relation); err != nil {
+			return fmt.Errorf("create constraint %s: %w", c.name, err)
+		}
+	}
+	return nil
+}
+
+// backfillData - Lengkapi data turunan setelah seeding
+// Dijalankan setiap start, setiap langkah harus idempotent
+func backfillData(db *gorm.DB) error {
+	var defaultWarehouse models.Warehouse
+	if err := db.Where("is_default = ?", true).First(&defaultWarehouse).Error; err != nil {
+		return fmt.Errorf("default warehouse: %w", err)
+	}
+
+	if err := backfillStockLedger(db, defaultWarehouse); err != nil {
+		return err
+	}
+	if err := backfillWarehouseStock(db, defaultWarehouse); err != nil {
+		return err
+	}
+	if err := backfillProductSKU(db); err != nil {
+		return err
+	}
+	if err := backfillSellerPriceHistory(db); err != nil {
+		return err
+	}
+	if err := backfillSellerProfiles(db); err != nil {
+		return err
+	}
+	return nil
+}
+
+// backfillSellerProfiles - Profil toko default (nama toko = nama user) untuk seller
+// (role dengan permission seller.listings) yang terdaftar sebelum profil toko ada
+func backfillSellerProfiles(db *gorm.DB) error {
+	var sellers []models.User
+	err := db.Joins("JOIN role_permissions rp ON rp.role_id = users.role_id").
+		Joins("JOIN permissions ON permissions.id = rp.permission_id AND permissions.deleted_at IS NULL").
+		Where("permissions.name = ?", models.PermSellerListings).
+		Where("NOT EXISTS (SELECT 1 FROM seller_profiles p WHERE p.user_id = users.id AND p.deleted_at IS NULL)").
+		Find(&sellers).Error
+	if err != nil {
+		return err
+	}
+
+	for _, seller := range sellers {
+		base := utils.Slugify(seller.Name)
+		if base == "" {
+			base = "toko"
+		}
+		slug := utils.UniqueSlug(base, func(slug string) bool {
+			var count int64
+			db.Model(&models.SellerProfile{}).Where("slug = ?", slug).Count(&count)
+			return count > 0
+		})
+		profile := models.SellerProfile{
+			UserID:   seller.ID,
+			ShopName: seller.Name,
+			Slug:     slug,
+			City:     seller.City,
+		}
+		if err := db.Create(&profile).Error; err != nil {
+			return fmt.Errorf("backfill seller profile %s: %w", seller.ID, err)
+		}
+	}
+	if len(sellers) > 0 {
+		fmt.Printf("✅ Profil toko dibuat untuk %d seller\n", len(sellers))
+	}
+	return nil
+}
+
+// backfillSellerPriceHistory - Harga jual saat ini menjadi histori INITIAL untuk etalase
+// yang dibuat sebelum histori harga ada
+func backfillSellerPriceHistory(db *gorm.DB) error {
+	var listings []models.SellerProduct
+	err := db.Where("NOT EXISTS (SELECT 1 FROM seller_product_price_histories h WHERE h.seller_product_id = seller_products.id)").
+		Find(&listings).Error
+	if err != nil {
+		return err
+	}
+
+	for _, listing := range listings {
+		history := models.SellerProductPriceHistory{
+			SellerProductID: listing.ID,
+			NewPrice:        listing.SellingPrice,
+			Source:          models.PriceSourceInitial,
+		}
+		if err := db.Create(&history).Error; err != nil {
+			return fmt.Errorf("backfill price history %s: %w", listing.ID, err)
+		}
+	}
+	if len(listings) > 0 {
+		fmt.Printf("✅ Histori harga awal dibuat untuk %d etalase\n", len(listings))
+	}
+	return nil
+}
+
+// backfillProductSKU - Generate SKU untuk produk lama / hasil seeding yang belum punya SKU
+// Format: 3 huruf kategori + 6 hex acak, contoh ELE-4F9A2C
+func backfillProductSKU(db *gorm.DB) error {
+	var products []models.Product
+	if err := db.Preload("ProductType").Where("sku = ''").Find(&products).Error; err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		prefix := utils.SKUPrefix(product.ProductType.Name)
+		for {
+			sku := utils.GenerateSKU(prefix)
+			var exists int64
+			db.Model(&models.Product{}).Where("sku = ?", sku).Count(&exists)
+			if exists > 0 {
+				continue
+			}
+			if err := db.Model(&product).Update("sku", sku).Error; err != nil {
+				return fmt.Errorf("backfill sku %s: %w", product.ID, err)
+			}
+			break
+		}
+	}
+	if len(products) > 0 {
+		fmt.Printf("✅ SKU di-generate untuk %d produk\n", len(products))
+	}
+	return nil
+}
+
+// backfillStockLedger - Buat movement saldo awal untuk produk yang belum punya ledger
+// sehingga SUM(stock_movements.quantity) = products.stock untuk data lama & hasil seeding
+func backfillStockLedger(db *gorm.DB, defaultWarehouse models.Warehouse) error {
+	// Movement lama (sebelum multi-gudang) dianggap terjadi di gudang default
+	if err := db.Model(&models.StockMovement{}).
+		Where("warehouse_id IS NULL").
+		Updates(map[string]interface{}{
+			"warehouse_id":            defaultWarehouse.ID,
+			"warehouse_balance_after": gorm.Expr("balance_after"),
+		}).Error; err != nil {
+		return err
+	}
+
+	var products []models.Product
+	err := db.Where("stock <> 0").
+		Where("NOT EXISTS (SELECT 1 FROM stock_movements WHERE stock_movements.product_id = products.id)").
+		Find(&products).Error
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		movement := models.StockMovement{
+			ProductID:             product.ID,
+			WarehouseID:           &defaultWarehouse.ID,
+			Type:                  models.MovementAdjustment,
+			Quantity:              product.Stock,
+			BalanceAfter:          product.Stock,
+			WarehouseBalanceAfter: product.Stock,
+			Reason:                "Saldo awal",
+		}
+		if err := db.Create(&movement).Error; err != nil {
+			return fmt.Errorf("backfill stock ledger %s: %w", product.ID, err)
+		}
+	}
+	if len(products) > 0 {
+		fmt.Printf("✅ Saldo awal ledger stok dibuat untuk %d produk\n", len(products))
+	}
+	return nil
+}
+
+// backfillWarehouseStock - Pindahkan stok produk yang belum punya rincian gudang ke gudang default
+// Sebelum multi-gudang, seluruh Product.Stock berada di gudang pusat
+func backfillWarehouseStock(db *gorm.DB, defaultWarehouse models.Warehouse) error {
+	var products []models.Product
+	err := db.Where("stock > 0").
+		Where("NOT EXISTS (SELECT 1 FROM warehouse_stocks WHERE warehouse_stocks.product_id = products.id)").
+		Find(&products).Error
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		level := models.WarehouseStock{
+			WarehouseID: defaultWarehouse.ID,
+			ProductID:   product.ID,
+			Quantity:    product.Stock,
+		}
+		if err := db.Create(&level).Error; err != nil {
+			return fmt.Errorf("backfill warehouse stock %s: %w", product.ID, err)
+		}
+	}
+	if len(products) > 0 {
+		fmt.Printf("✅ Stok %d produk dipindahkan ke %s\n", len(products), defaultWarehouse.Name)
+	}
+	return nil
+}
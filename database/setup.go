@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"technical-test-backend/models"
+	"time"
 
 	"golang.org/x/crypto/bcrypt" 
 	"gorm.io/driver/postgres"
@@ -37,25 +38,65 @@ func ConnectDatabase() {
 		log.Fatal("Gagal koneksi ke database:", err)
 	}
 
-	// 4. Auto migrate semua model (create tables jika belum ada)
-	// Urutan penting: Role -> ProductType -> User -> Product -> SellerProduct -> Transaction
+	// 4. Perbaikan data lama yang harus selesai sebelum AutoMigrate (unique index baru)
+	if err := runPreMigrations(database); err != nil {
+		log.Fatal("Gagal migrasi database:", err)
+	}
+
+	// 5. Auto migrate semua model (create tables jika belum ada)
+	// Urutan penting: Permission -> Role (+ role_permissions) -> ProductType -> User -> Supplier -> Product -> SellerProduct -> Transaction -> Warehouse -> StockMovement -> PurchaseOrder
 	err = database.AutoMigrate(
+		&models.Permission{},
 		&models.Role{}, 
 		&models.ProductType{}, 
 		&models.User{}, 
+		&models.Supplier{},
 		&models.Product{},
 		&models.SellerProduct{},
     	&models.Transaction{},
+		&models.Warehouse{},
+		&models.WarehouseStock{},
+		&models.StockTransfer{},
+		&models.StockMovement{},
+		&models.PurchaseOrder{},
+		&models.PurchaseOrderLine{},
+		&models.Notification{},
+		&models.SellerProductPriceHistory{},
+		&models.SellerProductPriceSchedule{},
+		&models.FlashSale{},
+		&models.FlashSaleItem{},
+		&models.Review{},
+		&models.WishlistItem{},
+		&models.SellerProfile{},
+		&models.RefreshToken{},
+		&models.RevokedToken{},
+		&models.UserToken{},
+		&models.LoginAttempt{},
+		&models.AuditLog{},
+		&models.UserTwoFactor{},
+		&models.RecoveryCode{},
+		&models.SigningKey{},
+		&models.APIKey{},
+		&models.UserIdentity{},
+		&models.OIDCLoginState{},
 	)
 	if err != nil {
 		log.Fatal("Gagal migrasi database:", err)
 	}
+	if err := runMigrations(database); err != nil {
+		log.Fatal("Gagal migrasi database:", err)
+	}
 	fmt.Println("✅ Migrasi Database Berhasil!")
 
-	// 5. Seeding data awal untuk development/testing
+	// 6. Seeding data awal untuk development/testing
 	seedDatabase(database)
 
-	// 6. Assign database connection ke global variable
+	// 7. Lengkapi data turunan (saldo awal ledger stok, dll)
+	if err := backfillData(database); err != nil {
+		log.Fatal("Gagal backfill data:", err)
+	}
+
+	// 8. Assign database connection ke global variable
 	DB = database
 }
 
@@ -68,14 +109,20 @@ func seedDatabase(db *gorm.DB) {
 	db.Model(&models.Role{}).Count(&countRoles)
 	if countRoles == 0 {
 		roles := []models.Role{
-			{Name: "Admin"}, 
-			{Name: "Seller"}, 
-			{Name: "Pelanggan"},
+			{Name: "Admin", Description: "Pengelola platform", IsSystem: true},
+			{Name: "Seller", Description: "Penjual di marketplace", Registrable: true, IsSystem: true},
+			{Name: "Pelanggan", Description: "Pembeli di marketplace", Registrable: true, IsSystem: true},
 		}
 		db.Create(&roles)
 		fmt.Println("✅ Data Roles Berhasil Dibuat!")
 	}
 
+	// --- SYNC PERMISSIONS ---
+	// Permission baru di kode otomatis dibuat & diberikan ke role bawaan
+	if err := syncPermissions(db); err != nil {
+		log.Fatal("Gagal sinkronisasi permission:", err)
+	}
+
 	// --- SEEDING PRODUCT TYPES ---
 	// Buat 5 kategori produk untuk marketplace
 	var countTypes int64
@@ -92,6 +139,21 @@ func seedDatabase(db *gorm.DB) {
 		fmt.Println("✅ Data Product Types Berhasil Dibuat!")
 	}
 
+	// --- SEEDING WAREHOUSES ---
+	// Buat gudang pusat sebagai gudang default
+	var countWarehouses int64
+	db.Model(&models.Warehouse{}).Count(&countWarehouses)
+	if countWarehouses == 0 {
+		db.Create(&models.Warehouse{
+			Code:      "GDG-PUSAT",
+			Name:      "Gudang Pusat",
+			City:      "Jakarta",
+			IsDefault: true,
+			IsActive:  true,
+		})
+		fmt.Println("✅ Data Gudang Pusat Berhasil Dibuat!")
+	}
+
 	// --- GET ROLES ---
 	// Ambil role ID untuk digunakan saat seeding users
 	var adminRole, sellerRole, pelangganRole models.Role
@@ -165,6 +227,12 @@ func seedDatabase(db *gorm.DB) {
 			},
 		}
 
+		// Demo user dianggap sudah verifikasi email
+		verifiedAt := time.Now()
+		for i := range users {
+			users[i].EmailVerifiedAt = &verifiedAt
+		}
+
 		if err := db.Create(&users).Error; err != nil {
 			log.Fatal("Gagal seeding users:", err)
 		}
@@ -232,4 +300,44 @@ func seedDatabase(db *gorm.DB) {
 		}
 		fmt.Println("✅ Sample Products Berhasil Dibuat! (24 produk)")
 	}
-}
\ No newline at end of file
+}
+
+// syncPermissions - Buat permission di models.PermissionDefinitions yang belum ada di database.
+// Permission baru langsung diberikan ke DefaultRoles; mapping permission yang sudah ada
+// tidak disentuh agar perubahan lewat API role tetap dipertahankan.
+func syncPermissions(db *gorm.DB) error {
+	created := 0
+	for _, def := range models.PermissionDefinitions {
+		var permission models.Permission
+		if err := db.Where("name = ?", def.Name).Limit(1).Find(&permission).Error; err != nil {
+			return err
+		}
+		if permission.Name != "" {
+			if permission.Description != def.Description {
+				if err := db.Model(&permission).Update("description", def.Description).Error; err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		permission = models.Permission{Name: def.Name, Description: def.Description}
+		if err := db.Create(&permission).Error; err != nil {
+			return err
+		}
+		var roles []models.Role
+		if err := db.Where("name IN ?", def.DefaultRoles).Find(&roles).Error; err != nil {
+			return err
+		}
+		for i := range roles {
+			if err := db.Model(&roles[i]).Association("Permissions").Append(&permission); err != nil {
+				return err
+			}
+		}
+		created++
+	}
+	if created > 0 {
+		fmt.Printf("✅ %d Permission Baru Berhasil Dibuat!\n", created)
+	}
+	return nil
+}
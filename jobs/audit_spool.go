@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"log"
+	"technical-test-backend/services"
+	"technical-test-backend/utils"
+	"time"
+)
+
+// startAuditSpoolJob - Masukkan ulang entry audit log yang gagal ditulis ke database (spool AUDIT_SPOOL_FILE).
+// Interval diatur dengan AUDIT_SPOOL_INTERVAL (default 1m, "0" untuk mematikan).
+func startAuditSpoolJob() {
+	runEvery("audit-spool", utils.EnvDuration("AUDIT_SPOOL_INTERVAL", time.Minute), func() error {
+		replayed, err := services.ReplayAuditSpool()
+		if replayed > 0 {
+			log.Printf("[job] audit-spool: %d audit log entries replayed", replayed)
+		}
+		return err
+	})
+}
@@ -0,0 +1,22 @@
+package jobs
+
+import (
+	"log"
+	"technical-test-backend/services"
+	"technical-test-backend/utils"
+	"time"
+)
+
+// startPriceScheduleJob - Terapkan & akhiri jadwal harga jual seller yang sudah jatuh tempo.
+// Interval diatur dengan PRICE_SCHEDULE_INTERVAL (default 1m, "0" untuk mematikan).
+func startPriceScheduleJob() {
+	priceService := services.SellerPriceService{}
+	runEvery("price-schedule", utils.EnvDuration("PRICE_SCHEDULE_INTERVAL", time.Minute), func() error {
+		result, err := priceService.ApplyDueSchedules(time.Now())
+		if result.Started+result.Ended+result.Failed > 0 {
+			log.Printf("[job] price-schedule: %d started, %d ended, %d failed",
+				result.Started, result.Ended, result.Failed)
+		}
+		return err
+	})
+}
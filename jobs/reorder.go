@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"log"
+	"strings"
+	"technical-test-backend/services"
+	"technical-test-backend/utils"
+	"time"
+)
+
+// startReorderJob - Job harian: hitung ulang reorder point & tandai produk yang
+// diproyeksikan habis sebelum barang dari supplier tiba.
+// Interval diatur dengan REORDER_JOB_INTERVAL (default 24h, "0" untuk mematikan).
+func startReorderJob() {
+	reorderService := services.ReorderService{}
+	runEvery("reorder-check", utils.EnvDuration("REORDER_JOB_INTERVAL", 24*time.Hour), func() error {
+		result, err := reorderService.Recalculate(services.LoadReorderSettings())
+		if err != nil {
+			return err
+		}
+		log.Printf("[job] reorder-check: %d product(s) evaluated, %d projected to run out within lead time",
+			result.ProductsEvaluated, result.Flagged)
+		if len(result.NewlyFlagged) > 0 {
+			log.Printf("[job] reorder-check: newly flagged: %s", strings.Join(result.NewlyFlagged, ", "))
+		}
+		return nil
+	})
+}
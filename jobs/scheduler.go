@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"log"
+	"time"
+)
+
+// Start - Jalankan semua background job. Dipanggil sekali dari main setelah database siap.
+func Start() {
+	startReorderJob()
+	startPriceScheduleJob()
+	startTokenCleanupJob()
+	startSigningKeyRotationJob()
+	startAuditSpoolJob()
+}
+
+// runEvery - Jalankan fn segera, lalu ulangi setiap interval di goroutine terpisah.
+// interval <= 0 berarti job dimatikan.
+func runEvery(name string, interval time.Duration, fn func() error) {
+	if interval <= 0 {
+		log.Printf("[job] %s disabled", name)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			runSafely(name, fn)
+			<-ticker.C
+		}
+	}()
+	log.Printf("[job] %s scheduled every %s", name, interval)
+}
+
+// runSafely - Error / panic di satu eksekusi job tidak boleh mematikan server
+func runSafely(name string, fn func() error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[job] %s panic: %v", name, r)
+		}
+	}()
+
+	start := time.Now()
+	if err := fn(); err != nil {
+		log.Printf("[job] %s failed: %v", name, err)
+		return
+	}
+	log.Printf("[job] %s finished in %s", name, time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"log"
+	"technical-test-backend/services"
+	"technical-test-backend/utils"
+	"time"
+)
+
+// startSigningKeyRotationJob - Buat kunci JWT pertama, rotasi kunci yang sudah melewati JWT_KEY_ROTATION_INTERVAL,
+// dan hapus kunci lama yang sudah tidak diterima. Interval cek diatur dengan JWT_KEY_CHECK_INTERVAL (default 1h, "0" untuk mematikan).
+func startSigningKeyRotationJob() {
+	authService := services.AuthService{}
+	runEvery("signing-key-rotation", utils.EnvDuration("JWT_KEY_CHECK_INTERVAL", time.Hour), func() error {
+		rotated, err := authService.RotateSigningKeys(time.Now())
+		if rotated {
+			log.Printf("[job] signing-key-rotation: new JWT signing key activated")
+		}
+		return err
+	})
+}
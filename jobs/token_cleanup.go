@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"log"
+	"technical-test-backend/services"
+	"technical-test-backend/utils"
+	"time"
+)
+
+// startTokenCleanupJob - Hapus refresh token, denylist jti, token email (reset / verifikasi), dan state login OIDC yang sudah kadaluarsa.
+// Interval diatur dengan TOKEN_CLEANUP_INTERVAL (default 1h, "0" untuk mematikan).
+func startTokenCleanupJob() {
+	authService := services.AuthService{}
+	runEvery("token-cleanup", utils.EnvDuration("TOKEN_CLEANUP_INTERVAL", time.Hour), func() error {
+		purged, err := authService.PurgeExpiredTokens(time.Now())
+		if purged > 0 {
+			log.Printf("[job] token-cleanup: %d expired tokens purged", purged)
+		}
+		return err
+	})
+}
@@ -0,0 +1,49 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileMailer - Simpan setiap email sebagai file .eml di Dir (development / debugging)
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+// Send - Tulis email ke <Dir>/<timestamp>-<penerima>.eml
+func (m *FileMailer) Send(msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return fmt.Errorf("mailer: create %s: %w", m.Dir, err)
+	}
+
+	now := time.Now()
+	safeTo := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_", " ", "_", "<", "", ">", "").Replace(msg.To)
+	path := filepath.Join(m.Dir, fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405.000000000"), safeTo))
+	if err := os.WriteFile(path, buildMIME(m.From, msg, now), 0o644); err != nil {
+		return fmt.Errorf("mailer: write %s: %w", path, err)
+	}
+	log.Printf("[mailer] email to %s saved to %s", msg.To, path)
+	return nil
+}
+
+// LogMailer - Tulis email ke log aplikasi, tidak ada yang benar-benar dikirim
+type LogMailer struct {
+	From string
+}
+
+// Send - Log penerima, subjek, dan isi email
+func (m *LogMailer) Send(msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	log.Printf("[mailer] from=%q to=%q subject=%q\n%s", m.From, msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,73 @@
+// Package mailer mengirim email transaksional (reset password, verifikasi email).
+// Driver dipilih dengan MAIL_DRIVER: smtp, file, atau log (default, untuk development).
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"technical-test-backend/utils"
+)
+
+// Message - Email teks biasa untuk satu penerima
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer - Pengirim email, implementasi: SMTPMailer, FileMailer, LogMailer
+type Mailer interface {
+	Send(msg Message) error
+}
+
+var (
+	defaultMailer Mailer
+	once          sync.Once
+)
+
+// Default - Mailer dari environment, dibuat sekali saat pertama dipakai
+func Default() Mailer {
+	once.Do(func() {
+		defaultMailer = FromEnv()
+	})
+	return defaultMailer
+}
+
+// FromEnv - Bangun mailer sesuai MAIL_DRIVER
+//
+//	smtp: SMTP_HOST, SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD
+//	file: MAIL_FILE_DIR (default storage/mail), satu file .eml per email
+//	log:  isi email ditulis ke log aplikasi
+func FromEnv() Mailer {
+	from := utils.EnvString("MAIL_FROM", "Inventory App <no-reply@localhost>")
+	switch strings.ToLower(utils.EnvString("MAIL_DRIVER", "log")) {
+	case "smtp":
+		return &SMTPMailer{
+			Host:     utils.EnvString("SMTP_HOST", "localhost"),
+			Port:     utils.EnvInt("SMTP_PORT", 587),
+			Username: utils.EnvString("SMTP_USERNAME", ""),
+			Password: utils.EnvString("SMTP_PASSWORD", ""),
+			From:     from,
+		}
+	case "file":
+		return &FileMailer{Dir: utils.EnvString("MAIL_FILE_DIR", "storage/mail"), From: from}
+	case "log":
+		return &LogMailer{From: from}
+	default:
+		log.Printf("[mailer] unknown MAIL_DRIVER, falling back to log")
+		return &LogMailer{From: from}
+	}
+}
+
+// validate - Penerima & subjek wajib ada, tanpa CR/LF (mencegah header injection)
+func (m Message) validate() error {
+	if m.To == "" {
+		return fmt.Errorf("mailer: recipient is required")
+	}
+	if strings.ContainsAny(m.To+m.Subject, "\r\n") {
+		return fmt.Errorf("mailer: recipient and subject must not contain line breaks")
+	}
+	return nil
+}
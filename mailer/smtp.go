@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPMailer - Kirim lewat server SMTP. STARTTLS dipakai otomatis jika server mendukung.
+// Tanpa Username, email dikirim tanpa AUTH (contoh: fake SMTP server lokal seperti MailHog / Mailpit).
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Send - Kirim satu email
+func (m *SMTPMailer) Send(msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	from, err := envelopeAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("mailer: invalid MAIL_FROM: %w", err)
+	}
+	to, err := envelopeAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("mailer: invalid recipient: %w", err)
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+	if err := smtp.SendMail(addr, auth, from, []string{to}, buildMIME(m.From, msg, time.Now())); err != nil {
+		return fmt.Errorf("mailer: smtp send to %s: %w", to, err)
+	}
+	return nil
+}
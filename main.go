@@ -3,7 +3,10 @@ package main
 import (
 	"log"
 	"os"
+	"strings"
 	"technical-test-backend/database"
+	"technical-test-backend/jobs"
+	"technical-test-backend/middlewares"
 	"technical-test-backend/routes"
 
 	"github.com/gin-gonic/gin"
@@ -22,6 +25,16 @@ import (
 
 // @host            localhost:8080
 // @BasePath        /
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 Access token: "Bearer <token>"
+
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+// @description                 API key seller (integrasi sistem), hanya untuk endpoint dengan scope yang sesuai
 func main() {
 	// Load Environment Variables
 	if err := godotenv.Load(); err != nil {
@@ -31,18 +44,34 @@ func main() {
 	// Connect & Migrate Database
 	database.ConnectDatabase()
 
+	// Background jobs (cek reorder harian, dll)
+	jobs.Start()
+
 	// Setup Gin Engine
 	r := gin.Default()
 	
 	// Disable automatic redirect for trailing slash
 	r.RedirectTrailingSlash = false
 
+	// IP klien (rate limit login per IP, audit log, API key last_used_ip) hanya dari X-Forwarded-For
+	// jika request datang dari proxy di TRUSTED_PROXIES (dipisah koma, IP / CIDR). Default: tidak ada,
+	// header X-Forwarded-For dari klien diabaikan dan IP koneksi yang dipakai.
+	var trustedProxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatal("TRUSTED_PROXIES tidak valid: ", err)
+	}
+
 	// CORS Middleware
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, X-API-Key")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type, X-Request-ID")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -52,6 +81,9 @@ func main() {
 		c.Next()
 	})
 
+	// Request ID & audit trail untuk semua request yang mengubah data di endpoint ber-permission
+	r.Use(middlewares.RequestID(), middlewares.AuditTrail())
+
 	// Panggil Routing dari folder routes/api.go
 	routes.SetupRouter(r)
 
@@ -0,0 +1,182 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// auditBodyLimit - Body request lebih besar dari ini tidak disalin ke audit log
+const auditBodyLimit = 64 << 10
+
+// Field body yang nilainya disensor di audit log (nama field mengandung salah satu kata ini)
+var auditSecretFields = []string{"password", "secret", "token", "api_key"}
+
+// RequestID - ID unik per request untuk korelasi log & audit log
+// Header X-Request-ID dari klien / reverse proxy dipakai jika formatnya wajar, selain itu dibuat UUID baru.
+// ID dikembalikan di header response X-Request-ID.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader("X-Request-ID")
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		c.Set("requestID", id)
+		c.Header("X-Request-ID", id)
+		c.Next()
+	}
+}
+
+// validRequestID - Maks 64 karakter huruf, angka, dan . _ : -
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune("._:-", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// AuditTrail - Catat request yang mengubah data (POST/PUT/PATCH/DELETE) di endpoint yang dilindungi
+// RequirePermission dan berhasil (status < 400) sebagai API_REQUEST di audit log.
+// Service yang sudah mencatat perubahan lengkap (before/after) lewat AuditContext.Record tidak dicatat dua kali.
+// Dipasang global setelah RequestID, AuditContext tersedia di context dengan key "audit".
+func AuditTrail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		audit := &services.AuditContext{IP: c.ClientIP(), RequestID: c.GetString("requestID")}
+		c.Set("audit", audit)
+
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+
+		body := captureRequestBody(c)
+		c.Next()
+
+		permissions, guarded := c.Get("requiredPermissions")
+		if !guarded || c.Writer.Status() >= http.StatusBadRequest || audit.Recorded() {
+			return
+		}
+		audit.SetActor(c.GetString("userID"))
+
+		data := map[string]interface{}{
+			"method":      c.Request.Method,
+			"route":       c.FullPath(),
+			"path":        c.Request.URL.Path,
+			"status":      c.Writer.Status(),
+			"permissions": permissions,
+		}
+		if len(c.Params) > 0 {
+			params := make(map[string]string, len(c.Params))
+			for _, p := range c.Params {
+				params[p.Key] = p.Value
+			}
+			data["params"] = params
+		}
+		if query := c.Request.URL.RawQuery; query != "" {
+			data["query"] = query
+		}
+		if body != nil {
+			data["body"] = body
+		}
+
+		// Response sudah terkirim: entry yang gagal ditulis masuk spool & di-replay job audit-spool,
+		// sampai di sini hanya jika spool juga gagal (sudah dicatat sebagai ALERT lengkap dengan isi entry)
+		targetType, targetID := auditTarget(c)
+		if err := services.RecordAPIRequest(audit, targetType, targetID, data); err != nil {
+			log.Printf("[audit] ALERT: %s %s tanpa audit log: %v", c.Request.Method, c.Request.URL.Path, err)
+		}
+	}
+}
+
+// captureRequestBody - Salin body JSON untuk audit log (field rahasia disensor), body tetap bisa dibaca handler.
+// nil untuk body kosong / bukan JSON (misal upload file import).
+func captureRequestBody(c *gin.Context) interface{} {
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return nil
+	}
+	buf, err := io.ReadAll(io.LimitReader(c.Request.Body, auditBodyLimit+1))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(buf), c.Request.Body))
+	if err != nil || len(buf) == 0 {
+		return nil
+	}
+	if len(buf) > auditBodyLimit {
+		return "(body lebih dari 64KB, tidak disalin)"
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(buf, &body); err != nil {
+		return nil
+	}
+	return redactSecrets(body)
+}
+
+// redactSecrets - Ganti nilai field rahasia (password, token, ...) di semua level dengan "[REDACTED]"
+func redactSecrets(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			lower := strings.ToLower(key)
+			secret := false
+			for _, word := range auditSecretFields {
+				if strings.Contains(lower, word) {
+					secret = true
+					break
+				}
+			}
+			if secret {
+				v[key] = "[REDACTED]"
+			} else {
+				v[key] = redactSecrets(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactSecrets(item)
+		}
+	}
+	return value
+}
+
+// auditTarget - Jenis entitas dari route dalam bentuk tunggal seperti entry dari service
+// (/products/:id -> product, /seller/products/:id -> seller_product, /purchase-orders -> purchase_order)
+// dan ID entitas dari parameter route pertama yang berupa UUID
+func auditTarget(c *gin.Context) (string, *uuid.UUID) {
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(c.FullPath(), "/"), "/") {
+		if segment == "" || strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	targetType := ""
+	if len(segments) > 0 {
+		targetType = segments[0]
+		if targetType == "seller" && len(segments) > 1 {
+			targetType = "seller_" + segments[1]
+		}
+	}
+	targetType = strings.TrimSuffix(strings.ReplaceAll(targetType, "-", "_"), "s")
+
+	for _, p := range c.Params {
+		if id, err := uuid.Parse(p.Value); err == nil {
+			return targetType, &id
+		}
+	}
+	return targetType, nil
+}
@@ -1,19 +1,28 @@
 package middlewares
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
-	"os"
 	"strings"
+	"technical-test-backend/services"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
 // AuthMiddleware - Middleware untuk memvalidasi JWT token pada setiap request
-// Fungsi: Extract token dari header -> Validasi token -> Simpan user info ke context
-func AuthMiddleware() gin.HandlerFunc {
+// Fungsi: Extract token dari header -> Validasi token & denylist jti -> Cek status akun -> Simpan user info ke context
+// Parameter: apiKeyScopes - scope API key yang diterima endpoint ini sebagai pengganti access token (header X-API-Key).
+// Kosong = endpoint hanya bisa diakses dengan access token.
+// Contoh: AuthMiddleware(models.APIKeyScopeCatalogRead) -> access token atau API key dengan scope catalog:read
+func AuthMiddleware(apiKeyScopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// 0. Tanpa Authorization header, X-API-Key dipakai sebagai kredensial (integrasi sistem seller)
+		if c.GetHeader("Authorization") == "" && c.GetHeader("X-API-Key") != "" {
+			authenticateAPIKey(c, apiKeyScopes)
+			return
+		}
+
 		// 1. Ambil Authorization header dari request
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -32,41 +41,108 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		// 3. Parse dan validasi JWT token dengan secret key
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Validasi signing method harus HMAC
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("metode signing tidak valid")
+		// 3-4. Parse & validasi JWT (signature, expiry), lalu cek jti di denylist (logout)
+		claims, err := services.ParseAccessToken(tokenString)
+		if err != nil {
+			message := "Token tidak valid atau kadaluarsa"
+			if errors.Is(err, services.ErrTokenRevoked) {
+				message = "Token sudah dicabut, silakan login ulang"
 			}
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
-
-		// 4. Cek apakah token valid dan tidak expired
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token tidak valid atau kadaluarsa"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": message})
 			c.Abort()
 			return
 		}
 
-		// 5. Extract claims (user info) dari token dan simpan ke gin context
-		// Data ini bisa diakses oleh handler berikutnya
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			c.Set("userID", claims["sub"])  // User ID dari claim "sub"
-			c.Set("role", claims["role"])   // Role dari claim "role"
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token invalid claims"})
-			c.Abort()
+		// 5. Tolak akun yang ditangguhkan / dinonaktifkan walau token masih berlaku
+		if !checkUserActive(c, claims.Subject) {
 			return
 		}
 
-		// 6. Lanjutkan ke handler berikutnya
+		// 6. Simpan claims (user info) ke gin context
+		// Data ini bisa diakses oleh handler berikutnya
+		c.Set("userID", claims.Subject)          // User ID dari claim "sub"
+		c.Set("role", claims.Role)               // Role dari claim "role"
+		c.Set("permissions", claims.Permissions) // Permission dari claim "perms"
+		c.Set("jti", claims.ID)                  // ID token untuk logout
+		if claims.ExpiresAt != nil {
+			c.Set("tokenExpiresAt", claims.ExpiresAt.Time)
+		}
+
+		// 7. Lanjutkan ke handler berikutnya
 		c.Next()
 	}
 }
 
+// checkUserActive - Tolak (abort) request dari akun yang ditangguhkan / dinonaktifkan / sudah tidak ada
+func checkUserActive(c *gin.Context, userID string) bool {
+	err := services.CheckUserActive(userID)
+	if err == nil {
+		return true
+	}
+	var statusErr *services.AccountStatusError
+	switch {
+	case errors.As(err, &statusErr):
+		c.JSON(http.StatusForbidden, gin.H{"error": statusErr.Error(), "status": statusErr.Status})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User tidak ditemukan, silakan login ulang"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+	c.Abort()
+	return false
+}
+
+// authenticateAPIKey - Autentikasi header X-API-Key untuk endpoint yang menerima salah satu scope.
+// Permission di context = permission scope key yang masih dimiliki role pemilik.
+func authenticateAPIKey(c *gin.Context, scopes []string) {
+	// 1. Endpoint tanpa scope (profil, password, kelola API key, ...) hanya untuk access token
+	if len(scopes) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Endpoint ini tidak bisa diakses dengan API key"})
+		c.Abort()
+		return
+	}
+
+	// 2. Validasi key (hash cocok, belum dicabut / kadaluarsa)
+	principal, err := services.AuthenticateAPIKey(c.GetHeader("X-API-Key"), c.ClientIP())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAPIKey) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key tidak valid, dicabut, atau kadaluarsa"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		c.Abort()
+		return
+	}
+
+	// 3. Key harus memiliki salah satu scope endpoint
+	if !principal.HasScope(scopes...) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Scope API key tidak mencukupi", "required_scope": scopes})
+		c.Abort()
+		return
+	}
+
+	// 4. Akun pemilik harus aktif, sama seperti access token
+	userID := principal.UserID.String()
+	if !checkUserActive(c, userID) {
+		return
+	}
+
+	// 5. Simpan user info ke context, audit log mencatat key yang dipakai
+	c.Set("userID", userID)
+	c.Set("role", principal.Role)
+	c.Set("permissions", principal.Permissions)
+	c.Set("apiKeyID", principal.KeyID.String())
+	if audit, ok := c.Value("audit").(*services.AuditContext); ok {
+		audit.APIKeyID = &principal.KeyID
+	}
+	c.Next()
+}
+
 // RoleMiddleware - Middleware untuk authorization berdasarkan role user
 // Parameter: allowedRoles - daftar role yang diizinkan akses endpoint
 // Contoh: RoleMiddleware("Admin", "Seller") -> hanya Admin dan Seller bisa akses
+//
+// Deprecated: gunakan RequirePermission agar akses bisa diatur lewat role & permission tanpa ubah kode.
 func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 1. Ambil role yang sudah di-set oleh AuthMiddleware sebelumnya
@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"net/http"
+	"technical-test-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission - Middleware authorization berdasarkan permission (claim "perms" di access token)
+// Parameter: permissions - semua permission ini wajib dimiliki role user
+// Contoh: RequirePermission(models.PermProductsWrite) -> hanya role dengan products.write bisa akses
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1. Ambil permission yang sudah di-set oleh AuthMiddleware sebelumnya
+		value, exists := c.Get("permissions")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Permission user tidak ditemukan"})
+			c.Abort()
+			return
+		}
+		granted, _ := value.([]string)
+
+		// 2. Semua permission yang diminta harus dimiliki, jika tidak return 403 Forbidden
+		for _, permission := range permissions {
+			if !services.HasPermission(granted, permission) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Anda tidak memiliki akses (Forbidden)", "required_permission": permission})
+				c.Abort()
+				return
+			}
+		}
+
+		// 3. Permission lengkap, lanjutkan ke handler
+		// (endpoint ber-permission yang mengubah data dicatat AuditTrail)
+		c.Set("requiredPermissions", permissions)
+		c.Next()
+	}
+}
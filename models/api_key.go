@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scope API key seller. Endpoint yang menerima API key menyebut scope-nya di routes (AuthMiddleware(scope)),
+// endpoint lain hanya bisa diakses dengan access token.
+const (
+	APIKeyScopeCatalogRead   = "catalog:read"   // Lihat produk master, etalase, histori & jadwal harga
+	APIKeyScopeCatalogWrite  = "catalog:write"  // Tambah / ubah / hapus etalase, jadwal harga
+	APIKeyScopeOrdersRead    = "orders:read"    // Lihat order masuk
+	APIKeyScopeOrdersConfirm = "orders:confirm" // Konfirmasi order masuk
+)
+
+// APIKeyScopeDefinition - Scope beserta permission role yang dibutuhkan pemilik key.
+// Permission request dengan API key = permission scope key yang masih dimiliki role pemilik.
+type APIKeyScopeDefinition struct {
+	Name        string
+	Permission  string
+	Description string
+}
+
+// APIKeyScopes - Daftar scope yang bisa dipilih saat membuat API key
+var APIKeyScopes = []APIKeyScopeDefinition{
+	{APIKeyScopeCatalogRead, PermSellerListings, "Lihat produk master, etalase, histori & jadwal harga"},
+	{APIKeyScopeCatalogWrite, PermSellerListings, "Tambah, ubah, hapus etalase dan jadwal harga"},
+	{APIKeyScopeOrdersRead, PermSellerOrders, "Lihat order masuk"},
+	{APIKeyScopeOrdersConfirm, PermSellerOrders, "Konfirmasi order masuk"},
+}
+
+// APIKey - Kredensial seller untuk integrasi sistem (ERP / POS) lewat header X-API-Key.
+// Hanya hash SHA-256 yang disimpan, Prefix (awal key) untuk dikenali pemilik di daftar key.
+// Scopes dipisah spasi. RevokedAt / ExpiresAt terlewati = key ditolak.
+type APIKey struct {
+	Base
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name       string    `gorm:"type:varchar(100);not null"`
+	Prefix     string    `gorm:"type:varchar(16);not null"`
+	KeyHash    string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Scopes     string    `gorm:"type:varchar(255);not null"`
+	ExpiresAt  *time.Time
+	RevokedAt  *time.Time
+	LastUsedAt *time.Time
+	LastUsedIP string `gorm:"type:varchar(64)"`
+
+	User User `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
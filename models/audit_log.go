@@ -0,0 +1,64 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Jenis aksi yang dicatat di audit log
+const (
+	AuditLoginLockout = "LOGIN_LOCKOUT" // Akun / IP dikunci sementara karena terlalu banyak login gagal
+	AuditLoginUnlock  = "LOGIN_UNLOCK"  // Admin membuka kunci login
+
+	AuditTwoFactorEnabled         = "TWO_FACTOR_ENABLED"         // User mengaktifkan 2FA
+	AuditTwoFactorDisabled        = "TWO_FACTOR_DISABLED"        // User menonaktifkan 2FA
+	AuditTwoFactorReset           = "TWO_FACTOR_RESET"           // Admin mereset 2FA user (perangkat hilang)
+	AuditRecoveryCodeUsed         = "RECOVERY_CODE_USED"         // Login memakai recovery code
+	AuditRecoveryCodesRegenerated = "RECOVERY_CODES_REGENERATED" // Recovery code lama diganti
+
+	AuditRoleCreated = "ROLE_CREATED" // Admin membuat role baru
+	AuditRoleUpdated = "ROLE_UPDATED" // Admin mengubah role / permission role
+	AuditRoleDeleted = "ROLE_DELETED" // Admin menghapus role
+
+	AuditUserSuspended   = "USER_SUSPENDED"   // Admin menangguhkan akun
+	AuditUserReactivated = "USER_REACTIVATED" // Admin mengaktifkan kembali akun
+	AuditUserDeactivated = "USER_DEACTIVATED" // Admin menonaktifkan akun
+	AuditUserAnonymized  = "USER_ANONYMIZED"  // Admin menghapus akun (data pribadi dianonimkan)
+	AuditUserUpdated     = "USER_UPDATED"     // Admin mengubah nama / email / role user
+
+	AuditProductUpdated       = "PRODUCT_UPDATED"        // Admin mengubah produk master (harga, SKU, kategori, ...)
+	AuditProductDeleted       = "PRODUCT_DELETED"        // Admin menghapus produk master
+	AuditProductTypeUpdated   = "PRODUCT_TYPE_UPDATED"   // Admin mengganti nama kategori
+	AuditProductTypeDeleted   = "PRODUCT_TYPE_DELETED"   // Admin menghapus kategori
+	AuditProductTypeMerged    = "PRODUCT_TYPE_MERGED"    // Admin memindahkan produk ke kategori lain / merge kategori
+	AuditSellerProductUpdated = "SELLER_PRODUCT_UPDATED" // Seller mengubah harga jual / status etalase
+	AuditOrderConfirmed       = "ORDER_CONFIRMED"        // Seller mengonfirmasi order (stok keluar)
+	AuditOrderCancelled       = "ORDER_CANCELLED"        // Order dibatalkan
+
+	AuditAPIKeyCreated = "API_KEY_CREATED" // Seller membuat API key
+	AuditAPIKeyRevoked = "API_KEY_REVOKED" // API key dicabut pemiliknya
+
+	AuditOIDCUserCreated    = "OIDC_USER_CREATED"    // User baru dibuat dari login OIDC pertama
+	AuditOIDCIdentityLinked = "OIDC_IDENTITY_LINKED" // Akun IdP dihubungkan ke user lama (email terverifikasi sama)
+	AuditOIDCRoleSynced     = "OIDC_ROLE_SYNCED"     // Role user diubah mengikuti grup di IdP
+
+	// Request lain yang mengubah data di endpoint ber-permission (tanpa before/after),
+	// Data berisi method, route, status, parameter, dan body request (field rahasia disensor)
+	AuditAPIRequest = "API_REQUEST"
+)
+
+// AuditLog - Catatan kejadian penting untuk keamanan & penelusuran (append-only,
+// UPDATE / DELETE ditolak trigger database). ActorID nil = dilakukan sistem.
+// Data berisi detail terstruktur (JSON) sesuai Action, perubahan data sebagai {"before": {...}, "after": {...}}.
+// RequestID = header X-Request-ID dari request yang memicu kejadian (kosong untuk job / sistem).
+// APIKeyID diisi jika pelaku memakai API key (X-API-Key), bukan access token.
+type AuditLog struct {
+	Base
+	ActorID    *uuid.UUID `gorm:"type:uuid;index"`
+	APIKeyID   *uuid.UUID `gorm:"type:uuid;index"`
+	Action     string     `gorm:"type:varchar(50);not null;index"`
+	TargetType string     `gorm:"type:varchar(50);index"`
+	TargetID   *uuid.UUID `gorm:"type:uuid;index"`
+	IP         string     `gorm:"type:varchar(64)"`
+	RequestID  string     `gorm:"type:varchar(64);index"`
+	Data       string     `gorm:"type:jsonb;not null;default:'{}'"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Alasan refresh token / access token dicabut
+const (
+	TokenRevokedRotated       = "ROTATED"        // Sudah ditukar dengan refresh token baru
+	TokenRevokedLogout        = "LOGOUT"         // User logout
+	TokenRevokedReuseDetected = "REUSE_DETECTED" // Refresh token lama dipakai ulang, satu sesi dicabut
+	TokenRevokedPasswordReset = "PASSWORD_RESET" // Password direset lewat email, semua sesi dicabut
+	TokenRevokedDisabled      = "DISABLED"       // Akun ditangguhkan / dinonaktifkan / dihapus admin
+)
+
+// RefreshToken - Refresh token yang di-rotate setiap dipakai. Hanya hash SHA-256 yang disimpan.
+// Semua token hasil rotasi dari satu login berbagi FamilyID (satu sesi / perangkat).
+type RefreshToken struct {
+	Base
+	UserID        uuid.UUID `gorm:"type:uuid;not null;index"`
+	FamilyID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	TokenHash     string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt     time.Time `gorm:"not null;index"`
+	RevokedAt     *time.Time
+	RevokedReason string     `gorm:"type:varchar(30)"`
+	ReplacedByID  *uuid.UUID `gorm:"type:uuid"` // Token pengganti hasil rotasi
+
+	// Info klien saat token diterbitkan
+	IP        string `gorm:"type:varchar(64)"`
+	UserAgent string `gorm:"type:varchar(255)"`
+
+	User User `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// RevokedToken - Denylist jti access token yang dicabut sebelum kadaluarsa.
+// Baris dihapus job pembersihan setelah ExpiresAt (token sudah tidak valid dengan sendirinya).
+type RevokedToken struct {
+	Base
+	JTI       string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	Reason    string    `gorm:"type:varchar(30)"`
+}
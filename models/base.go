@@ -4,10 +4,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Base struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
-}
\ No newline at end of file
+	DeletedAt gorm.DeletedAt `gorm:"index"` // Soft delete: row tetap ada untuk histori transaksi
+}
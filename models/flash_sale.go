@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status flash sale (diturunkan dari waktu, tidak disimpan)
+const (
+	FlashSaleScheduled = "SCHEDULED"
+	FlashSaleRunning   = "RUNNING"
+	FlashSaleEnded     = "ENDED"
+)
+
+// FlashSale - Kampanye promo dengan jendela waktu, dibuat oleh admin
+type FlashSale struct {
+	Base
+	Name        string     `gorm:"type:varchar(100);not null"`
+	Description string     `gorm:"type:text"`
+	StartAt     time.Time  `gorm:"not null;index"`
+	EndAt       time.Time  `gorm:"not null;index"`
+	StoppedAt   *time.Time // Dihentikan admin sebelum EndAt semula
+	CreatedByID *uuid.UUID `gorm:"type:uuid"`
+
+	Items []FlashSaleItem `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// Status - SCHEDULED / RUNNING / ENDED pada waktu now
+func (f FlashSale) Status(now time.Time) string {
+	switch {
+	case now.Before(f.StartAt):
+		return FlashSaleScheduled
+	case now.Before(f.EndAt):
+		return FlashSaleRunning
+	}
+	return FlashSaleEnded
+}
+
+// FlashSaleItem - Etalase seller yang ikut flash sale.
+// Harga promo tetap (PromoPrice) atau persentase diskon dari harga jual (DiscountPercent).
+// QuotaSold dinaikkan secara atomic saat order dibuat dan dikembalikan saat order dibatalkan.
+type FlashSaleItem struct {
+	Base
+	FlashSaleID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	SellerProductID uuid.UUID `gorm:"type:uuid;not null;index"`
+	PromoPrice      *float64  `gorm:"type:decimal(15,2)"`
+	DiscountPercent *float64  `gorm:"type:decimal(5,2)"`
+	Quota           int       `gorm:"not null"`
+	QuotaSold       int       `gorm:"not null;default:0"`
+
+	SellerProduct SellerProduct `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
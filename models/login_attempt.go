@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginAttempt - Penghitung login gagal per key ("account:<email>" / "ip:<alamat>")
+// untuk LOGIN_ATTEMPT_STORE=postgres, dipakai bersama oleh semua instance aplikasi.
+// Baris dihapus permanen saat login berhasil, di-unlock admin, atau sudah usang.
+type LoginAttempt struct {
+	Base
+	Key          string    `gorm:"type:varchar(150);not null;uniqueIndex"`
+	Failures     int       `gorm:"not null;default:0"`
+	LastFailedAt time.Time `gorm:"index"`
+	BlockedUntil *time.Time
+	LockedOut    bool `gorm:"not null;default:false"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Jenis notifikasi in-app
+const (
+	NotificationPriceChange         = "MASTER_PRICE_CHANGE"    // Harga modal berubah, etalase seller terdampak
+	NotificationPriceScheduleFailed = "PRICE_SCHEDULE_FAILED"  // Jadwal harga jual tidak bisa diterapkan
+	NotificationNewReview           = "NEW_REVIEW"             // Pembeli mengulas etalase seller
+	NotificationReviewReply         = "REVIEW_REPLY"           // Seller membalas ulasan pembeli
+	NotificationWishlistPriceDrop   = "WISHLIST_PRICE_DROP"    // Harga etalase di wishlist turun
+	NotificationWishlistBackInStock = "WISHLIST_BACK_IN_STOCK" // Produk di wishlist kembali tersedia
+)
+
+// Notification - Notifikasi in-app per user
+// Data berisi detail terstruktur (JSON) sesuai Type, Message versi yang bisa dibaca langsung
+type Notification struct {
+	Base
+	UserID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	Type    string    `gorm:"type:varchar(50);not null;index"`
+	Title   string    `gorm:"type:varchar(150);not null"`
+	Message string    `gorm:"type:text"`
+	Data    string    `gorm:"type:jsonb;not null;default:'{}'"`
+	ReadAt  *time.Time
+
+	User User `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
@@ -0,0 +1,62 @@
+package models
+
+// Permission yang dicek RequirePermission di routes. Format: <resource>.<aksi>
+const (
+	PermProductsWrite        = "products.write"         // Tambah / ubah / hapus / import produk master
+	PermInventoryRead        = "inventory.read"         // Stok menipis, histori & konsistensi stok, export, label, stok gudang
+	PermInventoryWrite       = "inventory.write"        // Mutasi stok, kelola gudang, transfer stok
+	PermProductTypesWrite    = "product_types.write"    // Kelola & merge kategori
+	PermSuppliersManage      = "suppliers.manage"       // Kelola supplier
+	PermPurchaseOrdersManage = "purchase_orders.manage" // Purchase order & job reorder
+	PermFlashSalesManage     = "flash_sales.manage"     // Kelola flash sale
+	PermReportsRead          = "reports.read"           // Laporan penjualan, top produk / seller, saran reorder
+	PermUsersManage          = "users.manage"           // Kelola user, unlock login, reset 2FA
+	PermRolesManage          = "roles.manage"           // Kelola role & mapping permission
+	PermAuditRead            = "audit.read"             // Lihat & export audit log
+	PermReviewsModerate      = "reviews.moderate"       // Moderasi ulasan
+	PermSellerListings       = "seller.listings"        // Etalase, harga & jadwal harga, profil toko (user = seller)
+	PermSellerOrders         = "seller.orders"          // Order masuk, konfirmasi order, balas ulasan
+	PermAPIKeysManage        = "api_keys.manage"        // Buat & cabut API key milik sendiri (integrasi ERP seller)
+	PermOrdersPlace          = "orders.place"           // Belanja: order, batal, riwayat, ulasan, wishlist (user = pembeli)
+	PermDashboardAdmin       = "dashboard.admin"        // Dashboard platform
+	PermDashboardSeller      = "dashboard.seller"       // Dashboard penjualan seller
+	PermDashboardCustomer    = "dashboard.customer"     // Dashboard belanja pelanggan
+)
+
+// Permission - Hak akses yang bisa di-assign ke role (tabel role_permissions)
+type Permission struct {
+	Base
+	Name        string `gorm:"type:varchar(100);not null;uniqueIndex:idx_permissions_name,where:deleted_at IS NULL"`
+	Description string `gorm:"type:varchar(255)"`
+}
+
+// PermissionDefinition - Permission yang dikenal aplikasi beserta role bawaan yang otomatis
+// mendapatkannya saat permission pertama kali dibuat (sinkronisasi saat startup)
+type PermissionDefinition struct {
+	Name         string
+	Description  string
+	DefaultRoles []string
+}
+
+// PermissionDefinitions - Daftar permission yang disinkronkan ke tabel permissions
+var PermissionDefinitions = []PermissionDefinition{
+	{PermProductsWrite, "Tambah, ubah, hapus, dan import produk master", []string{"Admin"}},
+	{PermInventoryRead, "Lihat stok menipis, histori & konsistensi stok, export produk, label barcode, stok gudang", []string{"Admin"}},
+	{PermInventoryWrite, "Catat mutasi stok, kelola gudang, transfer stok antar gudang", []string{"Admin"}},
+	{PermProductTypesWrite, "Kelola dan merge kategori produk", []string{"Admin"}},
+	{PermSuppliersManage, "Kelola supplier", []string{"Admin"}},
+	{PermPurchaseOrdersManage, "Kelola purchase order dan jalankan job reorder", []string{"Admin"}},
+	{PermFlashSalesManage, "Kelola flash sale", []string{"Admin"}},
+	{PermReportsRead, "Lihat laporan penjualan, top produk, top seller, saran reorder", []string{"Admin"}},
+	{PermUsersManage, "Kelola user, unlock login, reset 2FA, suspend / nonaktifkan akun", []string{"Admin"}},
+	{PermRolesManage, "Kelola role dan permission", []string{"Admin"}},
+	{PermAuditRead, "Lihat dan export audit log aksi admin, seller, dan order", []string{"Admin"}},
+	{PermReviewsModerate, "Moderasi ulasan pembeli", []string{"Admin"}},
+	{PermSellerListings, "Kelola etalase, harga jual, jadwal harga, dan profil toko", []string{"Seller"}},
+	{PermSellerOrders, "Lihat & konfirmasi order masuk, balas ulasan", []string{"Seller"}},
+	{PermAPIKeysManage, "Buat, lihat, dan cabut API key untuk integrasi sistem seller", []string{"Seller"}},
+	{PermOrdersPlace, "Buat & batalkan order, riwayat belanja, ulasan, wishlist", []string{"Pelanggan"}},
+	{PermDashboardAdmin, "Dashboard statistik platform", []string{"Admin"}},
+	{PermDashboardSeller, "Dashboard statistik penjualan seller", []string{"Seller"}},
+	{PermDashboardCustomer, "Dashboard statistik belanja pelanggan", []string{"Pelanggan"}},
+}
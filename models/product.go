@@ -1,16 +1,29 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid" 
 )
 
 type Product struct {
 	Base
 	Name          string      `gorm:"type:varchar(100);not null"`
+	SKU           string      `gorm:"column:sku;type:varchar(64);not null;default:'';uniqueIndex:idx_products_sku,where:sku <> '' AND deleted_at IS NULL"` // Wajib, di-generate jika kosong
+	Barcode       *string     `gorm:"type:varchar(14);uniqueIndex:idx_products_barcode,where:barcode IS NOT NULL AND deleted_at IS NULL"` // EAN-8 / UPC-A / EAN-13
 	Stock         int         `gorm:"not null;check:stock >= 0"`
 	Price         float64     `gorm:"type:decimal(10,2);not null"`
 	ProductTypeID uuid.UUID     `gorm:"type:uuid;not null"`
-	ProductType   ProductType `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	ProductType   ProductType `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+
+	// Reorder - SupplierID diisi admin, sisanya dihitung ulang oleh job harian dari penjualan
+	SupplierID       *uuid.UUID `gorm:"type:uuid;index"` // Supplier utama untuk restock
+	Supplier         *Supplier  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	AvgDailySales    float64    `gorm:"type:decimal(12,4);not null;default:0"`
+	SafetyStock      int        `gorm:"not null;default:0"`
+	ReorderPoint     int        `gorm:"not null;default:0"`
+	ReorderFlaggedAt *time.Time // Diisi jika stok diproyeksikan habis sebelum barang dari supplier tiba
+	ReorderUpdatedAt *time.Time
 	
 	CreatedAt     int64       `gorm:"autoCreateTime"`
 }
\ No newline at end of file
@@ -3,5 +3,6 @@ package models
 
 type ProductType struct {
 	Base
-	Name string `gorm:"type:varchar(50);not null;unique"` 
+	// Unique hanya untuk kategori yang belum dihapus (soft delete)
+	Name string `gorm:"type:varchar(50);not null;uniqueIndex:idx_product_types_name,where:deleted_at IS NULL"`
 }
\ No newline at end of file
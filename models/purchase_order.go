@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status purchase order
+const (
+	POStatusDraft             = "DRAFT"
+	POStatusSent              = "SENT"
+	POStatusPartiallyReceived = "PARTIALLY_RECEIVED"
+	POStatusReceived          = "RECEIVED"
+	POStatusClosed            = "CLOSED"
+)
+
+// PurchaseOrder - Pesanan pembelian ke supplier untuk restock gudang
+type PurchaseOrder struct {
+	Base
+	Number      string    `gorm:"type:varchar(30);not null;uniqueIndex"`
+	SupplierID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	WarehouseID uuid.UUID `gorm:"type:uuid;not null"` // Gudang tujuan penerimaan barang
+	Status      string    `gorm:"type:varchar(20);not null;default:'DRAFT';index"`
+	Notes       string    `gorm:"type:text"`
+	ExpectedAt  *time.Time
+	SentAt      *time.Time
+	ReceivedAt  *time.Time
+	ClosedAt    *time.Time
+	CreatedByID *uuid.UUID `gorm:"type:uuid"`
+
+	Supplier  Supplier            `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Warehouse Warehouse           `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Lines     []PurchaseOrderLine `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// PurchaseOrderLine - Baris barang dalam purchase order
+type PurchaseOrderLine struct {
+	Base
+	PurchaseOrderID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	ProductID        uuid.UUID `gorm:"type:uuid;not null;index"`
+	Quantity         int       `gorm:"not null;check:quantity > 0"`
+	ReceivedQuantity int       `gorm:"not null;default:0"`
+	UnitCost         float64   `gorm:"type:decimal(15,2);not null"`
+
+	Product Product `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+}
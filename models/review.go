@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status moderasi ulasan
+const (
+	ReviewVisible = "VISIBLE" // Tampil di marketplace dan dihitung di rating
+	ReviewHidden  = "HIDDEN"  // Disembunyikan admin, tidak dihitung di rating
+)
+
+// Review - Rating & ulasan pembeli untuk etalase seller.
+// Hanya dari transaksi COMPLETED milik pembeli sendiri, satu ulasan per transaksi.
+type Review struct {
+	Base
+	TransactionID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_reviews_transaction,where:deleted_at IS NULL"`
+	SellerProductID uuid.UUID `gorm:"type:uuid;not null;index"`
+	SellerID        uuid.UUID `gorm:"type:uuid;not null;index"` // Disalin dari etalase agar agregasi per seller tidak perlu join
+	UserID          uuid.UUID `gorm:"type:uuid;not null;index"`
+	Rating          int       `gorm:"not null;check:chk_reviews_rating,rating BETWEEN 1 AND 5"`
+	Comment         string    `gorm:"type:text"`
+
+	// Balasan seller (satu balasan, bisa diubah)
+	SellerReply string `gorm:"type:text"`
+	RepliedAt   *time.Time
+
+	// Moderasi admin
+	Status           string     `gorm:"type:varchar(20);not null;default:'VISIBLE';index"`
+	ModerationReason string     `gorm:"type:text"`
+	ModeratedByID    *uuid.UUID `gorm:"type:uuid"`
+	ModeratedAt      *time.Time
+
+	Transaction   Transaction   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	SellerProduct SellerProduct `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	User          User          `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
@@ -1,6 +1,13 @@
 package models
 
+// Role - Kumpulan permission yang dimiliki user. Akses endpoint ditentukan permission, bukan nama role.
+// IsSystem: role bawaan (Admin, Seller, Pelanggan) yang tidak bisa dihapus / diganti nama.
+// Registrable: role boleh dipilih saat registrasi publik.
 type Role struct {
 	Base
-	Name string `gorm:"type:varchar(50);unique;not null"`
-}
\ No newline at end of file
+	Name        string       `gorm:"type:varchar(50);unique;not null"`
+	Description string       `gorm:"type:varchar(255)"`
+	Registrable bool         `gorm:"not null;default:false"`
+	IsSystem    bool         `gorm:"not null;default:false"`
+	Permissions []Permission `gorm:"many2many:role_permissions;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
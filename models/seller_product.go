@@ -6,12 +6,13 @@ import (
 
 type SellerProduct struct {
 	Base
-	SellerID  uuid.UUID `gorm:"type:uuid;not null"`
-	ProductID uuid.UUID `gorm:"type:uuid;not null"` 
+	// Satu etalase per seller per produk (hanya baris yang belum dihapus)
+	SellerID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_seller_products_seller_product,where:deleted_at IS NULL"`
+	ProductID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_seller_products_seller_product,where:deleted_at IS NULL"` 
 	SellingPrice float64 `gorm:"type:decimal(15,2);not null"` 
 	
 	IsActive  bool `gorm:"default:true"`
 
 	Seller  User    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	Product Product `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Product Product `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
 }
\ No newline at end of file
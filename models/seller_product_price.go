@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sumber perubahan harga jual etalase
+const (
+	PriceSourceInitial       = "INITIAL"        // Harga awal saat produk dipajang
+	PriceSourceManual        = "MANUAL"         // Seller mengubah lewat PUT /seller/products/:id
+	PriceSourceSchedule      = "SCHEDULE"       // Jadwal harga mulai berlaku
+	PriceSourceScheduleEnd   = "SCHEDULE_END"   // Jadwal harga berakhir, harga dikembalikan
+	PriceSourceMasterReprice = "MASTER_REPRICE" // Disesuaikan otomatis karena harga modal naik
+)
+
+// SellerProductPriceHistory - Setiap perubahan selling_price sebuah etalase
+type SellerProductPriceHistory struct {
+	Base
+	SellerProductID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	OldPrice        float64    `gorm:"type:decimal(15,2);not null"` // 0 untuk harga awal
+	NewPrice        float64    `gorm:"type:decimal(15,2);not null"`
+	Source          string     `gorm:"type:varchar(20);not null"`
+	ScheduleID      *uuid.UUID `gorm:"type:uuid"`
+	ChangedByID     *uuid.UUID `gorm:"type:uuid"` // nil = sistem (scheduler / perubahan harga modal)
+
+	SellerProduct SellerProduct `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// Status jadwal perubahan harga
+const (
+	PriceScheduleStatusPending   = "PENDING"   // Menunggu start_at
+	PriceScheduleStatusActive    = "ACTIVE"    // Sedang berlaku, menunggu end_at
+	PriceScheduleStatusCompleted = "COMPLETED" // Selesai (tanpa end_at, atau sudah dikembalikan)
+	PriceScheduleStatusCancelled = "CANCELLED"
+	PriceScheduleStatusFailed    = "FAILED" // Tidak bisa diterapkan, lihat FailureReason
+)
+
+// SellerProductPriceSchedule - Perubahan harga jual terjadwal.
+// Tanpa EndAt harga baru berlaku permanen; dengan EndAt harga dikembalikan ke PreviousPrice.
+type SellerProductPriceSchedule struct {
+	Base
+	SellerProductID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	SellingPrice    float64    `gorm:"type:decimal(15,2);not null"`
+	StartAt         time.Time  `gorm:"not null;index"`
+	EndAt           *time.Time `gorm:"index"`
+	Status          string     `gorm:"type:varchar(20);not null;default:'PENDING';index"`
+	PreviousPrice   *float64   `gorm:"type:decimal(15,2)"` // Harga sebelum jadwal berlaku
+	AppliedAt       *time.Time
+	RevertedAt      *time.Time
+	FailureReason   string
+	CreatedByID     uuid.UUID `gorm:"type:uuid;not null"`
+
+	SellerProduct SellerProduct `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OperatingHour - Jam buka toko untuk satu hari (disimpan sebagai JSON di kolom operating_hours)
+// Day: monday..sunday, Open/Close: format HH:MM waktu server
+type OperatingHour struct {
+	Day    string `json:"day"`
+	Open   string `json:"open"`
+	Close  string `json:"close"`
+	Closed bool   `json:"closed"`
+}
+
+// OperatingHours - Jadwal mingguan toko, hari yang tidak tercantum dianggap tutup
+type OperatingHours []OperatingHour
+
+// IsOpenAt - Toko buka pada waktu t. Jadwal kosong = buka setiap saat.
+func (h OperatingHours) IsOpenAt(t time.Time) bool {
+	if len(h) == 0 {
+		return true
+	}
+	day := strings.ToLower(t.Weekday().String())
+	clock := t.Format("15:04")
+	for _, hour := range h {
+		if hour.Day == day {
+			return !hour.Closed && clock >= hour.Open && clock < hour.Close
+		}
+	}
+	return false
+}
+
+// SellerProfile - Profil toko seller untuk halaman publik /shops/:slug
+// Mode libur (vacation) menyembunyikan etalase dari marketplace dan menolak order baru.
+type SellerProfile struct {
+	Base
+	UserID          uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_seller_profiles_user,where:deleted_at IS NULL"`
+	ShopName        string         `gorm:"type:varchar(100);not null"`
+	Slug            string         `gorm:"type:varchar(120);not null;uniqueIndex:idx_seller_profiles_slug,where:deleted_at IS NULL"`
+	Description     string         `gorm:"type:text"`
+	LogoURL         string         `gorm:"type:varchar(255)"`
+	City            string         `gorm:"type:varchar(100)"`
+	OperatingHours  OperatingHours `gorm:"type:jsonb;serializer:json"`
+	VacationMode    bool           `gorm:"not null;default:false"`
+	VacationMessage string         `gorm:"type:varchar(255)"`
+	VacationUntil   *time.Time     // Mode libur berakhir otomatis setelah waktu ini (nil = sampai dimatikan)
+
+	User User `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// OnVacation - Mode libur aktif pada waktu now
+func (p SellerProfile) OnVacation(now time.Time) bool {
+	return p.VacationMode && (p.VacationUntil == nil || now.Before(*p.VacationUntil))
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Algoritma tanda tangan access token (JWT_ALGORITHM)
+const (
+	SigningAlgRS256 = "RS256" // RSA 2048 + SHA-256
+	SigningAlgEdDSA = "EdDSA" // Ed25519
+	SigningAlgHS256 = "HS256" // Legacy: shared secret JWT_SECRET, tanpa JWKS
+)
+
+// SigningKey - Pasangan kunci untuk tanda tangan access token, KID dipasang di header JWT "kid".
+// Kunci aktif (RetiredAt nil, paling baru) dipakai untuk sign. Kunci yang sudah di-rotate tetap
+// dipublikasikan di JWKS sampai ExpiresAt agar access token yang sudah terbit masih bisa diverifikasi.
+type SigningKey struct {
+	Base
+	KID        string     `gorm:"type:varchar(64);not null;uniqueIndex"` // Thumbprint JWK (RFC 7638)
+	Algorithm  string     `gorm:"type:varchar(10);not null"`
+	PrivateKey string     `gorm:"type:text;not null"` // PEM PKCS#8, terenkripsi APP_ENCRYPTION_KEY jika Encrypted, dikosongkan saat di-rotate
+	Encrypted  bool       `gorm:"not null;default:false"`
+	PublicKey  string     `gorm:"type:text;not null"` // PEM PKIX, dipublikasikan di /.well-known/jwks.json
+	RetiredAt  *time.Time // Berhenti dipakai sign (rotasi)
+	ExpiresAt  *time.Time `gorm:"index"` // Tidak diterima & dihapus dari JWKS setelah ini (nil = masih aktif)
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Jenis pergerakan stok gudang
+const (
+	MovementPurchaseReceipt = "PURCHASE_RECEIPT" // Barang masuk dari supplier
+	MovementSale            = "SALE"             // Order dikonfirmasi seller
+	MovementReturn          = "RETURN"           // Barang retur kembali ke gudang
+	MovementAdjustment      = "ADJUSTMENT"       // Koreksi manual oleh admin
+	MovementDamage          = "DAMAGE"           // Barang rusak / hilang
+	MovementStocktake       = "STOCKTAKE"        // Koreksi hasil stock opname
+	MovementTransferOut     = "TRANSFER_OUT"     // Keluar ke gudang lain
+	MovementTransferIn      = "TRANSFER_IN"      // Masuk dari gudang lain
+)
+
+// StockMovement - Ledger setiap perubahan Product.Stock
+// Quantity bertanda (+ masuk, - keluar), BalanceAfter adalah saldo total produk
+// setelah movement, WarehouseBalanceAfter adalah saldo di gudang terkait
+type StockMovement struct {
+	Base
+	ProductID             uuid.UUID  `gorm:"type:uuid;not null;index"`
+	WarehouseID           *uuid.UUID `gorm:"type:uuid;index"` // Nullable hanya untuk data sebelum multi-gudang
+	Type                  string     `gorm:"type:varchar(30);not null;index"`
+	Quantity              int        `gorm:"not null"`
+	BalanceAfter          int        `gorm:"not null"`
+	WarehouseBalanceAfter int        `gorm:"not null;default:0"`
+	Reason                string     `gorm:"type:varchar(255)"`
+	ActorID               *uuid.UUID `gorm:"type:uuid"`
+	ReferenceID           *uuid.UUID `gorm:"type:uuid;index"` // ID transaksi / dokumen sumber
+
+	Product   Product    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Warehouse *Warehouse `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Actor     *User      `gorm:"foreignKey:ActorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// Supplier - Pemasok barang untuk restock gudang
+type Supplier struct {
+	Base
+	Name         string `gorm:"type:varchar(100);not null"`
+	ContactName  string `gorm:"type:varchar(100)"`
+	Email        string `gorm:"type:varchar(100)"`
+	Phone        string `gorm:"type:varchar(30)"`
+	Address      string `gorm:"type:varchar(255)"`
+	LeadTimeDays int    `gorm:"not null;default:7"` // Estimasi hari dari PO dikirim sampai barang diterima
+	IsActive     bool   `gorm:"default:true"`
+}
@@ -19,6 +19,8 @@ type Transaction struct {
 	TotalPrice   float64 `gorm:"type:decimal(15,2)"` 
 	AdminFee     float64 `gorm:"type:decimal(15,2)"` 
 	SellerProfit float64 `gorm:"type:decimal(15,2)"`
+	// Flash sale yang harganya dipakai (nil = harga normal), kuota dikembalikan jika order dibatalkan
+	FlashSaleItemID *uuid.UUID `gorm:"type:uuid;index"`
 
 	User          User          `gorm:"foreignKey:UserID"`
 	SellerProduct SellerProduct `gorm:"foreignKey:SellerProductID"`
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserTwoFactor - TOTP 2FA (RFC 6238) milik user. Secret disimpan terenkripsi (APP_ENCRYPTION_KEY).
+// EnabledAt nil = enrolment belum dikonfirmasi dengan kode pertama, login belum meminta kode.
+type UserTwoFactor struct {
+	Base
+	UserID          uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_user_two_factors_user,where:deleted_at IS NULL"`
+	SecretEncrypted string    `gorm:"type:text;not null"`
+	EnabledAt       *time.Time
+	LastUsedStep    int64 `gorm:"not null;default:0"` // Langkah TOTP terakhir yang dipakai, kode yang sama ditolak
+
+	User User `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// RecoveryCode - Kode cadangan sekali pakai jika perangkat authenticator hilang (hash SHA-256)
+type RecoveryCode struct {
+	Base
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	CodeHash string    `gorm:"type:varchar(64);not null;index"`
+	UsedAt   *time.Time
+
+	User User `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
@@ -1,9 +1,18 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
+// Status akun user
+const (
+	UserStatusActive      = "ACTIVE"      // Normal
+	UserStatusSuspended   = "SUSPENDED"   // Ditangguhkan admin, otomatis aktif lagi setelah SuspendedUntil (nil = sampai diaktifkan)
+	UserStatusDeactivated = "DEACTIVATED" // Akun ditutup (termasuk akun yang dianonimkan)
+)
+
 type User struct {
 	Base
 	Name     string `gorm:"type:varchar(100);not null"`
@@ -11,4 +20,35 @@ type User struct {
 	Password string `gorm:"type:varchar(255);not null"`
 	RoleID   uuid.UUID `gorm:"type:uuid;not null"`
 	Role     Role   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+
+	// Alamat pengiriman, dipakai untuk memilih gudang terdekat saat order dikonfirmasi
+	Address   string   `gorm:"type:varchar(255)"`
+	City      string   `gorm:"type:varchar(100)"`
+	Latitude  *float64 `gorm:"type:decimal(10,7)"`
+	Longitude *float64 `gorm:"type:decimal(10,7)"`
+
+	// Verifikasi email: nil = belum diverifikasi. PendingEmail = email baru dari update profil
+	// yang menunggu dikonfirmasi lewat link verifikasi, Email baru diganti setelah konfirmasi.
+	EmailVerifiedAt *time.Time
+	PendingEmail    string `gorm:"type:varchar(100)"`
+
+	// Status akun. User SUSPENDED / DEACTIVATED ditolak di setiap request (walau token masih berlaku)
+	// dan etalasenya tidak tampil di marketplace. AnonymizedAt = data pribadi sudah dihapus (delete user),
+	// baris user tetap ada agar transaksi & laporan keuangan tidak berubah.
+	Status          string `gorm:"type:varchar(20);not null;default:'ACTIVE';index"`
+	StatusReason    string `gorm:"type:varchar(255)"`
+	StatusChangedAt *time.Time
+	SuspendedUntil  *time.Time
+	AnonymizedAt    *time.Time
+}
+
+// StatusAt - Status yang berlaku pada waktu now (suspensi yang sudah lewat SuspendedUntil = ACTIVE)
+func (u User) StatusAt(now time.Time) string {
+	if u.Status == UserStatusSuspended && u.SuspendedUntil != nil && !now.Before(*u.SuspendedUntil) {
+		return UserStatusActive
+	}
+	if u.Status == "" {
+		return UserStatusActive
+	}
+	return u.Status
 }
\ No newline at end of file
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity - Akun di identity provider OIDC yang terhubung ke user (satu per provider).
+// Dicari berdasarkan Provider + Subject (claim "sub", tetap walau email di IdP berubah).
+type UserIdentity struct {
+	Base
+	UserID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_user_identities_user_provider,where:deleted_at IS NULL"`
+	Provider    string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_user_identities_user_provider,where:deleted_at IS NULL;uniqueIndex:idx_user_identities_subject,where:deleted_at IS NULL"`
+	Subject     string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_subject,where:deleted_at IS NULL"`
+	Email       string    `gorm:"type:varchar(100)"` // Email dari IdP saat login terakhir
+	LastLoginAt *time.Time
+
+	User User `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// OIDCLoginState - Login OIDC yang sedang berjalan (state, nonce, PKCE code verifier), sekali pakai.
+// Hanya hash SHA-256 state yang disimpan, baris dihapus saat callback atau oleh job pembersihan token.
+type OIDCLoginState struct {
+	Base
+	Provider     string    `gorm:"type:varchar(50);not null"`
+	StateHash    string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Nonce        string    `gorm:"type:varchar(64);not null"`
+	CodeVerifier string    `gorm:"type:varchar(128);not null"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tujuan token satu kali pakai yang dikirim lewat email
+const (
+	UserTokenPasswordReset     = "PASSWORD_RESET"     // Link lupa password
+	UserTokenEmailVerification = "EMAIL_VERIFICATION" // Verifikasi email registrasi / email baru
+	UserTokenLoginChallenge    = "LOGIN_CHALLENGE"    // Langkah kedua login (kode 2FA), dikembalikan /auth/login
+)
+
+// UserToken - Token satu kali pakai yang dikirim lewat email (reset password, verifikasi email)
+// atau dikembalikan langsung ke klien (challenge login 2FA).
+// Hanya hash SHA-256 yang disimpan. Token lama dengan tujuan yang sama dihapus saat token baru dibuat.
+type UserToken struct {
+	Base
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Purpose   string    `gorm:"type:varchar(30);not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Email     string    `gorm:"type:varchar(100);not null"` // Alamat tujuan email, untuk verifikasi = alamat yang dikonfirmasi
+	ExpiresAt time.Time `gorm:"not null;index"`
+	UsedAt    *time.Time
+
+	User User `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
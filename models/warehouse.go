@@ -0,0 +1,46 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Warehouse - Lokasi gudang fisik
+// Product.Stock tetap menjadi total stok semua gudang
+type Warehouse struct {
+	Base
+	Code      string   `gorm:"type:varchar(20);not null;uniqueIndex:idx_warehouses_code,where:deleted_at IS NULL"`
+	Name      string   `gorm:"type:varchar(100);not null"`
+	Address   string   `gorm:"type:varchar(255)"`
+	City      string   `gorm:"type:varchar(100)"`
+	Latitude  *float64 `gorm:"type:decimal(10,7)"`
+	Longitude *float64 `gorm:"type:decimal(10,7)"`
+	IsDefault bool     `gorm:"default:false"` // Gudang tujuan jika warehouse_id tidak dikirim
+	IsActive  bool     `gorm:"default:true"`  // Gudang nonaktif tidak dipakai untuk alokasi order
+}
+
+// WarehouseStock - Stok satu produk di satu gudang
+type WarehouseStock struct {
+	Base
+	WarehouseID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_warehouse_stocks_warehouse_product"`
+	ProductID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_warehouse_stocks_warehouse_product;index"`
+	Quantity    int       `gorm:"not null;default:0;check:quantity >= 0"`
+
+	Warehouse Warehouse `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Product   Product   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+}
+
+// StockTransfer - Dokumen perpindahan stok antar gudang
+// Movement TRANSFER_OUT / TRANSFER_IN mereferensikan ID dokumen ini
+type StockTransfer struct {
+	Base
+	FromWarehouseID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	ToWarehouseID   uuid.UUID  `gorm:"type:uuid;not null;index"`
+	ProductID       uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Quantity        int        `gorm:"not null;check:quantity > 0"`
+	Note            string     `gorm:"type:varchar(255)"`
+	ActorID         *uuid.UUID `gorm:"type:uuid"`
+
+	FromWarehouse Warehouse `gorm:"foreignKey:FromWarehouseID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	ToWarehouse   Warehouse `gorm:"foreignKey:ToWarehouseID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Product       Product   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+}
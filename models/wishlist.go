@@ -0,0 +1,17 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// WishlistItem - Etalase seller yang disimpan pembeli untuk dibeli nanti.
+// Pembeli mendapat notifikasi saat harga jual turun atau produk kembali tersedia.
+type WishlistItem struct {
+	Base
+	UserID          uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_wishlist_items_user_listing,where:deleted_at IS NULL"`
+	SellerProductID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_wishlist_items_user_listing,where:deleted_at IS NULL;index"`
+	PriceWhenAdded  float64   `gorm:"type:decimal(15,2);not null"` // Harga jual saat disimpan
+
+	User          User          `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	SellerProduct SellerProduct `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
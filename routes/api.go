@@ -14,13 +14,23 @@ func SetupRouter(r *gin.Engine) {
 	// Setup all routes by entity
 	SetupAuthRoutes(r)
 	SetupProfileRoutes(r)
+	SetupNotificationRoutes(r)
 	SetupProductRoutes(r)
 	SetupProductTypeRoutes(r)
+	SetupWarehouseRoutes(r)
+	SetupSupplierRoutes(r)
+	SetupPurchaseOrderRoutes(r)
 	SetupMarketplaceRoutes(r)
+	SetupFlashSaleRoutes(r)
 	SetupSellerRoutes(r)
+	SetupAPIKeyRoutes(r)
+	SetupShopRoutes(r)
 	SetupCustomerRoutes(r)
 	SetupTransactionRoutes(r)
+	SetupReviewRoutes(r)
 	SetupDashboardRoutes(r)
 	SetupUserRoutes(r)
+	SetupRoleRoutes(r)
+	SetupAuditRoutes(r)
 	SetupReportRoutes(r)
 }
\ No newline at end of file
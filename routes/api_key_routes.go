@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"technical-test-backend/controllers"
+	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAPIKeyRoutes(r *gin.Engine) {
+	// API key seller untuk integrasi sistem (hanya dengan access token, bukan dengan API key)
+	r.GET("/seller/api-keys/scopes",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermAPIKeysManage),
+		controllers.GetAPIKeyScopes,
+	)
+
+	r.GET("/seller/api-keys",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermAPIKeysManage),
+		controllers.GetAPIKeys,
+	)
+
+	r.POST("/seller/api-keys",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermAPIKeysManage),
+		controllers.CreateAPIKey,
+	)
+
+	r.DELETE("/seller/api-keys/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermAPIKeysManage),
+		controllers.RevokeAPIKey,
+	)
+}
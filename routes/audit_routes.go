@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"technical-test-backend/controllers"
+	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAuditRoutes(r *gin.Engine) {
+	// Audit log (read-only, entry ditulis middleware AuditTrail & service)
+	r.GET("/audit-logs",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermAuditRead),
+		controllers.GetAuditLogs,
+	)
+
+	r.GET("/audit-logs/export",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermAuditRead),
+		controllers.ExportAuditLogs,
+	)
+}
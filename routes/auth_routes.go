@@ -2,6 +2,7 @@ package routes
 
 import (
 	"technical-test-backend/controllers"
+	"technical-test-backend/middlewares"
 
 	"github.com/gin-gonic/gin"
 )
@@ -9,5 +10,22 @@ import (
 func SetupAuthRoutes(r *gin.Engine) {
 	r.POST("/auth/register", controllers.Register)
 	r.POST("/auth/login", controllers.Login)
+	r.POST("/auth/login/2fa", controllers.VerifyTwoFactorLogin)
+	r.POST("/auth/login/2fa/setup", controllers.StartLoginTwoFactorSetup)
+	r.POST("/auth/login/2fa/setup/confirm", controllers.ConfirmLoginTwoFactorSetup)
 	r.GET("/auth/roles", controllers.GetRoles)
+	r.POST("/auth/refresh", controllers.RefreshToken)
+	r.POST("/auth/logout", middlewares.AuthMiddleware(), controllers.Logout)
+	r.POST("/auth/forgot-password", controllers.ForgotPassword)
+	r.POST("/auth/reset-password", controllers.ResetPassword)
+	r.POST("/auth/verify-email", controllers.VerifyEmail)
+	r.POST("/auth/resend-verification", middlewares.AuthMiddleware(), controllers.ResendVerification)
+
+	// Login lewat identity provider eksternal (OpenID Connect)
+	r.GET("/auth/oidc/providers", controllers.GetOIDCProviders)
+	r.GET("/auth/oidc/:provider/login", controllers.StartOIDCLogin)
+	r.GET("/auth/oidc/:provider/callback", controllers.OIDCCallback)
+
+	// Public key untuk verifikasi access token oleh service lain
+	r.GET("/.well-known/jwks.json", controllers.GetJWKS)
 }
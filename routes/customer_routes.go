@@ -3,6 +3,7 @@ package routes
 import (
 	"technical-test-backend/controllers"
 	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,7 +11,26 @@ import (
 func SetupCustomerRoutes(r *gin.Engine) {
 	r.GET("/customer/transactions",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Pelanggan"),
+		middlewares.RequirePermission(models.PermOrdersPlace),
 		controllers.GetCustomerTransactions,
 	)
+
+	// Wishlist etalase seller
+	r.GET("/customer/wishlist",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermOrdersPlace),
+		controllers.GetWishlist,
+	)
+
+	r.POST("/customer/wishlist",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermOrdersPlace),
+		controllers.AddToWishlist,
+	)
+
+	r.DELETE("/customer/wishlist/:sellerProductId",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermOrdersPlace),
+		controllers.RemoveFromWishlist,
+	)
 }
@@ -9,7 +9,7 @@ import (
 
 func SetupDashboardRoutes(r *gin.Engine) {
 	// Dashboard endpoint - accessible by all authenticated users
-	// Response berbeda tergantung role (Admin/Seller/Pelanggan)
+	// Response berbeda tergantung permission dashboard.admin / dashboard.seller / dashboard.customer
 	r.GET("/dashboard", 
 		middlewares.AuthMiddleware(), 
 		controllers.GetDashboard,
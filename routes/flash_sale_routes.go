@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"technical-test-backend/controllers"
+	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupFlashSaleRoutes(r *gin.Engine) {
+	// Pembeli: flash sale yang sedang berjalan
+	r.GET("/flash-sales/active",
+		middlewares.AuthMiddleware(),
+		controllers.GetRunningFlashSales,
+	)
+
+	// Admin: kelola kampanye
+	r.GET("/flash-sales",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermFlashSalesManage),
+		controllers.GetFlashSales,
+	)
+
+	r.GET("/flash-sales/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermFlashSalesManage),
+		controllers.GetFlashSaleByID,
+	)
+
+	r.POST("/flash-sales",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermFlashSalesManage),
+		controllers.CreateFlashSale,
+	)
+
+	r.PUT("/flash-sales/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermFlashSalesManage),
+		controllers.UpdateFlashSale,
+	)
+
+	r.DELETE("/flash-sales/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermFlashSalesManage),
+		controllers.DeleteFlashSale,
+	)
+
+	r.POST("/flash-sales/:id/stop",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermFlashSalesManage),
+		controllers.StopFlashSale,
+	)
+}
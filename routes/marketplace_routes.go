@@ -12,4 +12,10 @@ func SetupMarketplaceRoutes(r *gin.Engine) {
 		middlewares.AuthMiddleware(), 
 		controllers.GetMarketplace,
 	)
+
+	// Perbandingan penawaran seller untuk satu produk master (buy box)
+	r.GET("/marketplace/products/:productId/offers",
+		middlewares.AuthMiddleware(),
+		controllers.GetProductOffers,
+	)
 }
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"technical-test-backend/controllers"
+	"technical-test-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupNotificationRoutes(r *gin.Engine) {
+	// Notifikasi in-app, semua role (hanya milik user yang login)
+	r.GET("/notifications",
+		middlewares.AuthMiddleware(),
+		controllers.GetNotifications,
+	)
+
+	r.POST("/notifications/read-all",
+		middlewares.AuthMiddleware(),
+		controllers.MarkAllNotificationsRead,
+	)
+
+	r.POST("/notifications/:id/read",
+		middlewares.AuthMiddleware(),
+		controllers.MarkNotificationRead,
+	)
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"technical-test-backend/controllers"
 	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,31 +11,75 @@ import (
 func SetupProductRoutes(r *gin.Engine) {
 	// Product Master (Gudang Pusat)
 	r.GET("/products", 
-		middlewares.AuthMiddleware(), 
+		middlewares.AuthMiddleware(models.APIKeyScopeCatalogRead), 
 		controllers.FindAllProducts,
 	)
 	
 	r.POST("/products", 
 		middlewares.AuthMiddleware(), 
-		middlewares.RoleMiddleware("Admin"), 
+		middlewares.RequirePermission(models.PermProductsWrite), 
 		controllers.CreateProduct,
 	)
 	
 	r.DELETE("/products/:id", 
 		middlewares.AuthMiddleware(), 
-		middlewares.RoleMiddleware("Admin"), 
+		middlewares.RequirePermission(models.PermProductsWrite), 
 		controllers.DeleteProduct,
 	)
 	
 	r.PUT("/products/:id",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Admin"),
+		middlewares.RequirePermission(models.PermProductsWrite),
 		controllers.UpdateProduct,
 	)
 	
 	r.GET("/products/low-stock",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Admin"),
+		middlewares.RequirePermission(models.PermInventoryRead),
 		controllers.GetLowStock,
 	)
+
+	// SKU & barcode (scan dan cetak label)
+	r.GET("/products/by-code/:code",
+		middlewares.AuthMiddleware(models.APIKeyScopeCatalogRead),
+		controllers.GetProductByCode,
+	)
+
+	r.GET("/products/:id/label",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermInventoryRead),
+		controllers.GetProductLabel,
+	)
+
+	// Bulk import / export (CSV & XLSX)
+	r.POST("/products/import",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermProductsWrite),
+		controllers.ImportProducts,
+	)
+
+	r.GET("/products/export",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermInventoryRead),
+		controllers.ExportProducts,
+	)
+
+	// Stock Ledger
+	r.GET("/products/stock-consistency",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermInventoryRead),
+		controllers.CheckStockConsistency,
+	)
+
+	r.GET("/products/:id/stock-history",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermInventoryRead),
+		controllers.GetStockHistory,
+	)
+
+	r.POST("/products/:id/stock-movements",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermInventoryWrite),
+		controllers.RecordStockMovement,
+	)
 }
@@ -3,6 +3,7 @@ package routes
 import (
 	"technical-test-backend/controllers"
 	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,19 +16,25 @@ func SetupProductTypeRoutes(r *gin.Engine) {
 	
 	r.POST("/product-types", 
 		middlewares.AuthMiddleware(), 
-		middlewares.RoleMiddleware("Admin"), 
+		middlewares.RequirePermission(models.PermProductTypesWrite), 
 		controllers.CreateType,
 	)
 	
 	r.PUT("/product-types/:id",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Admin"),
+		middlewares.RequirePermission(models.PermProductTypesWrite),
 		controllers.UpdateType,
 	)
 	
 	r.DELETE("/product-types/:id",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Admin"),
+		middlewares.RequirePermission(models.PermProductTypesWrite),
 		controllers.DeleteType,
 	)
+
+	r.POST("/product-types/:id/merge",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermProductTypesWrite),
+		controllers.MergeType,
+	)
 }
@@ -22,4 +22,11 @@ func SetupProfileRoutes(r *gin.Engine) {
 		middlewares.AuthMiddleware(),
 		controllers.ChangePassword,
 	)
+
+	// TOTP 2FA (role yang boleh: TWO_FACTOR_ROLES, dicek di service)
+	r.GET("/profile/2fa", middlewares.AuthMiddleware(), controllers.GetTwoFactorStatus)
+	r.POST("/profile/2fa/setup", middlewares.AuthMiddleware(), controllers.SetupTwoFactor)
+	r.POST("/profile/2fa/enable", middlewares.AuthMiddleware(), controllers.EnableTwoFactor)
+	r.POST("/profile/2fa/disable", middlewares.AuthMiddleware(), controllers.DisableTwoFactor)
+	r.POST("/profile/2fa/recovery-codes", middlewares.AuthMiddleware(), controllers.RegenerateRecoveryCodes)
 }
@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"technical-test-backend/controllers"
+	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPurchaseOrderRoutes(r *gin.Engine) {
+	r.GET("/purchase-orders",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermPurchaseOrdersManage),
+		controllers.GetPurchaseOrders,
+	)
+
+	r.GET("/purchase-orders/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermPurchaseOrdersManage),
+		controllers.GetPurchaseOrderByID,
+	)
+
+	r.POST("/purchase-orders",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermPurchaseOrdersManage),
+		controllers.CreatePurchaseOrder,
+	)
+
+	r.PUT("/purchase-orders/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermPurchaseOrdersManage),
+		controllers.UpdatePurchaseOrder,
+	)
+
+	r.DELETE("/purchase-orders/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermPurchaseOrdersManage),
+		controllers.DeletePurchaseOrder,
+	)
+
+	// Alur status: DRAFT -> SENT -> PARTIALLY_RECEIVED/RECEIVED -> CLOSED
+	r.POST("/purchase-orders/:id/send",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermPurchaseOrdersManage),
+		controllers.SendPurchaseOrder,
+	)
+
+	r.POST("/purchase-orders/:id/receive",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermPurchaseOrdersManage),
+		controllers.ReceivePurchaseOrder,
+	)
+
+	r.POST("/purchase-orders/:id/close",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermPurchaseOrdersManage),
+		controllers.ClosePurchaseOrder,
+	)
+}
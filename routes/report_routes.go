@@ -3,6 +3,7 @@ package routes
 import (
 	"technical-test-backend/controllers"
 	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,19 +11,32 @@ import (
 func SetupReportRoutes(r *gin.Engine) {
 	r.GET("/reports/sales",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Admin"),
+		middlewares.RequirePermission(models.PermReportsRead),
 		controllers.GetSalesReport,
 	)
 
 	r.GET("/reports/top-products",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Admin"),
+		middlewares.RequirePermission(models.PermReportsRead),
 		controllers.GetTopProducts,
 	)
 
 	r.GET("/reports/top-sellers",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Admin"),
+		middlewares.RequirePermission(models.PermReportsRead),
 		controllers.GetTopSellers,
 	)
+
+	// Rekomendasi reorder dari kecepatan penjualan
+	r.GET("/reports/reorder-suggestions",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermReportsRead),
+		controllers.GetReorderSuggestions,
+	)
+
+	r.POST("/reports/reorder-suggestions/run",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermPurchaseOrdersManage),
+		controllers.RunReorderCheck,
+	)
 }
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"technical-test-backend/controllers"
+	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupReviewRoutes(r *gin.Engine) {
+	// Pembeli: ulas transaksi COMPLETED
+	r.POST("/transactions/:id/review",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermOrdersPlace),
+		controllers.CreateReview,
+	)
+
+	// Semua user: ulasan yang tampil untuk satu etalase
+	r.GET("/marketplace/listings/:id/reviews",
+		middlewares.AuthMiddleware(),
+		controllers.GetListingReviews,
+	)
+
+	// Seller: ulasan etalase sendiri & balasan
+	r.GET("/seller/reviews",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermSellerOrders),
+		controllers.GetSellerReviews,
+	)
+
+	r.POST("/seller/reviews/:id/reply",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermSellerOrders),
+		controllers.ReplyReview,
+	)
+
+	// Admin: moderasi
+	r.GET("/reviews",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermReviewsModerate),
+		controllers.GetReviews,
+	)
+
+	r.POST("/reviews/:id/moderate",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermReviewsModerate),
+		controllers.ModerateReview,
+	)
+}
@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"technical-test-backend/controllers"
+	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRoleRoutes(r *gin.Engine) {
+	// Role & permission management (RBAC)
+	r.GET("/permissions",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermRolesManage),
+		controllers.GetPermissions,
+	)
+
+	r.GET("/roles",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermRolesManage),
+		controllers.GetRoleList,
+	)
+
+	r.GET("/roles/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermRolesManage),
+		controllers.GetRoleDetail,
+	)
+
+	r.POST("/roles",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermRolesManage),
+		controllers.CreateRole,
+	)
+
+	r.PUT("/roles/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermRolesManage),
+		controllers.UpdateRole,
+	)
+
+	r.DELETE("/roles/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermRolesManage),
+		controllers.DeleteRole,
+	)
+}
@@ -3,38 +3,77 @@ package routes
 import (
 	"technical-test-backend/controllers"
 	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupSellerRoutes(r *gin.Engine) {
 	r.POST("/seller/products", 
-		middlewares.AuthMiddleware(), 
-		middlewares.RoleMiddleware("Seller"), 
+		middlewares.AuthMiddleware(models.APIKeyScopeCatalogWrite), 
+		middlewares.RequirePermission(models.PermSellerListings), 
 		controllers.AddToEtalase,
 	)
 	
 	r.GET("/seller/products",
-		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Seller"),
+		middlewares.AuthMiddleware(models.APIKeyScopeCatalogRead),
+		middlewares.RequirePermission(models.PermSellerListings),
 		controllers.GetSellerProducts,
 	)
 	
 	r.PUT("/seller/products/:id",
-		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Seller"),
+		middlewares.AuthMiddleware(models.APIKeyScopeCatalogWrite),
+		middlewares.RequirePermission(models.PermSellerListings),
 		controllers.UpdateSellerProduct,
 	)
 	
 	r.DELETE("/seller/products/:id",
-		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Seller"),
+		middlewares.AuthMiddleware(models.APIKeyScopeCatalogWrite),
+		middlewares.RequirePermission(models.PermSellerListings),
 		controllers.DeleteSellerProduct,
 	)
 	
-	r.GET("/seller/transactions",
+	// Histori & jadwal harga jual
+	r.GET("/seller/products/:id/price-history",
+		middlewares.AuthMiddleware(models.APIKeyScopeCatalogRead),
+		middlewares.RequirePermission(models.PermSellerListings),
+		controllers.GetPriceHistory,
+	)
+
+	r.GET("/seller/products/:id/price-schedules",
+		middlewares.AuthMiddleware(models.APIKeyScopeCatalogRead),
+		middlewares.RequirePermission(models.PermSellerListings),
+		controllers.GetPriceSchedules,
+	)
+
+	r.POST("/seller/products/:id/price-schedules",
+		middlewares.AuthMiddleware(models.APIKeyScopeCatalogWrite),
+		middlewares.RequirePermission(models.PermSellerListings),
+		controllers.CreatePriceSchedule,
+	)
+
+	r.DELETE("/seller/products/:id/price-schedules/:scheduleId",
+		middlewares.AuthMiddleware(models.APIKeyScopeCatalogWrite),
+		middlewares.RequirePermission(models.PermSellerListings),
+		controllers.CancelPriceSchedule,
+	)
+
+	// Profil toko (nama, slug, jam operasional, mode libur)
+	r.GET("/seller/profile",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Seller"),
+		middlewares.RequirePermission(models.PermSellerListings),
+		controllers.GetSellerProfile,
+	)
+
+	r.PUT("/seller/profile",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermSellerListings),
+		controllers.UpdateSellerProfile,
+	)
+
+	r.GET("/seller/transactions",
+		middlewares.AuthMiddleware(models.APIKeyScopeOrdersRead),
+		middlewares.RequirePermission(models.PermSellerOrders),
 		controllers.GetSellerTransactions,
 	)
 }
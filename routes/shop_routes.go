@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"technical-test-backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupShopRoutes(r *gin.Engine) {
+	// Public: halaman toko seller
+	r.GET("/shops/:slug", controllers.GetShop)
+}
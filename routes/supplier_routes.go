@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"technical-test-backend/controllers"
+	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSupplierRoutes(r *gin.Engine) {
+	r.GET("/suppliers",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermSuppliersManage),
+		controllers.GetSuppliers,
+	)
+
+	r.GET("/suppliers/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermSuppliersManage),
+		controllers.GetSupplierByID,
+	)
+
+	r.POST("/suppliers",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermSuppliersManage),
+		controllers.CreateSupplier,
+	)
+
+	r.PUT("/suppliers/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermSuppliersManage),
+		controllers.UpdateSupplier,
+	)
+
+	r.DELETE("/suppliers/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermSuppliersManage),
+		controllers.DeleteSupplier,
+	)
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"technical-test-backend/controllers"
 	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,13 +11,13 @@ import (
 func SetupTransactionRoutes(r *gin.Engine) {
 	r.POST("/transactions", 
 		middlewares.AuthMiddleware(), 
-		middlewares.RoleMiddleware("Pelanggan"), 
+		middlewares.RequirePermission(models.PermOrdersPlace), 
 		controllers.CreateOrder,
 	)
 	
 	r.POST("/transactions/:id/confirm", 
-		middlewares.AuthMiddleware(), 
-		middlewares.RoleMiddleware("Seller"), 
+		middlewares.AuthMiddleware(models.APIKeyScopeOrdersConfirm), 
+		middlewares.RequirePermission(models.PermSellerOrders), 
 		controllers.ConfirmOrder,
 	)
 	
@@ -27,7 +28,7 @@ func SetupTransactionRoutes(r *gin.Engine) {
 	
 	r.POST("/transactions/:id/cancel",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Pelanggan"),
+		middlewares.RequirePermission(models.PermOrdersPlace),
 		controllers.CancelTransaction,
 	)
 }
@@ -3,39 +3,71 @@ package routes
 import (
 	"technical-test-backend/controllers"
 	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupUserRoutes(r *gin.Engine) {
-	// User Management endpoints (Admin only)
+	// User Management endpoints (permission users.manage)
 	r.GET("/users", 
 		middlewares.AuthMiddleware(), 
-		middlewares.RoleMiddleware("Admin"), 
+		middlewares.RequirePermission(models.PermUsersManage), 
 		controllers.FindUsers,
 	)
 	
 	r.POST("/users/admin", 
 		middlewares.AuthMiddleware(), 
-		middlewares.RoleMiddleware("Admin"), 
+		middlewares.RequirePermission(models.PermUsersManage), 
 		controllers.CreateAdmin,
 	)
 	
 	r.DELETE("/users/:id", 
 		middlewares.AuthMiddleware(), 
-		middlewares.RoleMiddleware("Admin"), 
+		middlewares.RequirePermission(models.PermUsersManage), 
 		controllers.DeleteUser,
 	)
 	
 	r.GET("/users/:id",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Admin"),
+		middlewares.RequirePermission(models.PermUsersManage),
 		controllers.GetUserDetail,
 	)
 	
 	r.PUT("/users/:id",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("Admin"),
+		middlewares.RequirePermission(models.PermUsersManage),
 		controllers.UpdateUser,
 	)
+
+	r.POST("/users/:id/unlock-login",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermUsersManage),
+		controllers.UnlockUserLogin,
+	)
+
+	r.DELETE("/users/:id/2fa",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermUsersManage),
+		controllers.ResetUserTwoFactor,
+	)
+
+	// Status akun: suspend (sementara / sampai diaktifkan), reactivate, deactivate
+	r.POST("/users/:id/suspend",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermUsersManage),
+		controllers.SuspendUser,
+	)
+
+	r.POST("/users/:id/reactivate",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermUsersManage),
+		controllers.ReactivateUser,
+	)
+
+	r.POST("/users/:id/deactivate",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermUsersManage),
+		controllers.DeactivateUser,
+	)
 }
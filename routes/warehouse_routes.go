@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"technical-test-backend/controllers"
+	"technical-test-backend/middlewares"
+	"technical-test-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupWarehouseRoutes(r *gin.Engine) {
+	r.GET("/warehouses",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermInventoryRead),
+		controllers.GetWarehouses,
+	)
+
+	r.POST("/warehouses",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermInventoryWrite),
+		controllers.CreateWarehouse,
+	)
+
+	r.PUT("/warehouses/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermInventoryWrite),
+		controllers.UpdateWarehouse,
+	)
+
+	r.DELETE("/warehouses/:id",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermInventoryWrite),
+		controllers.DeleteWarehouse,
+	)
+
+	r.GET("/warehouses/:id/stock",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermInventoryRead),
+		controllers.GetWarehouseStock,
+	)
+
+	// Transfer antar gudang
+	r.GET("/warehouses/transfers",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermInventoryRead),
+		controllers.GetStockTransfers,
+	)
+
+	r.POST("/warehouses/transfers",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermInventoryWrite),
+		controllers.TransferStock,
+	)
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"technical-test-backend/database"
+	"technical-test-backend/mailer"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidUserToken - Token reset password / verifikasi tidak dikenal, kadaluarsa, atau sudah dipakai (HTTP 400)
+var ErrInvalidUserToken = errors.New("invalid or expired token")
+
+// ErrEmailTaken - Email sudah dipakai user lain (HTTP 409)
+var ErrEmailTaken = errors.New("email already in use")
+
+// ErrEmailAlreadyVerified - Tidak ada email yang perlu diverifikasi (HTTP 409)
+var ErrEmailAlreadyVerified = errors.New("email already verified")
+
+// ErrEmailNotVerified - Login ditolak karena email belum diverifikasi (HTTP 403)
+var ErrEmailNotVerified = errors.New("email belum diverifikasi, cek inbox untuk link verifikasi")
+
+// ForgotPasswordInput - Minta link reset password
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordInput - Token dari email + password baru
+type ResetPasswordInput struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// VerifyEmailInput - Token dari email verifikasi
+type VerifyEmailInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// passwordResetTTL - Umur link reset password (PASSWORD_RESET_TTL, default 1 jam)
+func passwordResetTTL() time.Duration {
+	return utils.EnvDuration("PASSWORD_RESET_TTL", time.Hour)
+}
+
+// emailVerificationTTL - Umur link verifikasi email (EMAIL_VERIFICATION_TTL, default 48 jam)
+func emailVerificationTTL() time.Duration {
+	return utils.EnvDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour)
+}
+
+// emailVerificationRequired - Login ditolak untuk email yang belum diverifikasi (REQUIRE_EMAIL_VERIFICATION, default false)
+func emailVerificationRequired() bool {
+	return utils.EnvBool("REQUIRE_EMAIL_VERIFICATION", false)
+}
+
+// appLink - Link ke halaman frontend (APP_BASE_URL) dengan token sebagai query string
+func appLink(path string, token string) string {
+	base := strings.TrimRight(utils.EnvString("APP_BASE_URL", "http://localhost:3000"), "/")
+	return base + path + "?token=" + url.QueryEscape(token)
+}
+
+// createUserToken - Buat token satu kali pakai, token lama dengan tujuan yang sama langsung tidak berlaku
+func createUserToken(tx *gorm.DB, userID uuid.UUID, purpose string, email string, ttl time.Duration) (string, error) {
+	if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Delete(&models.UserToken{}).Error; err != nil {
+		return "", err
+	}
+
+	token, err := generateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	record := models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		Email:     email,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// findUserToken - Kunci token dan pastikan masih berlaku (belum dipakai & belum kadaluarsa)
+func findUserToken(tx *gorm.DB, token string, purpose string) (models.UserToken, error) {
+	var record models.UserToken
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&record, "token_hash = ? AND purpose = ?", hashToken(token), purpose).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return record, ErrInvalidUserToken
+		}
+		return record, err
+	}
+	if record.UsedAt != nil || time.Now().After(record.ExpiresAt) {
+		return record, ErrInvalidUserToken
+	}
+	return record, nil
+}
+
+// consumeUserToken - findUserToken lalu tandai sudah dipakai
+func consumeUserToken(tx *gorm.DB, token string, purpose string) (models.UserToken, error) {
+	record, err := findUserToken(tx, token, purpose)
+	if err != nil {
+		return record, err
+	}
+	if err := tx.Model(&record).Update("used_at", time.Now()).Error; err != nil {
+		return record, err
+	}
+	return record, nil
+}
+
+// emailInUse - Email sudah dipakai user lain
+func emailInUse(tx *gorm.DB, email string, exceptUserID uuid.UUID) (bool, error) {
+	var count int64
+	err := tx.Model(&models.User{}).Where("email = ? AND id <> ?", email, exceptUserID).Count(&count).Error
+	return count > 0, err
+}
+
+// verificationMessage - Email berisi link verifikasi untuk alamat email
+func verificationMessage(user models.User, email string, token string) mailer.Message {
+	return mailer.Message{
+		To:      email,
+		Subject: "Verifikasi email Anda",
+		Body: fmt.Sprintf("Halo %s,\n\n"+
+			"Konfirmasi alamat email %s dengan membuka link berikut:\n%s\n\n"+
+			"Link berlaku selama %s dan hanya bisa dipakai sekali.\n"+
+			"Abaikan email ini jika Anda tidak merasa mendaftar atau mengganti email.\n",
+			user.Name, email, appLink("/verify-email", token), emailVerificationTTL()),
+	}
+}
+
+// sendEmailVerification - Buat token verifikasi untuk email lalu kirim linknya.
+// Token disimpan dalam transaksi sendiri, email dikirim setelah commit.
+func sendEmailVerification(user models.User, email string) error {
+	var token string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		token, err = createUserToken(tx, user.ID, models.UserTokenEmailVerification, email, emailVerificationTTL())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return mailer.Default().Send(verificationMessage(user, email, token))
+}
+
+// RequestPasswordReset - Kirim link reset password jika email terdaftar.
+// Selalu berhasil untuk email yang tidak terdaftar agar tidak bisa dipakai menebak akun.
+func (s *AuthService) RequestPasswordReset(input ForgotPasswordInput) error {
+	var user models.User
+	if err := database.DB.Where("email = ?", input.Email).Limit(1).Find(&user).Error; err != nil {
+		return err
+	}
+	if user.ID == uuid.Nil {
+		return nil
+	}
+
+	var token string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		token, err = createUserToken(tx, user.ID, models.UserTokenPasswordReset, user.Email, passwordResetTTL())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset password",
+		Body: fmt.Sprintf("Halo %s,\n\n"+
+			"Kami menerima permintaan reset password untuk akun Anda. Buka link berikut untuk membuat password baru:\n%s\n\n"+
+			"Link berlaku selama %s dan hanya bisa dipakai sekali.\n"+
+			"Abaikan email ini jika Anda tidak meminta reset password, password Anda tidak berubah.\n",
+			user.Name, appLink("/reset-password", token), passwordResetTTL()),
+	}
+	if err := mailer.Default().Send(msg); err != nil {
+		// Tidak dikembalikan ke klien (response harus sama untuk email terdaftar / tidak)
+		log.Printf("[auth] gagal kirim email reset password ke %s: %v", user.Email, err)
+	}
+	return nil
+}
+
+// ResetPassword - Ganti password dengan token dari email. Semua refresh token user dicabut
+// (semua perangkat harus login ulang), access token yang sudah terbit berakhir dalam ACCESS_TOKEN_TTL.
+func (s *AuthService) ResetPassword(input ResetPasswordInput) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		record, err := consumeUserToken(tx, input.Token, models.UserTokenPasswordReset)
+		if err != nil {
+			return err
+		}
+
+		var user models.User
+		if err := tx.First(&user, "id = ?", record.UserID).Error; err != nil {
+			return ErrInvalidUserToken
+		}
+
+		updates := map[string]interface{}{"password": string(hashedPassword)}
+		// Link diterima di alamat email akun = kepemilikan email terbukti
+		if user.EmailVerifiedAt == nil && record.Email == user.Email {
+			updates["email_verified_at"] = time.Now()
+		}
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			return err
+		}
+		return revokeRefreshTokens(tx, models.TokenRevokedPasswordReset, "user_id = ?", user.ID)
+	})
+}
+
+// VerifyEmail - Konfirmasi email dengan token dari email verifikasi.
+// Token untuk PendingEmail sekaligus mengganti Email user ke alamat baru.
+func (s *AuthService) VerifyEmail(input VerifyEmailInput) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		record, err := consumeUserToken(tx, input.Token, models.UserTokenEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", record.UserID).Error; err != nil {
+			return ErrInvalidUserToken
+		}
+
+		now := time.Now()
+		switch {
+		case record.Email == user.Email:
+			if user.EmailVerifiedAt != nil {
+				return nil
+			}
+			return tx.Model(&user).Update("email_verified_at", now).Error
+		case record.Email == user.PendingEmail:
+			taken, err := emailInUse(tx, record.Email, user.ID)
+			if err != nil {
+				return err
+			}
+			if taken {
+				return ErrEmailTaken
+			}
+			return tx.Model(&user).Updates(map[string]interface{}{
+				"email":             record.Email,
+				"pending_email":     "",
+				"email_verified_at": now,
+			}).Error
+		default:
+			// Email tujuan sudah diganti lagi sejak token dibuat
+			return ErrInvalidUserToken
+		}
+	})
+}
+
+// ResendVerification - Kirim ulang link verifikasi ke email baru yang menunggu konfirmasi,
+// atau ke email akun jika belum diverifikasi
+func (s *AuthService) ResendVerification(userID string) error {
+	var user models.User
+	if err := database.DB.First(&user, "id = ?", userID).Error; err != nil {
+		return err
+	}
+
+	email := user.PendingEmail
+	if email == "" {
+		if user.EmailVerifiedAt != nil {
+			return ErrEmailAlreadyVerified
+		}
+		email = user.Email
+	}
+	return sendEmailVerification(user, email)
+}
+
+// RequestEmailChange - Email baru dari update profil disimpan sebagai PendingEmail dan baru
+// menggantikan Email setelah link verifikasi yang dikirim ke alamat baru dibuka.
+// Email yang sama dengan email sekarang membatalkan permintaan yang tertunda.
+func (s *AuthService) RequestEmailChange(user models.User, email string) error {
+	if email == user.Email {
+		if user.PendingEmail == "" {
+			return nil
+		}
+		return database.DB.Model(&user).Update("pending_email", "").Error
+	}
+
+	taken, err := emailInUse(database.DB, email, user.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrEmailTaken
+	}
+	if err := database.DB.Model(&user).Update("pending_email", email).Error; err != nil {
+		return err
+	}
+	return sendEmailVerification(user, email)
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAccountSuspended - Akun ditangguhkan admin, login & request ditolak (HTTP 403)
+var ErrAccountSuspended = errors.New("account suspended")
+
+// ErrAccountDeactivated - Akun sudah dinonaktifkan / dihapus (HTTP 403)
+var ErrAccountDeactivated = errors.New("account deactivated")
+
+// ErrCannotChangeOwnStatus - Admin tidak boleh menangguhkan / menonaktifkan / menghapus akunnya sendiri (HTTP 409)
+var ErrCannotChangeOwnStatus = errors.New("cannot change status of your own account")
+
+// ErrAccountAnonymized - Akun sudah dianonimkan, status tidak bisa diubah lagi (HTTP 409)
+var ErrAccountAnonymized = errors.New("account already deleted")
+
+// ErrSellerUnavailable - Seller ditangguhkan / nonaktif, order baru ditolak (HTTP 409)
+var ErrSellerUnavailable = errors.New("seller is not available")
+
+// AccountStatusError - Akun tidak aktif beserta alasan & batas waktu suspensi (Until nil = sampai diaktifkan admin)
+type AccountStatusError struct {
+	Status string
+	Reason string
+	Until  *time.Time
+}
+
+func (e *AccountStatusError) Error() string {
+	message := "akun dinonaktifkan"
+	if e.Status == models.UserStatusSuspended {
+		message = "akun ditangguhkan"
+		if e.Until != nil {
+			message += " sampai " + e.Until.Format("2006-01-02 15:04")
+		}
+	}
+	if e.Reason != "" {
+		message += ": " + e.Reason
+	}
+	return message
+}
+
+func (e *AccountStatusError) Unwrap() error {
+	if e.Status == models.UserStatusSuspended {
+		return ErrAccountSuspended
+	}
+	return ErrAccountDeactivated
+}
+
+// SuspendUserInput - Alasan wajib, until kosong = sampai diaktifkan kembali oleh admin
+type SuspendUserInput struct {
+	Reason string     `json:"reason" binding:"required,max=255" example:"Penipuan pembeli, sedang diinvestigasi"`
+	Until  *time.Time `json:"until" example:"2025-05-01T00:00:00+07:00"`
+}
+
+// DeactivateUserInput - Alasan penutupan akun
+type DeactivateUserInput struct {
+	Reason string `json:"reason" binding:"required,max=255" example:"Permintaan pemilik akun"`
+}
+
+// accountStatusError - nil jika akun aktif pada waktu now
+func accountStatusError(user models.User, now time.Time) error {
+	status := user.StatusAt(now)
+	if status == models.UserStatusActive {
+		return nil
+	}
+	return &AccountStatusError{Status: status, Reason: user.StatusReason, Until: user.SuspendedUntil}
+}
+
+// inactiveUsersSQL - Subquery id user yang sedang ditangguhkan / nonaktif (argumen: now)
+const inactiveUsersSQL = `SELECT id FROM users WHERE status = 'DEACTIVATED'
+	OR (status = 'SUSPENDED' AND (suspended_until IS NULL OR suspended_until > ?))`
+
+// CheckUserActive - Dipakai AuthMiddleware pada setiap request agar suspensi langsung berlaku
+// walau access token masih berlaku. gorm.ErrRecordNotFound jika user sudah tidak ada.
+func CheckUserActive(userID string) error {
+	var user models.User
+	if err := database.DB.Select("id", "status", "status_reason", "suspended_until").
+		First(&user, "id = ?", userID).Error; err != nil {
+		return err
+	}
+	return accountStatusError(user, time.Now())
+}
+
+// lockManagedUser - Kunci user target perubahan status, tolak akun sendiri & akun yang sudah dianonimkan
+func lockManagedUser(tx *gorm.DB, userID string, actorID uuid.UUID) (models.User, error) {
+	var user models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error; err != nil {
+		return user, err
+	}
+	if user.ID == actorID {
+		return user, ErrCannotChangeOwnStatus
+	}
+	if user.AnonymizedAt != nil {
+		return user, ErrAccountAnonymized
+	}
+	return user, nil
+}
+
+// setUserStatus - Ubah status, cabut semua sesi jika akun tidak aktif lagi, lalu catat di audit log
+func setUserStatus(tx *gorm.DB, user *models.User, status string, reason string, until *time.Time, action string, audit *AuditContext) error {
+	now := time.Now()
+	before := map[string]interface{}{"status": user.StatusAt(now), "reason": user.StatusReason}
+	updates := map[string]interface{}{
+		"status":            status,
+		"status_reason":     reason,
+		"status_changed_at": now,
+		"suspended_until":   until,
+	}
+	if err := tx.Model(user).Updates(updates).Error; err != nil {
+		return err
+	}
+	if status != models.UserStatusActive {
+		if err := revokeRefreshTokens(tx, models.TokenRevokedDisabled, "user_id = ?", user.ID); err != nil {
+			return err
+		}
+		// User yang tidak aktif tidak dihitung sebagai pengelola role
+		if err := ensureRoleManagerExists(tx); err != nil {
+			return err
+		}
+	}
+	return audit.Record(tx, action, "user", &user.ID, map[string]interface{}{
+		"before": before,
+		"after":  map[string]interface{}{"status": status, "reason": reason, "until": until},
+	})
+}
+
+// SuspendUser - Tangguhkan akun (sementara jika until diisi). Semua sesi dicabut, access token
+// yang masih berlaku langsung ditolak AuthMiddleware, etalase seller hilang dari marketplace.
+func (s *UserService) SuspendUser(userID string, input SuspendUserInput, audit *AuditContext) (models.User, error) {
+	actorUUID, err := audit.actor()
+	if err != nil {
+		return models.User{}, err
+	}
+	if input.Until != nil && !input.Until.After(time.Now()) {
+		return models.User{}, errors.New("until must be in the future")
+	}
+
+	var user models.User
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if user, err = lockManagedUser(tx, userID, actorUUID); err != nil {
+			return err
+		}
+		if user.Status == models.UserStatusDeactivated {
+			return fmt.Errorf("%w: reactivate the account first", ErrAccountDeactivated)
+		}
+		return setUserStatus(tx, &user, models.UserStatusSuspended, strings.TrimSpace(input.Reason), input.Until,
+			models.AuditUserSuspended, audit)
+	})
+	if err != nil {
+		return user, err
+	}
+	return s.GetUserByID(userID)
+}
+
+// ReactivateUser - Cabut suspensi / aktifkan kembali akun yang dinonaktifkan (bukan akun yang sudah dianonimkan)
+func (s *UserService) ReactivateUser(userID string, audit *AuditContext) (models.User, error) {
+	actorUUID, err := audit.actor()
+	if err != nil {
+		return models.User{}, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := lockManagedUser(tx, userID, actorUUID)
+		if err != nil {
+			return err
+		}
+		return setUserStatus(tx, &user, models.UserStatusActive, "", nil, models.AuditUserReactivated, audit)
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+	return s.GetUserByID(userID)
+}
+
+// DeactivateUser - Tutup akun tanpa menghapus data (bisa diaktifkan kembali). Semua sesi dicabut.
+func (s *UserService) DeactivateUser(userID string, input DeactivateUserInput, audit *AuditContext) (models.User, error) {
+	actorUUID, err := audit.actor()
+	if err != nil {
+		return models.User{}, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := lockManagedUser(tx, userID, actorUUID)
+		if err != nil {
+			return err
+		}
+		return setUserStatus(tx, &user, models.UserStatusDeactivated, strings.TrimSpace(input.Reason), nil,
+			models.AuditUserDeactivated, audit)
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+	return s.GetUserByID(userID)
+}
+
+// anonymizeUser - Hapus data pribadi & kredensial user, baris user tetap ada (status DEACTIVATED)
+// agar transaksi, review, dan laporan keuangan yang merujuk user ini tidak berubah.
+func anonymizeUser(tx *gorm.DB, user *models.User, now time.Time) error {
+	originalEmail := user.Email // Updates di bawah ikut mengubah struct user
+	updates := map[string]interface{}{
+		"name":              "Deleted User",
+		"email":             "deleted-" + user.ID.String() + "@users.invalid",
+		"password":          "", // Hash kosong tidak pernah cocok dengan password apapun
+		"address":           "",
+		"city":              "",
+		"latitude":          nil,
+		"longitude":         nil,
+		"email_verified_at": nil,
+		"pending_email":     "",
+		"status":            models.UserStatusDeactivated,
+		"status_reason":     "deleted",
+		"status_changed_at": now,
+		"suspended_until":   nil,
+		"anonymized_at":     now,
+	}
+	if err := tx.Model(user).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	// Kredensial & data yang tidak dibutuhkan laporan
+	if err := revokeRefreshTokens(tx, models.TokenRevokedDisabled, "user_id = ?", user.ID); err != nil {
+		return err
+	}
+	// Kredensial & data pribadi (secret TOTP, hash token / key, email & subject IdP) dihapus permanen,
+	// bukan soft delete, agar tidak tersisa di database
+	for _, model := range []interface{}{&models.UserToken{}, &models.UserTwoFactor{}, &models.RecoveryCode{}, &models.APIKey{}, &models.UserIdentity{}} {
+		if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("user_id = ?", user.ID).Delete(&models.WishlistItem{}).Error; err != nil {
+		return err
+	}
+	// Penghitung login gagal memakai email asli sebagai key (account:<email>)
+	if err := loginAttemptStore().Reset(loginKey("account", originalEmail)); err != nil {
+		return err
+	}
+
+	// Etalase seller ditarik dari marketplace, profil toko dihapus (soft delete)
+	if err := tx.Model(&models.SellerProduct{}).Where("seller_id = ?", user.ID).Update("is_active", false).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id = ?", user.ID).Delete(&models.SellerProfile{}).Error
+}
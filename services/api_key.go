@@ -0,0 +1,273 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKeyService - API key seller untuk integrasi sistem (sinkron etalase, tarik & konfirmasi order)
+type APIKeyService struct{}
+
+// apiKeyPrefix - Awalan key agar mudah dikenali (misal oleh secret scanner)
+const apiKeyPrefix = "tsk_"
+
+// maxActiveAPIKeys - Batas key aktif per user
+const maxActiveAPIKeys = 10
+
+// apiKeyTouchInterval - LastUsedAt diperbarui paling sering sekali per interval ini (bukan setiap request)
+const apiKeyTouchInterval = time.Minute
+
+// ErrInvalidAPIKey - Key tidak dikenal, dicabut, atau kadaluarsa (HTTP 401)
+var ErrInvalidAPIKey = errors.New("invalid or revoked API key")
+
+// ErrUnknownAPIKeyScope - Scope tidak dikenal (HTTP 400)
+var ErrUnknownAPIKeyScope = errors.New("unknown API key scope")
+
+// ErrAPIKeyScopeNotAllowed - Role pembuat tidak memiliki permission untuk scope yang diminta (HTTP 403)
+var ErrAPIKeyScopeNotAllowed = errors.New("your role does not allow this API key scope")
+
+// ErrTooManyAPIKeys - Key aktif sudah mencapai batas, cabut key yang tidak dipakai (HTTP 409)
+var ErrTooManyAPIKeys = errors.New("too many active API keys")
+
+// CreateAPIKeyInput - Nama key, scope, dan masa berlaku opsional (kosong = sampai dicabut)
+type CreateAPIKeyInput struct {
+	Name      string     `json:"name" binding:"required,max=100" example:"Sinkron ERP"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1" example:"catalog:read,catalog:write,orders:read"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2026-12-31T23:59:59+07:00"`
+}
+
+// APIKeyInfo - Data API key tanpa secret
+type APIKeyInfo struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip"`
+}
+
+// CreatedAPIKey - Key baru, Key hanya ditampilkan sekali saat dibuat
+type CreatedAPIKey struct {
+	APIKeyInfo
+	Key string `json:"key"`
+}
+
+// APIKeyPrincipal - Hasil autentikasi X-API-Key untuk AuthMiddleware
+type APIKeyPrincipal struct {
+	KeyID       uuid.UUID
+	UserID      uuid.UUID
+	Role        string
+	Scopes      []string
+	Permissions []string // Permission scope yang masih dimiliki role pemilik
+}
+
+// HasScope - Key memiliki salah satu scope yang diterima endpoint
+func (p APIKeyPrincipal) HasScope(scopes ...string) bool {
+	for _, scope := range scopes {
+		for _, granted := range p.Scopes {
+			if granted == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func apiKeyInfo(key models.APIKey) APIKeyInfo {
+	return APIKeyInfo{
+		ID:         key.ID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     strings.Fields(key.Scopes),
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		LastUsedAt: key.LastUsedAt,
+		LastUsedIP: key.LastUsedIP,
+	}
+}
+
+// apiKeyScopePermission - Permission yang dibutuhkan scope, false jika scope tidak dikenal
+func apiKeyScopePermission(scope string) (string, bool) {
+	for _, def := range models.APIKeyScopes {
+		if def.Name == scope {
+			return def.Permission, true
+		}
+	}
+	return "", false
+}
+
+// List - API key milik user (aktif & yang sudah dicabut), terbaru dulu
+func (s *APIKeyService) List(userID string) ([]APIKeyInfo, error) {
+	var keys []models.APIKey
+	if err := database.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	infos := make([]APIKeyInfo, 0, len(keys))
+	for _, key := range keys {
+		infos = append(infos, apiKeyInfo(key))
+	}
+	return infos, nil
+}
+
+// Create - Buat API key dengan scope yang permission-nya dimiliki role user. Dicatat di audit log.
+func (s *APIKeyService) Create(input CreateAPIKeyInput, audit *AuditContext) (CreatedAPIKey, error) {
+	actorID, err := audit.actor()
+	if err != nil {
+		return CreatedAPIKey{}, err
+	}
+	now := time.Now()
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(now) {
+		return CreatedAPIKey{}, errors.New("expires_at must be in the future")
+	}
+
+	var created CreatedAPIKey
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.First(&user, "id = ?", actorID).Error; err != nil {
+			return err
+		}
+		permissions, err := rolePermissionNames(tx, user.RoleID)
+		if err != nil {
+			return err
+		}
+
+		// Scope unik & terurut, semua harus dikenal dan diizinkan role
+		seen := map[string]bool{}
+		scopes := []string{}
+		for _, scope := range input.Scopes {
+			scope = strings.TrimSpace(scope)
+			if seen[scope] {
+				continue
+			}
+			permission, ok := apiKeyScopePermission(scope)
+			if !ok {
+				return fmt.Errorf("%w: %s", ErrUnknownAPIKeyScope, scope)
+			}
+			if !HasPermission(permissions, permission) {
+				return fmt.Errorf("%w: %s", ErrAPIKeyScopeNotAllowed, scope)
+			}
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+		sort.Strings(scopes)
+
+		var active int64
+		if err := tx.Model(&models.APIKey{}).
+			Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", actorID, now).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active >= maxActiveAPIKeys {
+			return fmt.Errorf("%w (max %d)", ErrTooManyAPIKeys, maxActiveAPIKeys)
+		}
+
+		secret, err := generateOpaqueToken()
+		if err != nil {
+			return err
+		}
+		plain := apiKeyPrefix + secret
+		key := models.APIKey{
+			UserID:    actorID,
+			Name:      strings.TrimSpace(input.Name),
+			Prefix:    plain[:len(apiKeyPrefix)+8],
+			KeyHash:   hashToken(plain),
+			Scopes:    strings.Join(scopes, " "),
+			ExpiresAt: input.ExpiresAt,
+		}
+		if err := tx.Create(&key).Error; err != nil {
+			return err
+		}
+
+		created = CreatedAPIKey{APIKeyInfo: apiKeyInfo(key), Key: plain}
+		return audit.Record(tx, models.AuditAPIKeyCreated, "api_key", &key.ID, created.APIKeyInfo)
+	})
+	return created, err
+}
+
+// Revoke - Cabut API key milik user, request berikutnya dengan key ini langsung ditolak. Dicatat di audit log.
+func (s *APIKeyService) Revoke(id string, audit *AuditContext) (APIKeyInfo, error) {
+	actorID, err := audit.actor()
+	if err != nil {
+		return APIKeyInfo{}, err
+	}
+
+	var key models.APIKey
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&key, "id = ? AND user_id = ?", id, actorID).Error; err != nil {
+			return err
+		}
+		if key.RevokedAt != nil {
+			return nil
+		}
+		now := time.Now()
+		if err := tx.Model(&key).Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		key.RevokedAt = &now
+		return audit.Record(tx, models.AuditAPIKeyRevoked, "api_key", &key.ID, map[string]interface{}{
+			"name": key.Name, "prefix": key.Prefix,
+		})
+	})
+	return apiKeyInfo(key), err
+}
+
+// AuthenticateAPIKey - Validasi header X-API-Key: key dikenal, belum dicabut / kadaluarsa.
+// Status akun pemilik dicek AuthMiddleware (CheckUserActive) seperti access token.
+// Permission dihitung ulang dari role pemilik setiap request, sehingga perubahan role langsung berlaku.
+func AuthenticateAPIKey(plain string, ip string) (APIKeyPrincipal, error) {
+	if !strings.HasPrefix(plain, apiKeyPrefix) {
+		return APIKeyPrincipal{}, ErrInvalidAPIKey
+	}
+
+	var key models.APIKey
+	if err := database.DB.Preload("User.Role").Where("key_hash = ?", hashToken(plain)).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return APIKeyPrincipal{}, ErrInvalidAPIKey
+		}
+		return APIKeyPrincipal{}, err
+	}
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !key.ExpiresAt.After(now)) {
+		return APIKeyPrincipal{}, ErrInvalidAPIKey
+	}
+
+	rolePermissions, err := rolePermissionNames(database.DB, key.User.RoleID)
+	if err != nil {
+		return APIKeyPrincipal{}, err
+	}
+	principal := APIKeyPrincipal{
+		KeyID:       key.ID,
+		UserID:      key.UserID,
+		Role:        key.User.Role.Name,
+		Scopes:      strings.Fields(key.Scopes),
+		Permissions: []string{},
+	}
+	for _, scope := range principal.Scopes {
+		permission, ok := apiKeyScopePermission(scope)
+		if ok && HasPermission(rolePermissions, permission) && !HasPermission(principal.Permissions, permission) {
+			principal.Permissions = append(principal.Permissions, permission)
+		}
+	}
+
+	// Last used: dibatasi sekali per menit agar tidak menulis ke database setiap request
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		err := database.DB.Model(&models.APIKey{}).Where("id = ?", key.ID).
+			Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": truncate(ip, 64)}).Error
+		if err != nil {
+			return APIKeyPrincipal{}, err
+		}
+	}
+	return principal, nil
+}
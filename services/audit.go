@@ -0,0 +1,321 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditService - Baca & export audit log (hanya tambah, tidak ada ubah / hapus)
+type AuditService struct{}
+
+// auditExportLimit - Batas baris sekali export, persempit filter tanggal untuk data lebih banyak
+const auditExportLimit = 50000
+
+// AuditContext - Pelaku & request untuk audit log, dibuat middleware AuditTrail per request.
+// Service yang mencatat perubahan lengkap (before/after) lewat Record menandai request sudah
+// tercatat, sehingga middleware tidak menulis entry API_REQUEST kedua untuk request yang sama.
+type AuditContext struct {
+	ActorID   *uuid.UUID
+	APIKeyID  *uuid.UUID // Diisi AuthMiddleware jika request memakai X-API-Key
+	IP        string
+	RequestID string
+	recorded  bool
+}
+
+// Record - Catat perubahan dalam transaksi tx dengan pelaku, IP & request ID dari request.
+// Aman dipanggil pada AuditContext nil (dicatat sebagai aksi sistem).
+func (a *AuditContext) Record(tx *gorm.DB, action string, targetType string, targetID *uuid.UUID, data interface{}) error {
+	return writeAuditLog(tx, a.entry(action, targetType, targetID), data)
+}
+
+// entry - Entry audit log dengan pelaku, API key, IP & request ID dari request, request ditandai sudah tercatat
+func (a *AuditContext) entry(action string, targetType string, targetID *uuid.UUID) models.AuditLog {
+	entry := models.AuditLog{Action: action, TargetType: targetType, TargetID: targetID}
+	if a != nil {
+		entry.ActorID = a.ActorID
+		entry.APIKeyID = a.APIKeyID
+		entry.IP = a.IP
+		entry.RequestID = a.RequestID
+		a.recorded = true
+	}
+	return entry
+}
+
+// SetActor - Pelaku dari userID access token (diabaikan jika kosong / bukan UUID)
+func (a *AuditContext) SetActor(userID string) {
+	if a == nil || a.ActorID != nil {
+		return
+	}
+	if id, err := uuid.Parse(userID); err == nil {
+		a.ActorID = &id
+	}
+}
+
+// actor - ID pelaku, error jika request tidak membawa user (endpoint admin selalu lewat AuthMiddleware)
+func (a *AuditContext) actor() (uuid.UUID, error) {
+	if a == nil || a.ActorID == nil {
+		return uuid.Nil, errors.New("invalid user ID")
+	}
+	return *a.ActorID, nil
+}
+
+// Recorded - Request ini sudah dicatat service lewat Record
+func (a *AuditContext) Recorded() bool {
+	return a != nil && a.recorded
+}
+
+// createAuditLog - Catat satu kejadian di audit log (dalam transaksi pemanggil)
+// actorID nil = dilakukan sistem, data di-serialize ke JSON
+func createAuditLog(tx *gorm.DB, actorID *uuid.UUID, action string, targetType string, targetID *uuid.UUID, ip string, data interface{}) error {
+	return writeAuditLog(tx, models.AuditLog{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IP:         ip,
+	}, data)
+}
+
+func writeAuditLog(tx *gorm.DB, entry models.AuditLog, data interface{}) error {
+	payload := []byte("{}")
+	if data != nil {
+		var err error
+		if payload, err = json.Marshal(data); err != nil {
+			return err
+		}
+	}
+	entry.Data = string(payload)
+	return tx.Create(&entry).Error
+}
+
+// RecordAPIRequest - Entry API_REQUEST untuk request yang mengubah data tanpa Record dari service.
+// Ditulis setelah handler selesai (di luar transaksinya): dicoba ulang, lalu disimpan di spool jika
+// database gagal (lihat recordOutsideTransaction). Error hanya jika entry benar-benar tidak tersimpan.
+func RecordAPIRequest(audit *AuditContext, targetType string, targetID *uuid.UUID, data map[string]interface{}) error {
+	return recordOutsideTransaction(audit.entry(models.AuditAPIRequest, targetType, targetID), data)
+}
+
+// auditChanges - {"before": ..., "after": ...} berisi field yang nilainya berubah saja.
+// Nilai dibandingkan dalam bentuk JSON (pointer & tipe angka berbeda tetap dianggap sama jika nilainya sama).
+func auditChanges(before map[string]interface{}, after map[string]interface{}) map[string]interface{} {
+	changedBefore := map[string]interface{}{}
+	changedAfter := map[string]interface{}{}
+	for key, newValue := range after {
+		oldValue := before[key]
+		oldJSON, _ := json.Marshal(oldValue)
+		newJSON, _ := json.Marshal(newValue)
+		if string(oldJSON) != string(newJSON) {
+			changedBefore[key] = oldValue
+			changedAfter[key] = newValue
+		}
+	}
+	return map[string]interface{}{"before": changedBefore, "after": changedAfter}
+}
+
+// AuditLogFilter - Filter list & export audit log, field kosong = tidak difilter
+type AuditLogFilter struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	From       string // RFC3339 atau YYYY-MM-DD (inklusif)
+	To         string // RFC3339 atau YYYY-MM-DD (YYYY-MM-DD = sampai akhir hari)
+	Page       int
+	Limit      int
+}
+
+// AuditLogEntry - Satu baris audit log beserta nama & email pelaku
+type AuditLogEntry struct {
+	ID         string          `json:"id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ActorID    *string         `json:"actor_id"`
+	ActorName  string          `json:"actor_name"`
+	ActorEmail string          `json:"actor_email"`
+	APIKeyID   *string         `json:"api_key_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   *string         `json:"target_id"`
+	IP         string          `json:"ip"`
+	RequestID  string          `json:"request_id"`
+	Data       json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// AuditLogPage - Hasil list audit log (terbaru dulu)
+type AuditLogPage struct {
+	Items      []AuditLogEntry `json:"items"`
+	Pagination Pagination      `json:"pagination"`
+}
+
+// parseAuditTime - RFC3339 atau YYYY-MM-DD, endOfDay untuk batas atas tanggal saja
+func parseAuditTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return t, errors.New("date must be RFC3339 or YYYY-MM-DD")
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
+
+// auditLogQuery - Query audit_logs (+ pelaku) sesuai filter
+func auditLogQuery(filter AuditLogFilter) (*gorm.DB, error) {
+	query := database.DB.Table("audit_logs").
+		Joins("LEFT JOIN users ON users.id = audit_logs.actor_id").
+		Where("audit_logs.deleted_at IS NULL")
+	for column, value := range map[string]string{"actor_id": filter.ActorID, "target_id": filter.TargetID} {
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a UUID", column)
+		}
+		query = query.Where("audit_logs."+column+" = ?", id)
+	}
+	if filter.Action != "" {
+		query = query.Where("audit_logs.action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("audit_logs.target_type = ?", filter.TargetType)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("audit_logs.request_id = ?", filter.RequestID)
+	}
+	if filter.From != "" {
+		from, err := parseAuditTime(filter.From, false)
+		if err != nil {
+			return nil, fmt.Errorf("from: %w", err)
+		}
+		query = query.Where("audit_logs.created_at >= ?", from)
+	}
+	if filter.To != "" {
+		to, err := parseAuditTime(filter.To, true)
+		if err != nil {
+			return nil, fmt.Errorf("to: %w", err)
+		}
+		query = query.Where("audit_logs.created_at <= ?", to)
+	}
+	return query, nil
+}
+
+// findAuditLogs - Baris audit log terbaru dulu
+func findAuditLogs(query *gorm.DB, offset int, limit int) ([]AuditLogEntry, error) {
+	var rows []struct {
+		models.AuditLog
+		ActorName  string
+		ActorEmail string
+	}
+	err := query.Select("audit_logs.*, users.name AS actor_name, users.email AS actor_email").
+		Order("audit_logs.created_at DESC, audit_logs.id").
+		Offset(offset).Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]AuditLogEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := AuditLogEntry{
+			ID:         row.ID.String(),
+			CreatedAt:  row.CreatedAt,
+			ActorName:  row.ActorName,
+			ActorEmail: row.ActorEmail,
+			Action:     row.Action,
+			TargetType: row.TargetType,
+			IP:         row.IP,
+			RequestID:  row.RequestID,
+			Data:       json.RawMessage(row.Data),
+		}
+		if row.ActorID != nil {
+			id := row.ActorID.String()
+			entry.ActorID = &id
+		}
+		if row.TargetID != nil {
+			id := row.TargetID.String()
+			entry.TargetID = &id
+		}
+		if row.APIKeyID != nil {
+			id := row.APIKeyID.String()
+			entry.APIKeyID = &id
+		}
+		if len(entry.Data) == 0 {
+			entry.Data = json.RawMessage("{}")
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// List - Audit log terbaru dulu dengan filter & pagination (limit maks 200)
+func (s *AuditService) List(filter AuditLogFilter) (AuditLogPage, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 50
+	}
+	result := AuditLogPage{Items: []AuditLogEntry{}, Pagination: Pagination{Page: filter.Page, Limit: filter.Limit}}
+
+	query, err := auditLogQuery(filter)
+	if err != nil {
+		return result, err
+	}
+	if err := query.Session(&gorm.Session{}).Count(&result.Pagination.Total).Error; err != nil {
+		return result, err
+	}
+	result.Pagination.TotalPages = int((result.Pagination.Total + int64(filter.Limit) - 1) / int64(filter.Limit))
+
+	items, err := findAuditLogs(query, (filter.Page-1)*filter.Limit, filter.Limit)
+	if err != nil {
+		return result, err
+	}
+	result.Items = items
+	return result, nil
+}
+
+// AuditExportColumns - Header file export audit log
+var AuditExportColumns = []string{"created_at", "actor_id", "actor_name", "actor_email", "api_key_id", "action", "target_type", "target_id", "ip", "request_id", "data"}
+
+// Export - Baris audit log sesuai filter (tanpa pagination, maks auditExportLimit) untuk csv / xlsx
+func (s *AuditService) Export(filter AuditLogFilter) ([][]interface{}, error) {
+	query, err := auditLogQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := findAuditLogs(query, 0, auditExportLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]interface{}, len(AuditExportColumns))
+	for i, col := range AuditExportColumns {
+		header[i] = col
+	}
+	rows := [][]interface{}{header}
+	for _, e := range entries {
+		rows = append(rows, []interface{}{
+			e.CreatedAt.Format(time.RFC3339), stringOrEmpty(e.ActorID), e.ActorName, e.ActorEmail, stringOrEmpty(e.APIKeyID),
+			e.Action, e.TargetType, stringOrEmpty(e.TargetID), e.IP, e.RequestID, string(e.Data),
+		})
+	}
+	return rows, nil
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
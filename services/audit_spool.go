@@ -0,0 +1,154 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// auditWriteAttempts - Percobaan menulis entry API_REQUEST sebelum entry dipindahkan ke spool
+const auditWriteAttempts = 3
+
+// auditSpoolMu - Tulis & replay spool tidak boleh bersamaan (satu file per instance)
+var auditSpoolMu sync.Mutex
+
+// auditWriteFailures - Jumlah entry audit yang gagal ditulis ke database sejak aplikasi start
+var auditWriteFailures atomic.Int64
+
+// auditSpoolPath - File JSON Lines untuk entry audit yang gagal ditulis (AUDIT_SPOOL_FILE, default storage/audit/pending.jsonl)
+func auditSpoolPath() string {
+	return utils.EnvString("AUDIT_SPOOL_FILE", "storage/audit/pending.jsonl")
+}
+
+// AuditWriteFailures - Jumlah entry audit yang gagal ditulis ke database sejak start (masuk spool / hilang)
+func AuditWriteFailures() int64 {
+	return auditWriteFailures.Load()
+}
+
+// recordOutsideTransaction - Tulis entry audit di luar transaksi handler (response sudah terkirim).
+// Dicoba beberapa kali, jika tetap gagal entry disimpan di spool dan dimasukkan ulang oleh ReplayAuditSpool,
+// sehingga perubahan yang sudah di-commit tetap punya audit log. Setiap kegagalan dicatat sebagai ALERT.
+func recordOutsideTransaction(entry models.AuditLog, data interface{}) error {
+	payload := []byte("{}")
+	if data != nil {
+		var err error
+		if payload, err = json.Marshal(data); err != nil {
+			return err
+		}
+	}
+	// ID & waktu ditetapkan di sini agar replay idempotent dan waktu kejadian tidak bergeser
+	entry.ID = uuid.New()
+	entry.CreatedAt = time.Now()
+	entry.UpdatedAt = entry.CreatedAt
+	entry.Data = string(payload)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = database.DB.Create(&entry).Error; err == nil {
+			return nil
+		}
+		if attempt == auditWriteAttempts {
+			break
+		}
+		time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+	}
+
+	failures := auditWriteFailures.Add(1)
+	if spoolErr := appendAuditSpool(entry); spoolErr != nil {
+		line, _ := json.Marshal(entry)
+		log.Printf("[audit] ALERT: entry %s tidak tersimpan (database: %v, spool: %v), total gagal sejak start: %d, entry: %s",
+			entry.ID, err, spoolErr, failures, line)
+		return fmt.Errorf("audit log not written: %w", err)
+	}
+	log.Printf("[audit] ALERT: entry %s gagal ditulis ke database (%v), disimpan di %s untuk replay, total gagal sejak start: %d",
+		entry.ID, err, auditSpoolPath(), failures)
+	return nil
+}
+
+// appendAuditSpool - Tambah satu entry ke spool (fsync agar tidak hilang saat proses mati)
+func appendAuditSpool(entry models.AuditLog) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	auditSpoolMu.Lock()
+	defer auditSpoolMu.Unlock()
+
+	path := auditSpoolPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ReplayAuditSpool - Masukkan entry dari spool ke audit_logs dalam satu transaksi, lalu kosongkan spool.
+// Entry yang sudah ada (ID sama) dilewati. Jika gagal, spool dibiarkan untuk percobaan berikutnya.
+func ReplayAuditSpool() (int, error) {
+	auditSpoolMu.Lock()
+	defer auditSpoolMu.Unlock()
+
+	path := auditSpoolPath()
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var entries []models.AuditLog
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64<<10), 4<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry models.AuditLog
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.ID == uuid.Nil {
+			// Baris terpotong (proses mati saat menulis): isi tetap ditulis ke log agar bisa dipulihkan manual
+			log.Printf("[audit] ALERT: baris spool tidak valid dilewati: %s", scanner.Text())
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(entries) > 0 {
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			return tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&entries, 100).Error
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(entries), os.Remove(path)
+}
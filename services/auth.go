@@ -2,13 +2,14 @@ package services
 
 import (
 	"errors"
-	"os"
+	"log"
 	"technical-test-backend/database"
 	"technical-test-backend/models"
 	"time"
+
 	"github.com/google/uuid"
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 // AuthService menangani semua logika bisnis terkait autentikasi dan otorisasi
@@ -29,9 +30,12 @@ type LoginInput struct {
 }
 
 // LoginResponse adalah struktur response setelah login berhasil
+// Jika Challenge terisi, Tokens kosong: login dilanjutkan dengan kode 2FA di /auth/login/2fa
 type LoginResponse struct {
-	Token string      `json:"token"` // JWT token untuk autentikasi
-	User  models.User `json:"user"`  // Data user yang login
+	Tokens        TokenPair       `json:"tokens"`                   // Access token (JWT) + refresh token
+	User          models.User     `json:"user"`                     // Data user yang login
+	Challenge     *LoginChallenge `json:"challenge,omitempty"`      // Langkah kedua login (2FA)
+	RecoveryCodes []string        `json:"recovery_codes,omitempty"` // Hanya setelah enrolment 2FA saat login
 }
 
 // RoleResponse adalah struktur untuk menampilkan role yang tersedia untuk registrasi
@@ -41,7 +45,7 @@ type RoleResponse struct {
 }
 
 // RegisterUser - Proses registrasi user baru
-// Alur: Validasi role -> Hash password -> Simpan ke database
+// Alur: Validasi role -> Hash password -> Simpan ke database -> Kirim email verifikasi
 func (s *AuthService) RegisterUser(input RegisterInput) error {
 	// 1. Parse dan validasi UUID dari role_id
 	roleUUID, err := uuid.Parse(input.RoleID)
@@ -55,9 +59,9 @@ func (s *AuthService) RegisterUser(input RegisterInput) error {
 		return errors.New("role tidak ditemukan")
 	}
 
-	// 3. Proteksi: Tidak boleh register sebagai Admin melalui jalur publik
-	if role.Name == "Admin" {
-		return errors.New("registrasi admin dilarang melalui jalur publik")
+	// 3. Proteksi: Hanya role registrable (bukan Admin / role internal) yang boleh dipilih di jalur publik
+	if !role.Registrable {
+		return errors.New("role ini tidak bisa dipilih melalui registrasi publik")
 	}
 
 	// 4. Hash password menggunakan bcrypt untuk keamanan
@@ -72,56 +76,94 @@ func (s *AuthService) RegisterUser(input RegisterInput) error {
 		RoleID:   roleUUID, 
 	}
 
-	// 6. Simpan ke database
-	if err := database.DB.Create(&user).Error; err != nil {
+	// 6. Simpan ke database, role seller (permission seller.listings) langsung mendapat profil toko default
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		isSeller, err := roleHasPermission(tx, role.ID, models.PermSellerListings)
+		if err != nil || !isSeller {
+			return err
+		}
+		_, err = ensureSellerProfile(tx, user)
+		return err
+	})
+	if err != nil {
 		return errors.New("gagal register")
 	}
 
+	// 7. Kirim link verifikasi email, gagal kirim tidak membatalkan registrasi (bisa kirim ulang)
+	if err := sendEmailVerification(user, user.Email); err != nil {
+		log.Printf("[auth] gagal kirim email verifikasi ke %s: %v", user.Email, err)
+	}
+
 	return nil
 }
 
 // LoginUser - Proses autentikasi user dan generate JWT token
-// Alur: Cek email -> Validasi password -> Generate JWT token
-func (s *AuthService) LoginUser(input LoginInput) (LoginResponse, error) {
+// Alur: Cek blokir login -> Cek email -> Validasi password -> (challenge 2FA) -> Generate access token + refresh token (sesi baru)
+func (s *AuthService) LoginUser(input LoginInput, meta ClientMeta) (LoginResponse, error) {
+	// 0. Tolak lebih dulu jika akun / IP sedang di-backoff atau dikunci (brute-force protection)
+	now := time.Now()
+	if err := checkLoginAllowed(input.Email, meta.IP, now); err != nil {
+		return LoginResponse{}, err
+	}
+
 	// 1. Cari user berdasarkan email dan load data role-nya
 	var user models.User
 	if err := database.DB.Preload("Role").Where("email = ?", input.Email).First(&user).Error; err != nil {
+		recordLoginFailure(input.Email, meta, nil, now)
 		return LoginResponse{}, errors.New("email atau password salah")
 	}
 
 	// 2. Verifikasi password dengan bcrypt
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
+		recordLoginFailure(input.Email, meta, &user.ID, now)
 		return LoginResponse{}, errors.New("email atau password salah")
 	}
+	if emailVerificationRequired() && user.EmailVerifiedAt == nil {
+		return LoginResponse{}, ErrEmailNotVerified
+	}
+	if err := accountStatusError(user, now); err != nil {
+		return LoginResponse{}, err
+	}
 
-	// 3. Generate JWT token dengan claims: user_id, role, expiry (24 jam)
-	// 3. Generate JWT token dengan claims: user_id, role, expiry (24 jam)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub":  user.ID,              // Subject: User ID
-		"role": user.Role.Name,       // Role untuk authorization
-		"exp":  time.Now().Add(time.Hour * 24).Unix(), // Expired dalam 24 jam
-	})
+	// 3. 2FA aktif (atau wajib untuk role ini): kembalikan challenge token,
+	// penghitung login gagal baru di-reset setelah kode 2FA benar
+	enabled, err := twoFactorEnabled(database.DB, user.ID)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	if enabled || twoFactorRequired(user.Role.Name) {
+		challenge, err := issueLoginChallenge(user, !enabled)
+		if err != nil {
+			return LoginResponse{}, err
+		}
+		return LoginResponse{Challenge: challenge, User: user}, nil
+	}
+	resetLoginFailures(input.Email)
 
-	// 4. Sign token dengan JWT_SECRET dari environment variable
-	tokenString, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	// 4. Generate access token (claims: sub, role, jti, exp = ACCESS_TOKEN_TTL)
+	// dan refresh token sebagai awal family (sesi) baru
+	tokens, _, err := issueTokenPair(database.DB, user, uuid.New(), meta)
 	if err != nil {
 		return LoginResponse{}, err
 	}
 
 	// 5. Return token dan data user
 	return LoginResponse{
-		Token: tokenString,
-		User:  user,
+		Tokens: tokens,
+		User:   user,
 	}, nil
 }
 
 // GetAvailableRoles - Mendapatkan list role yang tersedia untuk registrasi publik
-// Hanya mengembalikan role Seller dan Pelanggan (Admin tidak bisa register publik)
+// Hanya mengembalikan role registrable (default: Seller dan Pelanggan, Admin tidak bisa register publik)
 func (s *AuthService) GetAvailableRoles() ([]RoleResponse, error) {
 	var roles []models.Role
 	
-	// Ambil hanya role Seller dan Pelanggan (exclude Admin)
-	if err := database.DB.Where("name IN ?", []string{"Seller", "Pelanggan"}).Find(&roles).Error; err != nil {
+	// Ambil hanya role yang boleh dipilih saat registrasi (exclude Admin)
+	if err := database.DB.Where("registrable = ?", true).Order("name").Find(&roles).Error; err != nil {
 		return nil, err
 	}
 
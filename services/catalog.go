@@ -4,8 +4,11 @@ import (
 	"errors"
 	"technical-test-backend/database"
 	"technical-test-backend/models"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CatalogService struct{}
@@ -19,61 +22,146 @@ type AddToEtalaseInput struct {
 // MarketplaceItem - Struktur data untuk tampilan marketplace
 type MarketplaceItem struct {
 	ID            uuid.UUID `json:"seller_product_id"` // ID produk di etalase seller
+	ProductID     uuid.UUID `json:"product_id"`        // ID produk master (gudang pusat)
+	SellerID      uuid.UUID `json:"seller_id"`         // ID seller
 	ProductName   string    `json:"product_name"`      // Nama produk
 	Category      string    `json:"category"`          // Kategori produk
 	SellerName    string    `json:"seller_name"`       // Nama toko seller
+	ShopSlug      string    `json:"shop_slug"`         // Slug halaman toko (/shops/:slug)
 	Price         float64   `json:"price"`             // Harga jual
 	StockTersedia int       `json:"stock_available"`   // Stok tersedia dari gudang pusat
+	InStock       bool      `json:"in_stock"`          // false = stok habis (hanya muncul dengan include_out_of_stock)
+
+	// Harga yang dibayar sekarang (harga flash sale jika kuota masih ada)
+	EffectivePrice float64 `json:"effective_price"`
+
+	// Harga coret ("was Rp X, now Rp Y"), null jika tidak ada penurunan harga baru-baru ini
+	WasPrice        *float64 `json:"was_price"`
+	DiscountPercent float64  `json:"discount_percent"`
+
+	// Flash sale yang sedang berjalan, null jika tidak ada
+	FlashSale *MarketplaceFlashSale `json:"flash_sale"`
+
+	// Rating ulasan pembeli untuk etalase ini (hanya ulasan yang tampil)
+	AverageRating float64 `json:"average_rating"`
+	ReviewCount   int64   `json:"review_count"`
 }
 
+// MarketplaceFlashSale - Harga promo & countdown flash sale di marketplace
+type MarketplaceFlashSale struct {
+	FlashSaleID     string    `json:"flash_sale_id"`
+	Name            string    `json:"name"`
+	PromoPrice      float64   `json:"promo_price"`
+	DiscountPercent float64   `json:"discount_percent"`
+	QuotaRemaining  int       `json:"quota_remaining"`
+	SoldOut         bool      `json:"sold_out"`
+	EndsAt          time.Time `json:"ends_at"`
+	EndsInSeconds   int64     `json:"ends_in_seconds"`
+}
+
+// Hasil AddToEtalase
+const (
+	EtalaseCreated     = "CREATED"     // Etalase baru
+	EtalaseReactivated = "REACTIVATED" // Etalase lama yang nonaktif diaktifkan kembali
+	EtalaseUpdated     = "UPDATED"     // Etalase sudah aktif, harga jual diperbarui
+)
+
 // AddToEtalase - Seller menambahkan produk dari gudang pusat ke marketplace mereka
-// Alur: Validasi produk exist -> Validasi harga jual >= harga modal -> Simpan ke SellerProduct
-func (s *CatalogService) AddToEtalase(sellerID string, input AddToEtalaseInput) (models.SellerProduct, error) {
+// Satu seller hanya punya satu etalase per produk: jika sudah ada, etalase tersebut
+// diaktifkan kembali / harga jualnya diperbarui (upsert), bukan dibuat baris baru.
+// Alur: Validasi produk exist -> Validasi harga jual >= harga modal -> Upsert SellerProduct
+func (s *CatalogService) AddToEtalase(sellerID string, input AddToEtalaseInput) (models.SellerProduct, string, error) {
 	sUUID, _ := uuid.Parse(sellerID)
 	pUUID, _ := uuid.Parse(input.ProductID)
 
 	// 1. Cek apakah produk master ada di gudang pusat
 	var master models.Product
 	if err := database.DB.First(&master, "id = ?", pUUID).Error; err != nil {
-		return models.SellerProduct{}, errors.New("master product not found")
+		return models.SellerProduct{}, "", errors.New("master product not found")
 	}
 
 	// 2. Validasi harga jual tidak boleh lebih rendah dari harga modal
 	// Selling Price = Harga Modal + Markup Seller
 	if input.SellingPrice < master.Price {
-		return models.SellerProduct{}, errors.New("selling price lower than capital price")
+		return models.SellerProduct{}, "", errors.New("selling price lower than capital price")
 	}
 
-	// 3. Simpan produk ke etalase seller (marketplace)
-	item := models.SellerProduct{
-		SellerID:     sUUID,
-		ProductID:    pUUID,
-		SellingPrice: input.SellingPrice,
-		IsActive:     true, // Default aktif
+	item := models.SellerProduct{}
+	action := EtalaseCreated
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// 3. Cari etalase yang sudah ada (di-lock agar request bersamaan tidak dobel)
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("seller_id = ? AND product_id = ?", sUUID, pUUID).Limit(1).Find(&item).Error
+		if err != nil {
+			return err
+		}
+
+		if item.ID == uuid.Nil {
+			// 4a. Simpan produk ke etalase seller (marketplace)
+			item = models.SellerProduct{
+				SellerID:     sUUID,
+				ProductID:    pUUID,
+				SellingPrice: input.SellingPrice,
+				IsActive:     true, // Default aktif
+			}
+			result := tx.Clauses(clause.OnConflict{
+				Columns:     []clause.Column{{Name: "seller_id"}, {Name: "product_id"}},
+				TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+				DoNothing:   true,
+			}).Create(&item)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 1 {
+				// Harga awal menjadi baris pertama histori harga
+				return recordInitialPrice(tx, item, &sUUID)
+			}
+			// Request lain baru saja membuat etalase yang sama, lanjut sebagai update
+			item = models.SellerProduct{}
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				First(&item, "seller_id = ? AND product_id = ?", sUUID, pUUID).Error; err != nil {
+				return err
+			}
+		}
+
+		// 4b. Etalase sudah ada: perbarui harga & aktifkan kembali
+		action = EtalaseUpdated
+		if !item.IsActive {
+			action = EtalaseReactivated
+		}
+		if err := changeSellingPrice(tx, &item, input.SellingPrice, models.PriceSourceManual, &sUUID, nil); err != nil {
+			return err
+		}
+		return tx.Model(&item).Update("is_active", true).Error
+	})
+	if err != nil {
+		return models.SellerProduct{}, "", err
 	}
-	err := database.DB.Create(&item).Error
-	return item, err
+	return item, action, nil
 }
 
 // GetMarketplaceItems - Get semua produk aktif di marketplace dengan filter
 // Filter: search (nama produk), category, price range (min-max)
-// Hanya menampilkan produk yang is_active = true
-func (s *CatalogService) GetMarketplaceItems(search string, categoryID string, minPrice float64, maxPrice float64) ([]MarketplaceItem, error) {
+// Hanya menampilkan produk yang is_active = true, produk stok habis hanya jika includeOutOfStock
+func (s *CatalogService) GetMarketplaceItems(search string, categoryID string, minPrice float64, maxPrice float64, includeOutOfStock bool) ([]MarketplaceItem, error) {
 	var items []models.SellerProduct
 	
-	// 1. Build query dengan base filter: hanya produk aktif
+	// 1. Build query dengan base filter: hanya produk aktif, seller yang sedang libur / ditangguhkan disembunyikan
 	query := database.DB.Preload("Product.ProductType").Preload("Seller").Where("is_active = ?", true)
+	query = excludeUnavailableSellers(query, time.Now())
 	
-	// 2. Filter pencarian berdasarkan nama produk (case insensitive)
+	// 2-3. Filter stok, nama produk (case insensitive) & kategori, join products cukup sekali
+	if search != "" || categoryID != "" || !includeOutOfStock {
+		query = query.Joins("JOIN products ON seller_products.product_id = products.id")
+	}
+	if !includeOutOfStock {
+		query = query.Where("products.stock > 0")
+	}
 	if search != "" {
-		query = query.Joins("JOIN products ON seller_products.product_id = products.id").
-			Where("products.name ILIKE ?", "%"+search+"%")
+		query = query.Where("products.name ILIKE ?", "%"+search+"%")
 	}
-	
-	// 3. Filter berdasarkan kategori produk
 	if categoryID != "" {
-		query = query.Joins("JOIN products ON seller_products.product_id = products.id").
-			Where("products.product_type_id = ?", categoryID)
+		query = query.Where("products.product_type_id = ?", categoryID)
 	}
 	
 	// 4. Filter berdasarkan range harga
@@ -85,22 +173,90 @@ func (s *CatalogService) GetMarketplaceItems(search string, categoryID string, m
 	}
 	
 	// 5. Execute query
-	if err := query.Find(&items).Error; err != nil {
+	if err := query.Order("seller_products.created_at").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return toMarketplaceItems(items)
+}
+
+// toMarketplaceItems - Susun item marketplace: harga coret, flash sale, harga efektif, rating.
+// Filter stok dilakukan pemanggil (query), item stok habis ditandai in_stock = false.
+func toMarketplaceItems(items []models.SellerProduct) ([]MarketplaceItem, error) {
+	// 1. Harga coret dari histori harga
+	listingIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		listingIDs = append(listingIDs, item.ID)
+	}
+	wasPrices, err := getWasPrices(listingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Flash sale yang sedang berjalan
+	now := time.Now()
+	flashItems, err := getRunningFlashSaleItems(database.DB, listingIDs, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Rating ulasan per etalase
+	ratings, err := getListingRatings(listingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Profil toko seller (nama toko & slug)
+	sellerIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		sellerIDs = append(sellerIDs, item.SellerID)
+	}
+	profiles, err := getSellerProfiles(sellerIDs)
+	if err != nil {
 		return nil, err
 	}
 
 	var result []MarketplaceItem
 	for _, item := range items {
-		if item.Product.Stock > 0 {
-			result = append(result, MarketplaceItem{
-				ID:            item.ID,
-				ProductName:   item.Product.Name,
-				Category:      item.Product.ProductType.Name,
-				SellerName:    item.Seller.Name,
-				Price:         item.SellingPrice,
-				StockTersedia: item.Product.Stock,
-			})
+		marketItem := MarketplaceItem{
+			ID:             item.ID,
+			ProductID:      item.ProductID,
+			SellerID:       item.SellerID,
+			ProductName:    item.Product.Name,
+			Category:       item.Product.ProductType.Name,
+			SellerName:     item.Seller.Name,
+			Price:          item.SellingPrice,
+			EffectivePrice: item.SellingPrice,
+			StockTersedia:  item.Product.Stock,
+			InStock:        item.Product.Stock > 0,
+			AverageRating:  ratings[item.ID].AverageRating,
+			ReviewCount:    ratings[item.ID].ReviewCount,
+		}
+		if profile, ok := profiles[item.SellerID]; ok {
+			marketItem.SellerName = profile.ShopName
+			marketItem.ShopSlug = profile.Slug
+		}
+		if was, ok := wasPrices[item.ID]; ok && was > item.SellingPrice {
+			marketItem.WasPrice = &was
+			marketItem.DiscountPercent = discountPercent(was, item.SellingPrice)
 		}
+		if flash, ok := flashItems[item.ID]; ok {
+			promo := flashSalePrice(flash.FlashSaleItem, item)
+			remaining := max(flash.Quota-flash.QuotaSold, 0)
+			marketItem.FlashSale = &MarketplaceFlashSale{
+				FlashSaleID:     flash.FlashSaleID.String(),
+				Name:            flash.FlashSaleName,
+				PromoPrice:      promo,
+				DiscountPercent: discountPercent(item.SellingPrice, promo),
+				QuotaRemaining:  remaining,
+				SoldOut:         remaining == 0,
+				EndsAt:          flash.FlashSaleEndAt,
+				EndsInSeconds:   int64(flash.FlashSaleEndAt.Sub(now).Seconds()),
+			}
+			if remaining > 0 {
+				marketItem.EffectivePrice = promo
+			}
+		}
+		result = append(result, marketItem)
 	}
 	return result, nil
 }
@@ -115,6 +271,9 @@ type SellerProductDetail struct {
 	ProfitMargin float64 `json:"profit_margin"`
 	Stock        int     `json:"stock"`
 	IsActive     bool    `json:"is_active"`
+	// Rating ulasan pembeli (hanya ulasan yang tampil)
+	AverageRating float64 `json:"average_rating"`
+	ReviewCount   int64   `json:"review_count"`
 }
 
 // Fungsi untuk melihat produk yang dijual oleh seller tertentu
@@ -128,6 +287,15 @@ func (s *CatalogService) GetSellerProducts(sellerID string) ([]SellerProductDeta
 		return nil, err
 	}
 
+	listingIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		listingIDs = append(listingIDs, item.ID)
+	}
+	ratings, err := getListingRatings(listingIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	var result []SellerProductDetail
 	for _, item := range items {
 		profitMargin := ((item.SellingPrice - item.Product.Price) / item.Product.Price) * 100
@@ -140,6 +308,9 @@ func (s *CatalogService) GetSellerProducts(sellerID string) ([]SellerProductDeta
 			ProfitMargin: profitMargin,
 			Stock:        item.Product.Stock,
 			IsActive:     item.IsActive,
+
+			AverageRating: ratings[item.ID].AverageRating,
+			ReviewCount:   ratings[item.ID].ReviewCount,
 		})
 	}
 	return result, nil
@@ -151,7 +322,8 @@ type UpdateSellerProductInput struct {
 	IsActive     *bool    `json:"is_active"`
 }
 
-func (s *CatalogService) UpdateSellerProduct(sellerProductID string, sellerID string, input UpdateSellerProductInput) (models.SellerProduct, error) {
+// UpdateSellerProduct - Perubahan harga jual / status etalase dicatat di audit log (before/after)
+func (s *CatalogService) UpdateSellerProduct(sellerProductID string, sellerID string, input UpdateSellerProductInput, audit *AuditContext) (models.SellerProduct, error) {
 	var sellerProduct models.SellerProduct
 	
 	// Check if seller product exists and belongs to the seller
@@ -166,14 +338,48 @@ func (s *CatalogService) UpdateSellerProduct(sellerProductID string, sellerID st
 		if *input.SellingPrice < sellerProduct.Product.Price {
 			return sellerProduct, errors.New("selling price cannot be lower than base price")
 		}
-		updates["selling_price"] = *input.SellingPrice
 	}
 	
 	if input.IsActive != nil {
+		// Etalase yang dinonaktifkan karena harga modal naik hanya bisa diaktifkan lagi
+		// jika harga jualnya (setelah update ini) tidak di bawah harga modal
+		sellingPrice := sellerProduct.SellingPrice
+		if input.SellingPrice != nil {
+			sellingPrice = *input.SellingPrice
+		}
+		if *input.IsActive && sellingPrice < sellerProduct.Product.Price {
+			return sellerProduct, errors.New("selling price is below base price, raise the selling price before activating")
+		}
 		updates["is_active"] = *input.IsActive
 	}
 
-	if err := database.DB.Model(&sellerProduct).Updates(updates).Error; err != nil {
+	before := map[string]interface{}{"selling_price": sellerProduct.SellingPrice, "is_active": sellerProduct.IsActive}
+	after := map[string]interface{}{}
+	for key, value := range updates {
+		after[key] = value
+	}
+	if input.SellingPrice != nil {
+		after["selling_price"] = *input.SellingPrice
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Perubahan harga dicatat di histori harga
+		if input.SellingPrice != nil {
+			actorID := parseOptionalUUID(sellerID)
+			if err := changeSellingPrice(tx, &sellerProduct, *input.SellingPrice, models.PriceSourceManual, actorID, nil); err != nil {
+				return err
+			}
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&sellerProduct).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		changes := auditChanges(before, after)
+		changes["product_id"] = sellerProduct.ProductID
+		return audit.Record(tx, models.AuditSellerProductUpdated, "seller_product", &sellerProduct.ID, changes)
+	})
+	if err != nil {
 		return sellerProduct, err
 	}
 
@@ -4,6 +4,8 @@ import (
 	"technical-test-backend/database"
 	"technical-test-backend/models"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type DashboardService struct{}
@@ -54,6 +56,20 @@ type AdminDashboard struct {
 	TotalCustomers     int64   `json:"total_customers"`
 	TransactionsToday  int64   `json:"transactions_today"`
 	PlatformIncome     float64 `json:"platform_income"`
+	Warehouses         []WarehouseSummary `json:"warehouses"`
+}
+
+// WarehouseSummary - Ringkasan stok per gudang untuk dashboard admin
+type WarehouseSummary struct {
+	WarehouseID   string `json:"warehouse_id"`
+	Code          string `json:"code"`
+	Name          string `json:"name"`
+	City          string `json:"city"`
+	IsDefault     bool   `json:"is_default"`
+	IsActive      bool   `json:"is_active"`
+	TotalStock    int64  `json:"total_stock"`
+	ProductCount  int64  `json:"product_count"`
+	LowStockCount int64  `json:"low_stock_count"` // Produk dengan stok <= 10 di gudang ini
 }
 
 // GetBuyerStats - Customer Dashboard
@@ -187,6 +203,15 @@ func (s *DashboardService) GetSellerStats(sellerID string) SellerDashboard {
 	return stats
 }
 
+// usersWithPermission - Query user yang role-nya memiliki permission
+func usersWithPermission(permission string) *gorm.DB {
+	return database.DB.Model(&models.User{}).
+		Where("role_id IN (?)", database.DB.Table("role_permissions rp").
+			Select("rp.role_id").
+			Joins("JOIN permissions p ON p.id = rp.permission_id").
+			Where("p.name = ? AND p.deleted_at IS NULL", permission))
+}
+
 // GetAdminStats - Admin Dashboard
 func (s *DashboardService) GetAdminStats() AdminDashboard {
 	var stats AdminDashboard
@@ -197,17 +222,11 @@ func (s *DashboardService) GetAdminStats() AdminDashboard {
 	// Total product types
 	database.DB.Model(&models.ProductType{}).Count(&stats.TotalProductTypes)
 	
-	// Total sellers (count users with Seller role)
-	database.DB.Table("users").
-		Joins("JOIN roles ON users.role_id = roles.id").
-		Where("roles.name = ?", "Seller").
-		Count(&stats.TotalSellers)
+	// Total sellers (count users whose role has seller.listings)
+	usersWithPermission(models.PermSellerListings).Count(&stats.TotalSellers)
 	
-	// Total customers (count users with Pelanggan role)
-	database.DB.Table("users").
-		Joins("JOIN roles ON users.role_id = roles.id").
-		Where("roles.name = ?", "Pelanggan").
-		Count(&stats.TotalCustomers)
+	// Total customers (count users whose role has orders.place)
+	usersWithPermission(models.PermOrdersPlace).Count(&stats.TotalCustomers)
 	
 	// Transactions today
 	today := time.Now().Format("2006-01-02")
@@ -220,6 +239,25 @@ func (s *DashboardService) GetAdminStats() AdminDashboard {
 		Where("status = ?", models.StatusCompleted).
 		Select("COALESCE(SUM(admin_fee), 0)").
 		Scan(&stats.PlatformIncome)
+
+	// Breakdown stok per gudang
+	database.DB.Table("warehouses").
+		Select(`
+			warehouses.id as warehouse_id,
+			warehouses.code,
+			warehouses.name,
+			warehouses.city,
+			warehouses.is_default,
+			warehouses.is_active,
+			COALESCE(SUM(warehouse_stocks.quantity), 0) as total_stock,
+			COUNT(warehouse_stocks.id) FILTER (WHERE warehouse_stocks.quantity > 0) as product_count,
+			COUNT(warehouse_stocks.id) FILTER (WHERE warehouse_stocks.quantity <= 10) as low_stock_count
+		`).
+		Joins("LEFT JOIN warehouse_stocks ON warehouse_stocks.warehouse_id = warehouses.id AND warehouse_stocks.product_id IN (SELECT id FROM products WHERE deleted_at IS NULL)").
+		Where("warehouses.deleted_at IS NULL").
+		Group("warehouses.id").
+		Order("warehouses.is_default DESC, warehouses.name ASC").
+		Scan(&stats.Warehouses)
 	
 	return stats
 }
\ No newline at end of file
@@ -0,0 +1,401 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FlashSaleService menangani kampanye flash sale (admin) dan harga promo di marketplace
+type FlashSaleService struct{}
+
+// ErrFlashSaleConflict - Etalase sudah ikut flash sale lain di jendela waktu yang bentrok (HTTP 409)
+var ErrFlashSaleConflict = errors.New("seller product is already in another flash sale")
+
+// ErrInvalidFlashSaleStatus - Aksi tidak diizinkan pada status flash sale saat ini (HTTP 409)
+var ErrInvalidFlashSaleStatus = errors.New("invalid flash sale status")
+
+// ErrFlashSaleQuotaExceeded - Sisa kuota flash sale kurang dari quantity order (HTTP 409)
+var ErrFlashSaleQuotaExceeded = errors.New("flash sale quota exceeded")
+
+// FlashSaleItemInput - Etalase peserta: isi salah satu promo_price atau discount_percent
+type FlashSaleItemInput struct {
+	SellerProductID string   `json:"seller_product_id" binding:"required"`
+	PromoPrice      *float64 `json:"promo_price" binding:"omitempty,gt=0"`
+	DiscountPercent *float64 `json:"discount_percent" binding:"omitempty,gt=0,lt=100"`
+	Quota           int      `json:"quota" binding:"required,min=1"`
+}
+
+// FlashSaleInput - Input create/update flash sale
+type FlashSaleInput struct {
+	Name        string               `json:"name" binding:"required,max=100"`
+	Description string               `json:"description"`
+	StartAt     time.Time            `json:"start_at" binding:"required" example:"2025-02-02T12:00:00+07:00"`
+	EndAt       time.Time            `json:"end_at" binding:"required" example:"2025-02-02T14:00:00+07:00"`
+	Items       []FlashSaleItemInput `json:"items" binding:"required,min=1,dive"`
+}
+
+// FlashSaleItemDetail - Response etalase peserta flash sale
+type FlashSaleItemDetail struct {
+	ID              string  `json:"id"`
+	SellerProductID string  `json:"seller_product_id"`
+	ProductName     string  `json:"product_name"`
+	SellerName      string  `json:"seller_name"`
+	NormalPrice     float64 `json:"normal_price"`
+	PromoPrice      float64 `json:"promo_price"`
+	DiscountPercent float64 `json:"discount_percent"`
+	Quota           int     `json:"quota"`
+	Sold            int     `json:"sold"`
+	Remaining       int     `json:"remaining"`
+}
+
+// FlashSaleDetail - Response flash sale beserta countdown
+type FlashSaleDetail struct {
+	ID              string                `json:"id"`
+	Name            string                `json:"name"`
+	Description     string                `json:"description"`
+	StartAt         time.Time             `json:"start_at"`
+	EndAt           time.Time             `json:"end_at"`
+	StoppedAt       *time.Time            `json:"stopped_at"`
+	Status          string                `json:"status"`
+	StartsInSeconds int64                 `json:"starts_in_seconds"`
+	EndsInSeconds   int64                 `json:"ends_in_seconds"`
+	Items           []FlashSaleItemDetail `json:"items"`
+}
+
+// flashSalePrice - Harga promo efektif. Tidak pernah di bawah harga modal,
+// sehingga seller tidak rugi jika harga modal naik setelah kampanye dibuat.
+func flashSalePrice(item models.FlashSaleItem, listing models.SellerProduct) float64 {
+	price := listing.SellingPrice
+	if item.PromoPrice != nil {
+		price = *item.PromoPrice
+	} else if item.DiscountPercent != nil {
+		price = math.Round(listing.SellingPrice*(100-*item.DiscountPercent)) / 100
+	}
+	if price < listing.Product.Price {
+		price = listing.Product.Price
+	}
+	return price
+}
+
+func discountPercent(normal float64, promo float64) float64 {
+	if normal <= 0 || promo >= normal {
+		return 0
+	}
+	return math.Round((normal-promo)/normal*10000) / 100
+}
+
+// runningFlashSaleItem - Item flash sale yang sedang berjalan beserta info kampanyenya
+type runningFlashSaleItem struct {
+	models.FlashSaleItem
+	FlashSaleName  string
+	FlashSaleEndAt time.Time
+}
+
+// getRunningFlashSaleItems - Item flash sale yang sedang berjalan per etalase
+func getRunningFlashSaleItems(tx *gorm.DB, listingIDs []uuid.UUID, now time.Time) (map[uuid.UUID]runningFlashSaleItem, error) {
+	result := make(map[uuid.UUID]runningFlashSaleItem)
+	if len(listingIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []runningFlashSaleItem
+	err := tx.Table("flash_sale_items").
+		Select("flash_sale_items.*, flash_sales.name AS flash_sale_name, flash_sales.end_at AS flash_sale_end_at").
+		Joins("JOIN flash_sales ON flash_sales.id = flash_sale_items.flash_sale_id AND flash_sales.deleted_at IS NULL").
+		Where("flash_sale_items.seller_product_id IN ? AND flash_sale_items.deleted_at IS NULL", listingIDs).
+		Where("flash_sales.start_at <= ? AND flash_sales.end_at > ?", now, now).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.SellerProductID] = row
+	}
+	return result, nil
+}
+
+// reserveFlashSaleQuota - Naikkan quota_sold secara atomic. UPDATE bersyarat di Postgres
+// dievaluasi ulang setelah row lock dilepas, jadi pembeli bersamaan tidak bisa melebihi kuota.
+func reserveFlashSaleQuota(tx *gorm.DB, itemID uuid.UUID, quantity int) (bool, error) {
+	result := tx.Model(&models.FlashSaleItem{}).
+		Where("id = ? AND quota_sold + ? <= quota", itemID, quantity).
+		UpdateColumn("quota_sold", gorm.Expr("quota_sold + ?", quantity))
+	return result.RowsAffected == 1, result.Error
+}
+
+// releaseFlashSaleQuota - Kembalikan kuota saat order flash sale dibatalkan
+func releaseFlashSaleQuota(tx *gorm.DB, itemID uuid.UUID, quantity int) error {
+	return tx.Model(&models.FlashSaleItem{}).
+		Where("id = ?", itemID).
+		UpdateColumn("quota_sold", gorm.Expr("GREATEST(quota_sold - ?, 0)", quantity)).Error
+}
+
+// GetAll - Daftar flash sale (admin), filter status SCHEDULED / RUNNING / ENDED
+func (s *FlashSaleService) GetAll(status string) ([]FlashSaleDetail, error) {
+	now := time.Now()
+	sales, err := findFlashSales(status, now, "start_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	result := []FlashSaleDetail{}
+	for _, sale := range sales {
+		result = append(result, toFlashSaleDetail(sale, now))
+	}
+	return result, nil
+}
+
+// GetRunning - Flash sale yang sedang berjalan untuk pembeli (berakhir paling cepat dulu),
+// hanya berisi etalase yang aktif
+func (s *FlashSaleService) GetRunning() ([]FlashSaleDetail, error) {
+	now := time.Now()
+	sales, err := findFlashSales(models.FlashSaleRunning, now, "end_at")
+	if err != nil {
+		return nil, err
+	}
+	result := []FlashSaleDetail{}
+	for _, sale := range sales {
+		activeItems := []models.FlashSaleItem{}
+		for _, item := range sale.Items {
+			// Etalase seller yang ditangguhkan / nonaktif ikut disembunyikan
+			if item.SellerProduct.IsActive && accountStatusError(item.SellerProduct.Seller, now) == nil {
+				activeItems = append(activeItems, item)
+			}
+		}
+		sale.Items = activeItems
+		result = append(result, toFlashSaleDetail(sale, now))
+	}
+	return result, nil
+}
+
+func findFlashSales(status string, now time.Time, order string) ([]models.FlashSale, error) {
+	query := database.DB.Preload("Items.SellerProduct.Product").Preload("Items.SellerProduct.Seller")
+	switch status {
+	case "":
+	case models.FlashSaleScheduled:
+		query = query.Where("start_at > ?", now)
+	case models.FlashSaleRunning:
+		query = query.Where("start_at <= ? AND end_at > ?", now, now)
+	case models.FlashSaleEnded:
+		query = query.Where("end_at <= ?", now)
+	default:
+		return nil, errors.New("status must be SCHEDULED, RUNNING or ENDED")
+	}
+
+	var sales []models.FlashSale
+	err := query.Order(order).Find(&sales).Error
+	return sales, err
+}
+
+// GetByID - Detail flash sale
+func (s *FlashSaleService) GetByID(id string) (FlashSaleDetail, error) {
+	var sale models.FlashSale
+	err := database.DB.Preload("Items.SellerProduct.Product").Preload("Items.SellerProduct.Seller").
+		First(&sale, "id = ?", id).Error
+	if err != nil {
+		return FlashSaleDetail{}, err
+	}
+	return toFlashSaleDetail(sale, time.Now()), nil
+}
+
+func toFlashSaleDetail(sale models.FlashSale, now time.Time) FlashSaleDetail {
+	detail := FlashSaleDetail{
+		ID:          sale.ID.String(),
+		Name:        sale.Name,
+		Description: sale.Description,
+		StartAt:     sale.StartAt,
+		EndAt:       sale.EndAt,
+		StoppedAt:   sale.StoppedAt,
+		Status:      sale.Status(now),
+		Items:       []FlashSaleItemDetail{},
+	}
+	if detail.Status == models.FlashSaleScheduled {
+		detail.StartsInSeconds = int64(sale.StartAt.Sub(now).Seconds())
+	}
+	if detail.Status != models.FlashSaleEnded {
+		detail.EndsInSeconds = int64(sale.EndAt.Sub(now).Seconds())
+	}
+
+	for _, item := range sale.Items {
+		promo := flashSalePrice(item, item.SellerProduct)
+		remaining := item.Quota - item.QuotaSold
+		if remaining < 0 {
+			remaining = 0
+		}
+		detail.Items = append(detail.Items, FlashSaleItemDetail{
+			ID:              item.ID.String(),
+			SellerProductID: item.SellerProductID.String(),
+			ProductName:     item.SellerProduct.Product.Name,
+			SellerName:      item.SellerProduct.Seller.Name,
+			NormalPrice:     item.SellerProduct.SellingPrice,
+			PromoPrice:      promo,
+			DiscountPercent: discountPercent(item.SellerProduct.SellingPrice, promo),
+			Quota:           item.Quota,
+			Sold:            item.QuotaSold,
+			Remaining:       remaining,
+		})
+	}
+	return detail
+}
+
+// Create - Admin membuat flash sale
+// Alur: Validasi waktu -> Validasi tiap etalase (aktif, harga promo, bentrok kampanye lain) -> Simpan
+func (s *FlashSaleService) Create(actorID string, input FlashSaleInput) (FlashSaleDetail, error) {
+	if !input.EndAt.After(time.Now()) {
+		return FlashSaleDetail{}, errors.New("end_at must be in the future")
+	}
+
+	sale := models.FlashSale{
+		Name:        input.Name,
+		Description: input.Description,
+		StartAt:     input.StartAt,
+		EndAt:       input.EndAt,
+		CreatedByID: parseOptionalUUID(actorID),
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		items, err := buildFlashSaleItems(tx, uuid.Nil, input)
+		if err != nil {
+			return err
+		}
+		sale.Items = items
+		return tx.Create(&sale).Error
+	})
+	if err != nil {
+		return FlashSaleDetail{}, err
+	}
+	return s.GetByID(sale.ID.String())
+}
+
+// Update - Ubah flash sale yang belum dimulai, item diganti seluruhnya
+func (s *FlashSaleService) Update(id string, input FlashSaleInput) (FlashSaleDetail, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var sale models.FlashSale
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sale, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if status := sale.Status(time.Now()); status != models.FlashSaleScheduled {
+			return fmt.Errorf("%w: only SCHEDULED flash sales can be edited (current: %s)", ErrInvalidFlashSaleStatus, status)
+		}
+		if !input.StartAt.After(time.Now()) {
+			return errors.New("start_at must be in the future")
+		}
+
+		items, err := buildFlashSaleItems(tx, sale.ID, input)
+		if err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("flash_sale_id = ?", sale.ID).Delete(&models.FlashSaleItem{}).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].FlashSaleID = sale.ID
+		}
+		if err := tx.Create(&items).Error; err != nil {
+			return err
+		}
+		return tx.Model(&sale).Updates(map[string]interface{}{
+			"name":        input.Name,
+			"description": input.Description,
+			"start_at":    input.StartAt,
+			"end_at":      input.EndAt,
+		}).Error
+	})
+	if err != nil {
+		return FlashSaleDetail{}, err
+	}
+	return s.GetByID(id)
+}
+
+// Delete - Hapus flash sale yang belum dimulai (yang sudah berjalan gunakan Stop)
+func (s *FlashSaleService) Delete(id string) error {
+	var sale models.FlashSale
+	if err := database.DB.First(&sale, "id = ?", id).Error; err != nil {
+		return err
+	}
+	if status := sale.Status(time.Now()); status != models.FlashSaleScheduled {
+		return fmt.Errorf("%w: only SCHEDULED flash sales can be deleted (current: %s)", ErrInvalidFlashSaleStatus, status)
+	}
+	return database.DB.Select("Items").Delete(&sale).Error
+}
+
+// Stop - Hentikan flash sale yang sedang berjalan, end_at dimajukan ke sekarang
+func (s *FlashSaleService) Stop(id string) (FlashSaleDetail, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var sale models.FlashSale
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sale, "id = ?", id).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		if status := sale.Status(now); status != models.FlashSaleRunning {
+			return fmt.Errorf("%w: only RUNNING flash sales can be stopped (current: %s)", ErrInvalidFlashSaleStatus, status)
+		}
+		return tx.Model(&sale).Updates(map[string]interface{}{"end_at": now, "stopped_at": now}).Error
+	})
+	if err != nil {
+		return FlashSaleDetail{}, err
+	}
+	return s.GetByID(id)
+}
+
+// buildFlashSaleItems - Validasi item input. saleID = flash sale yang sedang diedit (dikecualikan dari cek bentrok).
+func buildFlashSaleItems(tx *gorm.DB, saleID uuid.UUID, input FlashSaleInput) ([]models.FlashSaleItem, error) {
+	if !input.EndAt.After(input.StartAt) {
+		return nil, errors.New("end_at must be after start_at")
+	}
+
+	seen := make(map[string]bool)
+	items := make([]models.FlashSaleItem, 0, len(input.Items))
+	for _, in := range input.Items {
+		if (in.PromoPrice == nil) == (in.DiscountPercent == nil) {
+			return nil, fmt.Errorf("seller product %s: set either promo_price or discount_percent", in.SellerProductID)
+		}
+		if seen[in.SellerProductID] {
+			return nil, fmt.Errorf("seller product %s is listed more than once", in.SellerProductID)
+		}
+		seen[in.SellerProductID] = true
+
+		var listing models.SellerProduct
+		if err := tx.Preload("Product").First(&listing, "id = ?", in.SellerProductID).Error; err != nil {
+			return nil, fmt.Errorf("seller product %s not found", in.SellerProductID)
+		}
+		if !listing.IsActive {
+			return nil, fmt.Errorf("seller product %s is not active", in.SellerProductID)
+		}
+
+		item := models.FlashSaleItem{
+			SellerProductID: listing.ID,
+			PromoPrice:      in.PromoPrice,
+			DiscountPercent: in.DiscountPercent,
+			Quota:           in.Quota,
+		}
+		if in.PromoPrice != nil {
+			if *in.PromoPrice >= listing.SellingPrice {
+				return nil, fmt.Errorf("seller product %s: promo_price must be lower than the selling price %.2f", in.SellerProductID, listing.SellingPrice)
+			}
+			if *in.PromoPrice < listing.Product.Price {
+				return nil, fmt.Errorf("seller product %s: promo_price cannot be lower than base price %.2f", in.SellerProductID, listing.Product.Price)
+			}
+		} else if math.Round(listing.SellingPrice*(100-*in.DiscountPercent))/100 < listing.Product.Price {
+			return nil, fmt.Errorf("seller product %s: discount would put the price below base price %.2f", in.SellerProductID, listing.Product.Price)
+		}
+
+		var conflicts int64
+		tx.Model(&models.FlashSaleItem{}).
+			Joins("JOIN flash_sales ON flash_sales.id = flash_sale_items.flash_sale_id AND flash_sales.deleted_at IS NULL").
+			Where("flash_sale_items.seller_product_id = ? AND flash_sales.id <> ?", listing.ID, saleID).
+			Where("flash_sales.start_at < ? AND flash_sales.end_at > ?", input.EndAt, input.StartAt).
+			Count(&conflicts)
+		if conflicts > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrFlashSaleConflict, in.SellerProductID)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginAttemptState - Jumlah login gagal dan blokir yang berlaku untuk satu key (akun / IP)
+type LoginAttemptState struct {
+	Failures     int
+	LastFailedAt time.Time
+	BlockedUntil time.Time // Zero = tidak diblokir
+	LockedOut    bool      // true = batas gagal tercapai (lockout), false = hanya backoff
+}
+
+// LoginAttemptStore - Penyimpanan penghitung login gagal.
+// Update harus atomic per key: fn menerima state terakhir dan mengembalikan state baru.
+type LoginAttemptStore interface {
+	Get(key string) (LoginAttemptState, error)
+	Update(key string, fn func(state LoginAttemptState) LoginAttemptState) (LoginAttemptState, error)
+	Reset(key string) error
+	// Purge - Hapus key yang gagal terakhir sebelum waktu tertentu dan sudah tidak diblokir
+	Purge(before time.Time) (int64, error)
+}
+
+var (
+	loginAttemptStoreInstance LoginAttemptStore
+	loginAttemptStoreOnce     sync.Once
+)
+
+// loginAttemptStore - Store sesuai LOGIN_ATTEMPT_STORE: postgres (default, dipakai bersama
+// semua instance) atau memory (hanya satu instance, hilang saat restart)
+func loginAttemptStore() LoginAttemptStore {
+	loginAttemptStoreOnce.Do(func() {
+		switch strings.ToLower(utils.EnvString("LOGIN_ATTEMPT_STORE", "postgres")) {
+		case "memory":
+			loginAttemptStoreInstance = NewMemoryLoginAttemptStore()
+		case "postgres":
+			loginAttemptStoreInstance = PostgresLoginAttemptStore{}
+		default:
+			log.Printf("[auth] unknown LOGIN_ATTEMPT_STORE, falling back to postgres")
+			loginAttemptStoreInstance = PostgresLoginAttemptStore{}
+		}
+	})
+	return loginAttemptStoreInstance
+}
+
+// MemoryLoginAttemptStore - Penghitung di memori proses
+type MemoryLoginAttemptStore struct {
+	mu      sync.Mutex
+	entries map[string]LoginAttemptState
+}
+
+// NewMemoryLoginAttemptStore - Store memori kosong
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{entries: map[string]LoginAttemptState{}}
+}
+
+// Get - State untuk key (zero value jika belum pernah gagal)
+func (s *MemoryLoginAttemptStore) Get(key string) (LoginAttemptState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries[key], nil
+}
+
+// Update - Terapkan fn ke state key di bawah mutex
+func (s *MemoryLoginAttemptStore) Update(key string, fn func(state LoginAttemptState) LoginAttemptState) (LoginAttemptState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := fn(s.entries[key])
+	s.entries[key] = state
+	return state, nil
+}
+
+// Reset - Hapus penghitung key
+func (s *MemoryLoginAttemptStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// Purge - Hapus key usang agar map tidak tumbuh terus
+func (s *MemoryLoginAttemptStore) Purge(before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var purged int64
+	for key, state := range s.entries {
+		if state.LastFailedAt.Before(before) && state.BlockedUntil.Before(before) {
+			delete(s.entries, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// PostgresLoginAttemptStore - Penghitung di tabel login_attempts, update memakai row lock
+type PostgresLoginAttemptStore struct{}
+
+// Get - State untuk key (zero value jika belum pernah gagal)
+func (PostgresLoginAttemptStore) Get(key string) (LoginAttemptState, error) {
+	var row models.LoginAttempt
+	if err := database.DB.Where("key = ?", key).Limit(1).Find(&row).Error; err != nil {
+		return LoginAttemptState{}, err
+	}
+	return loginAttemptStateFromRow(row), nil
+}
+
+// Update - Buat baris jika belum ada, kunci, lalu simpan hasil fn
+func (PostgresLoginAttemptStore) Update(key string, fn func(state LoginAttemptState) LoginAttemptState) (LoginAttemptState, error) {
+	var state LoginAttemptState
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.LoginAttempt{Key: key}).Error; err != nil {
+			return err
+		}
+		var row models.LoginAttempt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&row, "key = ?", key).Error; err != nil {
+			return err
+		}
+
+		state = fn(loginAttemptStateFromRow(row))
+		var blockedUntil *time.Time
+		if !state.BlockedUntil.IsZero() {
+			blockedUntil = &state.BlockedUntil
+		}
+		return tx.Model(&row).Updates(map[string]interface{}{
+			"failures":       state.Failures,
+			"last_failed_at": state.LastFailedAt,
+			"blocked_until":  blockedUntil,
+			"locked_out":     state.LockedOut,
+		}).Error
+	})
+	return state, err
+}
+
+// Reset - Hapus permanen penghitung key
+func (PostgresLoginAttemptStore) Reset(key string) error {
+	return database.DB.Unscoped().Where("key = ?", key).Delete(&models.LoginAttempt{}).Error
+}
+
+// Purge - Hapus permanen key usang
+func (PostgresLoginAttemptStore) Purge(before time.Time) (int64, error) {
+	result := database.DB.Unscoped().
+		Where("last_failed_at < ? AND (blocked_until IS NULL OR blocked_until < ?)", before, before).
+		Delete(&models.LoginAttempt{})
+	return result.RowsAffected, result.Error
+}
+
+func loginAttemptStateFromRow(row models.LoginAttempt) LoginAttemptState {
+	state := LoginAttemptState{
+		Failures:     row.Failures,
+		LastFailedAt: row.LastFailedAt,
+		LockedOut:    row.LockedOut,
+	}
+	if row.BlockedUntil != nil {
+		state.BlockedUntil = *row.BlockedUntil
+	}
+	return state
+}
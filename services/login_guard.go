@@ -0,0 +1,175 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrTooManyLoginAttempts - Login diblokir sementara (backoff / lockout) (HTTP 429)
+var ErrTooManyLoginAttempts = errors.New("too many failed login attempts")
+
+// LoginThrottledError - Login ditolak karena backoff / lockout, RetryAfter untuk header Retry-After
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	LockedOut  bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.LockedOut {
+		return fmt.Sprintf("terlalu banyak percobaan login gagal, login dikunci sementara. Coba lagi dalam %s", e.RetryAfter)
+	}
+	return fmt.Sprintf("terlalu banyak percobaan login gagal, coba lagi dalam %s", e.RetryAfter)
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+// loginLimit - Batas login gagal untuk satu jenis key.
+// Gagal ke-1..FreeFailures tanpa jeda, setelahnya backoff eksponensial
+// (LOGIN_BACKOFF_BASE x 2^n, maks LOGIN_BACKOFF_MAX), gagal ke-MaxFailures = lockout.
+type loginLimit struct {
+	Scope        string // "account" / "ip"
+	MaxFailures  int
+	FreeFailures int
+}
+
+// accountLoginLimit - Per email (LOGIN_MAX_ATTEMPTS, default 5)
+func accountLoginLimit() loginLimit {
+	return loginLimit{Scope: "account", MaxFailures: max(utils.EnvInt("LOGIN_MAX_ATTEMPTS", 5), 1), FreeFailures: 1}
+}
+
+// ipLoginLimit - Per IP (LOGIN_MAX_ATTEMPTS_PER_IP, default 20), lebih longgar karena IP bisa dipakai bersama (NAT)
+func ipLoginLimit() loginLimit {
+	maxFailures := max(utils.EnvInt("LOGIN_MAX_ATTEMPTS_PER_IP", 20), 1)
+	return loginLimit{Scope: "ip", MaxFailures: maxFailures, FreeFailures: maxFailures / 2}
+}
+
+// loginAttemptWindow - Kegagalan yang lebih lama dari ini tidak dihitung lagi (LOGIN_ATTEMPT_WINDOW, default 15m)
+func loginAttemptWindow() time.Duration {
+	return utils.EnvDuration("LOGIN_ATTEMPT_WINDOW", 15*time.Minute)
+}
+
+// loginKey - Key penghitung untuk scope, email dinormalisasi huruf kecil
+func loginKey(scope string, value string) string {
+	return scope + ":" + strings.ToLower(strings.TrimSpace(value))
+}
+
+// next - State baru setelah satu login gagal pada waktu now
+func (l loginLimit) next(state LoginAttemptState, now time.Time) LoginAttemptState {
+	// Lockout yang sudah berakhir atau kegagalan lama tidak dihitung lagi
+	if (state.LockedOut && !now.Before(state.BlockedUntil)) || now.Sub(state.LastFailedAt) > loginAttemptWindow() {
+		state = LoginAttemptState{}
+	}
+
+	state.Failures++
+	state.LastFailedAt = now
+	state.LockedOut = false
+	state.BlockedUntil = time.Time{}
+	switch {
+	case state.Failures >= l.MaxFailures:
+		state.LockedOut = true
+		state.BlockedUntil = now.Add(utils.EnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute))
+	case state.Failures > l.FreeFailures:
+		base := utils.EnvDuration("LOGIN_BACKOFF_BASE", time.Second)
+		maxBackoff := utils.EnvDuration("LOGIN_BACKOFF_MAX", 30*time.Second)
+		exponent := math.Min(float64(state.Failures-l.FreeFailures-1), 30)
+		backoff := time.Duration(float64(base) * math.Pow(2, exponent))
+		state.BlockedUntil = now.Add(min(backoff, maxBackoff))
+	}
+	return state
+}
+
+// checkLoginAllowed - Tolak login jika akun atau IP sedang di-backoff / dikunci.
+// Store error tidak memblokir login (fail open), hanya di-log.
+func checkLoginAllowed(email string, ip string, now time.Time) error {
+	var throttled *LoginThrottledError
+	for _, key := range loginKeys(email, ip) {
+		state, err := loginAttemptStore().Get(key)
+		if err != nil {
+			log.Printf("[auth] login attempt store: %v", err)
+			continue
+		}
+		if !now.Before(state.BlockedUntil) {
+			continue
+		}
+		retryAfter := state.BlockedUntil.Sub(now).Round(time.Second)
+		if retryAfter < time.Second {
+			retryAfter = time.Second
+		}
+		if throttled == nil || retryAfter > throttled.RetryAfter {
+			throttled = &LoginThrottledError{RetryAfter: retryAfter, LockedOut: state.LockedOut}
+		}
+	}
+	if throttled != nil {
+		return throttled
+	}
+	return nil
+}
+
+// loginKeys - Key akun & IP untuk satu percobaan login
+func loginKeys(email string, ip string) []string {
+	keys := []string{loginKey("account", email)}
+	if ip != "" {
+		keys = append(keys, loginKey("ip", ip))
+	}
+	return keys
+}
+
+// recordLoginFailure - Tambah penghitung akun & IP. Saat batas tercapai (lockout baru),
+// kejadian dicatat di audit log. userID nil jika email tidak terdaftar.
+func recordLoginFailure(email string, meta ClientMeta, userID *uuid.UUID, now time.Time) {
+	limits := []loginLimit{accountLoginLimit(), ipLoginLimit()}
+	for i, key := range loginKeys(email, meta.IP) {
+		limit := limits[i]
+		state, err := loginAttemptStore().Update(key, func(state LoginAttemptState) LoginAttemptState {
+			return limit.next(state, now)
+		})
+		if err != nil {
+			log.Printf("[auth] login attempt store: %v", err)
+			continue
+		}
+		if !state.LockedOut || state.Failures != limit.MaxFailures {
+			continue
+		}
+
+		// Key mentah (email / IP) tidak disimpan: audit log append-only, email yang diketik untuk akun
+		// tidak terdaftar tidak boleh tersimpan permanen. Akun dikenali dari target, IP dari kolom IP,
+		// email tidak terdaftar hanya sebagai hash (untuk mengelompokkan lockout yang sama).
+		data := map[string]interface{}{
+			"scope":        limit.Scope,
+			"failures":     state.Failures,
+			"locked_until": state.BlockedUntil,
+			"user_agent":   meta.UserAgent,
+		}
+		var targetID *uuid.UUID
+		targetType := "login_ip"
+		if limit.Scope == "account" {
+			targetID = userID
+			targetType = "user"
+			if userID == nil {
+				data["key_hash"] = hashToken(key)
+			}
+		}
+		if err := createAuditLog(database.DB, nil, models.AuditLoginLockout, targetType, targetID, meta.IP, data); err != nil {
+			log.Printf("[auth] audit log: %v", err)
+		}
+	}
+}
+
+// resetLoginFailures - Login berhasil: hapus penghitung akun.
+// Penghitung IP tidak di-reset agar login ke akun sendiri tidak membuka blokir IP.
+func resetLoginFailures(email string) {
+	if err := loginAttemptStore().Reset(loginKey("account", email)); err != nil {
+		log.Printf("[auth] login attempt store: %v", err)
+	}
+}
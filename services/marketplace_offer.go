@@ -0,0 +1,215 @@
+package services
+
+import (
+	"sort"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MarketplaceOffer - Penawaran satu seller untuk produk master
+type MarketplaceOffer struct {
+	MarketplaceItem
+	SellerCompletedSales int64   `json:"seller_completed_sales"` // Jumlah order COMPLETED seller (semua produk)
+	SellerRating         float64 `json:"seller_rating"`          // Rata-rata rating seller (semua etalase)
+	SellerReviewCount    int64   `json:"seller_review_count"`    // Jumlah ulasan seller (semua etalase)
+	IsBuyBox             bool    `json:"is_buy_box"`             // Penawaran terbaik (ditampilkan paling menonjol)
+}
+
+// ProductOffers - Semua penawaran aktif untuk satu produk master
+type ProductOffers struct {
+	ProductID      string             `json:"product_id"`
+	ProductName    string             `json:"product_name"`
+	Category       string             `json:"category"`
+	StockAvailable int                `json:"stock_available"`
+	OfferCount     int                `json:"offer_count"`
+	LowestPrice    float64            `json:"lowest_price"`
+	HighestPrice   float64            `json:"highest_price"`
+	BuyBox         *MarketplaceOffer  `json:"buy_box"`
+	Offers         []MarketplaceOffer `json:"offers"`
+}
+
+// MarketplaceProductGroup - Satu baris marketplace per produk master (mode grouped)
+type MarketplaceProductGroup struct {
+	ProductID      string           `json:"product_id"`
+	ProductName    string           `json:"product_name"`
+	Category       string           `json:"category"`
+	StockAvailable int              `json:"stock_available"`
+	OfferCount     int              `json:"offer_count"`
+	LowestPrice    float64          `json:"lowest_price"`
+	HighestPrice   float64          `json:"highest_price"`
+	BuyBox         MarketplaceOffer `json:"buy_box"`
+}
+
+// sellerScore - Reputasi seller untuk urutan penawaran
+type sellerScore struct {
+	CompletedSales int64
+	AverageRating  float64
+	ReviewCount    int64
+}
+
+// getSellerScores - Jumlah order COMPLETED dan rating ulasan per seller
+func getSellerScores(sellerIDs []uuid.UUID) (map[uuid.UUID]sellerScore, error) {
+	result := make(map[uuid.UUID]sellerScore)
+	if len(sellerIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		SellerID       uuid.UUID
+		CompletedSales int64
+	}
+	err := database.DB.Table("transactions").
+		Select("seller_products.seller_id, COUNT(*) AS completed_sales").
+		Joins("JOIN seller_products ON seller_products.id = transactions.seller_product_id").
+		Where("transactions.status = ? AND transactions.deleted_at IS NULL", models.StatusCompleted).
+		Where("seller_products.seller_id IN ?", sellerIDs).
+		Group("seller_products.seller_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.SellerID] = sellerScore{CompletedSales: row.CompletedSales}
+	}
+
+	ratings, err := getSellerRatings(sellerIDs)
+	if err != nil {
+		return nil, err
+	}
+	for sellerID, rating := range ratings {
+		score := result[sellerID]
+		score.AverageRating = rating.AverageRating
+		score.ReviewCount = rating.ReviewCount
+		result[sellerID] = score
+	}
+	return result, nil
+}
+
+// getItemSellerScores - Reputasi semua seller pada daftar item marketplace
+func getItemSellerScores(items []MarketplaceItem) (map[uuid.UUID]sellerScore, error) {
+	sellerIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		sellerIDs = append(sellerIDs, item.SellerID)
+	}
+	return getSellerScores(sellerIDs)
+}
+
+// rankOffers - Urutkan penawaran: harga efektif termurah, lalu rating seller tertinggi,
+// lalu order COMPLETED terbanyak, lalu etalase paling lama. Penawaran pertama menjadi buy box.
+func rankOffers(items []MarketplaceItem, scores map[uuid.UUID]sellerScore) []MarketplaceOffer {
+	offers := make([]MarketplaceOffer, 0, len(items))
+	for _, item := range items {
+		score := scores[item.SellerID]
+		offers = append(offers, MarketplaceOffer{
+			MarketplaceItem:      item,
+			SellerCompletedSales: score.CompletedSales,
+			SellerRating:         score.AverageRating,
+			SellerReviewCount:    score.ReviewCount,
+		})
+	}
+	sort.SliceStable(offers, func(i, j int) bool {
+		a, b := offers[i], offers[j]
+		if a.EffectivePrice != b.EffectivePrice {
+			return a.EffectivePrice < b.EffectivePrice
+		}
+		if a.SellerRating != b.SellerRating {
+			return a.SellerRating > b.SellerRating
+		}
+		return a.SellerCompletedSales > b.SellerCompletedSales
+	})
+	if len(offers) > 0 {
+		offers[0].IsBuyBox = true
+	}
+	return offers
+}
+
+// GetProductOffers - Semua penawaran seller aktif untuk satu produk master
+func (s *CatalogService) GetProductOffers(productID string) (ProductOffers, error) {
+	var product models.Product
+	if err := database.DB.Preload("ProductType").First(&product, "id = ?", productID).Error; err != nil {
+		return ProductOffers{}, err
+	}
+
+	result := ProductOffers{
+		ProductID:      product.ID.String(),
+		ProductName:    product.Name,
+		Category:       product.ProductType.Name,
+		StockAvailable: product.Stock,
+		Offers:         []MarketplaceOffer{},
+	}
+
+	// Produk stok habis tidak punya penawaran yang bisa dibeli
+	if product.Stock <= 0 {
+		return result, nil
+	}
+
+	var listings []models.SellerProduct
+	query := database.DB.Preload("Product.ProductType").Preload("Seller").
+		Where("product_id = ? AND is_active = ?", product.ID, true)
+	if err := excludeUnavailableSellers(query, time.Now()).
+		Order("created_at").Find(&listings).Error; err != nil {
+		return result, err
+	}
+	items, err := toMarketplaceItems(listings)
+	if err != nil {
+		return result, err
+	}
+	scores, err := getItemSellerScores(items)
+	if err != nil {
+		return result, err
+	}
+
+	offers := rankOffers(items, scores)
+	result.Offers = offers
+	result.OfferCount = len(offers)
+	if len(offers) > 0 {
+		result.BuyBox = &offers[0]
+		result.LowestPrice = offers[0].EffectivePrice
+		result.HighestPrice = offers[len(offers)-1].EffectivePrice
+	}
+	return result, nil
+}
+
+// GetMarketplaceGroups - Marketplace dikelompokkan per produk master, masing-masing
+// dengan buy box dan rentang harga. Filter sama dengan GetMarketplaceItems.
+func (s *CatalogService) GetMarketplaceGroups(search string, categoryID string, minPrice float64, maxPrice float64, includeOutOfStock bool) ([]MarketplaceProductGroup, error) {
+	items, err := s.GetMarketplaceItems(search, categoryID, minPrice, maxPrice, includeOutOfStock)
+	if err != nil {
+		return nil, err
+	}
+
+	byProduct := make(map[uuid.UUID][]MarketplaceItem)
+	order := []uuid.UUID{}
+	for _, item := range items {
+		if _, ok := byProduct[item.ProductID]; !ok {
+			order = append(order, item.ProductID)
+		}
+		byProduct[item.ProductID] = append(byProduct[item.ProductID], item)
+	}
+
+	scores, err := getItemSellerScores(items)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []MarketplaceProductGroup{}
+	for _, productID := range order {
+		offers := rankOffers(byProduct[productID], scores)
+		best := offers[0]
+		groups = append(groups, MarketplaceProductGroup{
+			ProductID:      productID.String(),
+			ProductName:    best.ProductName,
+			Category:       best.Category,
+			StockAvailable: best.StockTersedia,
+			OfferCount:     len(offers),
+			LowestPrice:    best.EffectivePrice,
+			HighestPrice:   offers[len(offers)-1].EffectivePrice,
+			BuyBox:         best,
+		})
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].ProductName < groups[j].ProductName })
+	return groups, nil
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationService menangani notifikasi in-app milik user yang login
+type NotificationService struct{}
+
+// NotificationDetail - Response notifikasi
+type NotificationDetail struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Title     string          `json:"title"`
+	Message   string          `json:"message"`
+	Data      json.RawMessage `json:"data"`
+	IsRead    bool            `json:"is_read"`
+	ReadAt    *time.Time      `json:"read_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// createNotification - Simpan notifikasi, dipanggil di dalam transaksi fitur yang memicunya
+func createNotification(tx *gorm.DB, userID uuid.UUID, notificationType string, title string, message string, data interface{}) error {
+	payload := []byte("{}")
+	if data != nil {
+		var err error
+		if payload, err = json.Marshal(data); err != nil {
+			return err
+		}
+	}
+	return tx.Create(&models.Notification{
+		UserID:  userID,
+		Type:    notificationType,
+		Title:   title,
+		Message: message,
+		Data:    string(payload),
+	}).Error
+}
+
+// GetMyNotifications - Notifikasi user (terbaru dulu) beserta jumlah yang belum dibaca
+func (s *NotificationService) GetMyNotifications(userID string, unreadOnly bool, limit int) ([]NotificationDetail, int64, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var unread int64
+	database.DB.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&unread)
+
+	query := database.DB.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	var notifications []models.Notification
+	if err := query.Order("created_at DESC").Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := []NotificationDetail{}
+	for _, n := range notifications {
+		result = append(result, NotificationDetail{
+			ID:        n.ID.String(),
+			Type:      n.Type,
+			Title:     n.Title,
+			Message:   n.Message,
+			Data:      json.RawMessage(n.Data),
+			IsRead:    n.ReadAt != nil,
+			ReadAt:    n.ReadAt,
+			CreatedAt: n.CreatedAt,
+		})
+	}
+	return result, unread, nil
+}
+
+// MarkAsRead - Tandai satu notifikasi milik user sebagai sudah dibaca
+func (s *NotificationService) MarkAsRead(userID string, notificationID string) error {
+	var notification models.Notification
+	if err := database.DB.First(&notification, "id = ? AND user_id = ?", notificationID, userID).Error; err != nil {
+		return errors.New("notification not found")
+	}
+	if notification.ReadAt != nil {
+		return nil
+	}
+	return database.DB.Model(&notification).Update("read_at", time.Now()).Error
+}
+
+// MarkAllAsRead - Tandai semua notifikasi user sebagai sudah dibaca
+func (s *NotificationService) MarkAllAsRead(userID string) (int64, error) {
+	result := database.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,446 @@
+package services
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"technical-test-backend/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrOIDCProviderNotFound - Provider tidak ada di OIDC_PROVIDERS / konfigurasinya belum lengkap (HTTP 404)
+var ErrOIDCProviderNotFound = errors.New("OIDC provider not configured")
+
+// ErrOIDCProviderError - Discovery, JWKS, atau token endpoint IdP gagal / response tidak valid (HTTP 502)
+var ErrOIDCProviderError = errors.New("identity provider error")
+
+// ErrOIDCInvalidIDToken - ID token gagal diverifikasi (signature, iss, aud, exp, nonce) (HTTP 401)
+var ErrOIDCInvalidIDToken = errors.New("invalid ID token")
+
+// oidcHTTPClient - Request ke IdP (discovery, JWKS, token, userinfo)
+var oidcHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// oidcResponseLimit - Batas ukuran response IdP yang dibaca
+const oidcResponseLimit = 1 << 20
+
+// oidcMetadataTTL - Discovery document & JWKS di-cache selama ini, JWKS diambil ulang lebih cepat jika kid tidak dikenal
+const oidcMetadataTTL = time.Hour
+
+// oidcKeyRefreshInterval - Jeda minimum ambil ulang JWKS karena kid tidak dikenal (mencegah banjir request ke IdP)
+const oidcKeyRefreshInterval = time.Minute
+
+// oidcSupportedAlgs - Algoritma tanda tangan ID token yang diterima (tanpa "none" / HMAC)
+var oidcSupportedAlgs = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// oidcRoleMapping - Anggota Group di IdP mendapat Role (nama role aplikasi)
+type oidcRoleMapping struct {
+	Group string
+	Role  string
+}
+
+// oidcProviderConfig - Konfigurasi satu IdP dari environment OIDC_<NAMA>_*
+type oidcProviderConfig struct {
+	Name         string
+	DisplayName  string
+	Issuer       string
+	ClientID     string
+	ClientSecret string // Kosong = public client (PKCE saja)
+	RedirectURL  string
+	Scopes       []string
+	GroupsClaim  string
+	RoleMappings []oidcRoleMapping // Urutan = prioritas jika user anggota beberapa grup
+	DefaultRole  string            // Role user baru tanpa grup yang cocok, kosong = ditolak
+	AllowSignup  bool              // Buat user baru jika belum ada akun dengan email yang sama
+}
+
+// oidcMetadata - Discovery document (/.well-known/openid-configuration), hanya field yang dipakai
+type oidcMetadata struct {
+	Issuer                   string   `json:"issuer"`
+	AuthorizationEndpoint    string   `json:"authorization_endpoint"`
+	TokenEndpoint            string   `json:"token_endpoint"`
+	UserinfoEndpoint         string   `json:"userinfo_endpoint"`
+	JWKSURI                  string   `json:"jwks_uri"`
+	IDTokenSigningAlgs       []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethods []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethods     []string `json:"code_challenge_methods_supported"`
+}
+
+// oidcProviderState - Cache discovery & public key satu provider
+type oidcProviderState struct {
+	metadata      oidcMetadata
+	fetchedAt     time.Time
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+var oidcCache = struct {
+	sync.Mutex
+	providers map[string]*oidcProviderState
+}{providers: map[string]*oidcProviderState{}}
+
+// oidcEnvKey - OIDC_<NAMA>_<FIELD>, nama provider huruf besar dengan "-" menjadi "_"
+func oidcEnvKey(name string, field string) string {
+	return "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_" + field
+}
+
+// oidcProviderNames - Nama provider dari OIDC_PROVIDERS (dipisah koma, huruf kecil)
+func oidcProviderNames() []string {
+	names := []string{}
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// oidcProvider - Konfigurasi provider, ErrOIDCProviderNotFound jika tidak terdaftar / issuer, client ID, atau redirect URL kosong
+func oidcProvider(name string) (oidcProviderConfig, error) {
+	name = strings.ToLower(name)
+	if !slices.Contains(oidcProviderNames(), name) {
+		return oidcProviderConfig{}, ErrOIDCProviderNotFound
+	}
+
+	cfg := oidcProviderConfig{
+		Name:         name,
+		DisplayName:  utils.EnvString(oidcEnvKey(name, "DISPLAY_NAME"), name),
+		Issuer:       os.Getenv(oidcEnvKey(name, "ISSUER")),
+		ClientID:     os.Getenv(oidcEnvKey(name, "CLIENT_ID")),
+		ClientSecret: os.Getenv(oidcEnvKey(name, "CLIENT_SECRET")),
+		RedirectURL:  os.Getenv(oidcEnvKey(name, "REDIRECT_URL")),
+		Scopes:       strings.Fields(utils.EnvString(oidcEnvKey(name, "SCOPES"), "openid email profile")),
+		GroupsClaim:  utils.EnvString(oidcEnvKey(name, "GROUPS_CLAIM"), "groups"),
+		DefaultRole:  os.Getenv(oidcEnvKey(name, "DEFAULT_ROLE")),
+		AllowSignup:  utils.EnvBool(oidcEnvKey(name, "ALLOW_SIGNUP"), true),
+	}
+	if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return oidcProviderConfig{}, fmt.Errorf("%w: %s (ISSUER, CLIENT_ID, REDIRECT_URL wajib)", ErrOIDCProviderNotFound, name)
+	}
+
+	// Scope openid wajib agar IdP mengembalikan ID token
+	if !slices.Contains(cfg.Scopes, "openid") {
+		cfg.Scopes = append([]string{"openid"}, cfg.Scopes...)
+	}
+
+	// ROLE_MAPPING: "grup=Role,grup2=Role2"
+	for _, pair := range strings.Split(os.Getenv(oidcEnvKey(name, "ROLE_MAPPING")), ",") {
+		group, role, ok := strings.Cut(pair, "=")
+		group, role = strings.TrimSpace(group), strings.TrimSpace(role)
+		if ok && group != "" && role != "" {
+			cfg.RoleMappings = append(cfg.RoleMappings, oidcRoleMapping{Group: group, Role: role})
+		}
+	}
+	return cfg, nil
+}
+
+// mappedRole - Role dari mapping pertama yang grupnya dimiliki user, kosong jika tidak ada
+func (cfg oidcProviderConfig) mappedRole(groups []string) string {
+	for _, mapping := range cfg.RoleMappings {
+		for _, group := range groups {
+			if group == mapping.Group {
+				return mapping.Role
+			}
+		}
+	}
+	return ""
+}
+
+// managesRole - Role ini diatur lewat grup IdP (ada di ROLE_MAPPING)
+func (cfg oidcProviderConfig) managesRole(role string) bool {
+	for _, mapping := range cfg.RoleMappings {
+		if mapping.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// oidcReadJSON - Decode response JSON IdP, status selain 2xx = ErrOIDCProviderError
+func oidcReadJSON(resp *http.Response, dst interface{}) error {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, oidcResponseLimit))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOIDCProviderError, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var oauthErr struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Error != "" {
+			return fmt.Errorf("%w: %s %s", ErrOIDCProviderError, oauthErr.Error, oauthErr.ErrorDescription)
+		}
+		return fmt.Errorf("%w: %s returned HTTP %d", ErrOIDCProviderError, resp.Request.URL.Host, resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		return fmt.Errorf("%w: invalid JSON from %s", ErrOIDCProviderError, resp.Request.URL.Host)
+	}
+	return nil
+}
+
+// oidcGetJSON - GET JSON dari IdP, bearer diisi untuk userinfo
+func oidcGetJSON(endpoint string, bearer string, dst interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOIDCProviderError, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	resp, err := oidcHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOIDCProviderError, err)
+	}
+	return oidcReadJSON(resp, dst)
+}
+
+// oidcState - Cache provider (dibuat jika belum ada), dipanggil dengan oidcCache terkunci
+func oidcState(name string) *oidcProviderState {
+	state, ok := oidcCache.providers[name]
+	if !ok {
+		state = &oidcProviderState{}
+		oidcCache.providers[name] = state
+	}
+	return state
+}
+
+// oidcDiscover - Discovery document provider (cache oidcMetadataTTL).
+// Issuer di dokumen harus sama dengan OIDC_<NAMA>_ISSUER, PKCE S256 harus didukung jika IdP mencantumkan daftarnya.
+func oidcDiscover(cfg oidcProviderConfig) (oidcMetadata, error) {
+	oidcCache.Lock()
+	defer oidcCache.Unlock()
+	state := oidcState(cfg.Name)
+	if state.metadata.Issuer != "" && time.Since(state.fetchedAt) < oidcMetadataTTL {
+		return state.metadata, nil
+	}
+
+	var metadata oidcMetadata
+	if err := oidcGetJSON(strings.TrimSuffix(cfg.Issuer, "/")+"/.well-known/openid-configuration", "", &metadata); err != nil {
+		return metadata, err
+	}
+	if strings.TrimSuffix(metadata.Issuer, "/") != strings.TrimSuffix(cfg.Issuer, "/") {
+		return metadata, fmt.Errorf("%w: discovery issuer %q does not match %q", ErrOIDCProviderError, metadata.Issuer, cfg.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return metadata, fmt.Errorf("%w: discovery document incomplete", ErrOIDCProviderError)
+	}
+	if len(metadata.CodeChallengeMethods) > 0 && !slices.Contains(metadata.CodeChallengeMethods, "S256") {
+		return metadata, fmt.Errorf("%w: provider does not support PKCE S256", ErrOIDCProviderError)
+	}
+
+	state.metadata = metadata
+	state.fetchedAt = time.Now()
+	return metadata, nil
+}
+
+// oidcSigningKey - Public key IdP untuk kid dari JWKS (cache). kid tidak dikenal = JWKS diambil ulang
+// (maks. sekali per oidcKeyRefreshInterval) untuk menangani rotasi kunci di IdP.
+// kid kosong hanya diterima jika JWKS berisi satu kunci.
+func oidcSigningKey(cfg oidcProviderConfig, metadata oidcMetadata, kid string) (crypto.PublicKey, error) {
+	oidcCache.Lock()
+	defer oidcCache.Unlock()
+	state := oidcState(cfg.Name)
+
+	lookup := func() (crypto.PublicKey, bool) {
+		if kid == "" && len(state.keys) == 1 {
+			for _, key := range state.keys {
+				return key, true
+			}
+		}
+		key, ok := state.keys[kid]
+		return key, ok && kid != ""
+	}
+
+	stale := time.Since(state.keysFetchedAt) >= oidcMetadataTTL
+	if key, ok := lookup(); ok && !stale {
+		return key, nil
+	}
+	if !stale && time.Since(state.keysFetchedAt) < oidcKeyRefreshInterval {
+		return nil, fmt.Errorf("%w: unknown key id %q", ErrOIDCInvalidIDToken, kid)
+	}
+
+	var set utils.JWKSet
+	if err := oidcGetJSON(metadata.JWKSURI, "", &set); err != nil {
+		return nil, err
+	}
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// Kunci dengan tipe yang belum didukung dilewati, bukan menggagalkan seluruh JWKS
+		if key, err := utils.ParseJWK(jwk); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	state.keys = keys
+	state.keysFetchedAt = time.Now()
+
+	if key, ok := lookup(); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown key id %q", ErrOIDCInvalidIDToken, kid)
+}
+
+// oidcSigningAlgs - Algoritma ID token yang diterima: dukungan IdP (default RS256 sesuai spesifikasi) ∩ oidcSupportedAlgs
+func oidcSigningAlgs(metadata oidcMetadata) []string {
+	advertised := metadata.IDTokenSigningAlgs
+	if len(advertised) == 0 {
+		advertised = []string{"RS256"}
+	}
+	algs := []string{}
+	for _, alg := range advertised {
+		if slices.Contains(oidcSupportedAlgs, alg) {
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
+
+// oidcTokenResponse - Response token endpoint (authorization_code)
+type oidcTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+}
+
+// oidcExchangeCode - Tukar authorization code + PKCE code verifier dengan token.
+// Client secret dikirim dengan client_secret_basic (default spesifikasi) atau client_secret_post jika hanya itu yang didukung IdP.
+func oidcExchangeCode(cfg oidcProviderConfig, metadata oidcMetadata, code string, verifier string) (oidcTokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {cfg.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	useBasic := cfg.ClientSecret != "" &&
+		(len(metadata.TokenEndpointAuthMethods) == 0 || slices.Contains(metadata.TokenEndpointAuthMethods, "client_secret_basic"))
+	if !useBasic {
+		form.Set("client_id", cfg.ClientID)
+		if cfg.ClientSecret != "" {
+			form.Set("client_secret", cfg.ClientSecret)
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oidcTokenResponse{}, fmt.Errorf("%w: %v", ErrOIDCProviderError, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if useBasic {
+		// RFC 6749 2.3.1: client ID & secret di-encode form sebelum Basic auth
+		req.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(cfg.ClientSecret))
+	}
+
+	resp, err := oidcHTTPClient.Do(req)
+	if err != nil {
+		return oidcTokenResponse{}, fmt.Errorf("%w: %v", ErrOIDCProviderError, err)
+	}
+	var tokens oidcTokenResponse
+	if err := oidcReadJSON(resp, &tokens); err != nil {
+		return tokens, err
+	}
+	if tokens.IDToken == "" {
+		return tokens, fmt.Errorf("%w: token response without id_token", ErrOIDCProviderError)
+	}
+	return tokens, nil
+}
+
+// oidcVerifyIDToken - Verifikasi ID token: signature (JWKS), iss, aud, azp, exp, iat, nonce
+func oidcVerifyIDToken(cfg oidcProviderConfig, metadata oidcMetadata, idToken string, nonce string) (jwt.MapClaims, error) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(oidcSigningAlgs(metadata)),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	claims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return oidcSigningKey(cfg, metadata, kid)
+	})
+	if err != nil {
+		if errors.Is(err, ErrOIDCProviderError) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrOIDCInvalidIDToken, err)
+	}
+
+	if claimString(claims, "nonce") != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrOIDCInvalidIDToken)
+	}
+	if claimString(claims, "sub") == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrOIDCInvalidIDToken)
+	}
+	// Token untuk beberapa audience harus diterbitkan untuk client ini (azp)
+	audience, _ := claims.GetAudience()
+	if azp := claimString(claims, "azp"); (len(audience) > 1 || azp != "") && azp != cfg.ClientID {
+		return nil, fmt.Errorf("%w: azp mismatch", ErrOIDCInvalidIDToken)
+	}
+	return claims, nil
+}
+
+// oidcMergeUserinfo - Lengkapi claims dari userinfo endpoint (email / grup tidak selalu ada di ID token).
+// sub userinfo harus sama dengan ID token, claim yang sudah ada di ID token tidak ditimpa.
+func oidcMergeUserinfo(metadata oidcMetadata, accessToken string, claims jwt.MapClaims) error {
+	var userinfo map[string]interface{}
+	if err := oidcGetJSON(metadata.UserinfoEndpoint, accessToken, &userinfo); err != nil {
+		return err
+	}
+	if sub, _ := userinfo["sub"].(string); sub != claimString(claims, "sub") {
+		return fmt.Errorf("%w: userinfo sub mismatch", ErrOIDCInvalidIDToken)
+	}
+	for key, value := range userinfo {
+		if _, exists := claims[key]; !exists {
+			claims[key] = value
+		}
+	}
+	return nil
+}
+
+// claimString - Claim string, kosong jika tidak ada / bukan string
+func claimString(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// claimBool - Claim boolean (sebagian IdP mengirim "true" sebagai string)
+func claimBool(claims jwt.MapClaims, name string) bool {
+	switch value := claims[name].(type) {
+	case bool:
+		return value
+	case string:
+		return strings.EqualFold(value, "true")
+	}
+	return false
+}
+
+// claimStrings - Claim daftar string (array, atau string tunggal dipisah spasi / koma), present false jika claim tidak ada
+func claimStrings(claims jwt.MapClaims, name string) (values []string, present bool) {
+	raw, present := claims[name]
+	switch value := raw.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+	case string:
+		values = strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+	return values, present
+}
@@ -0,0 +1,440 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OIDCService - Login lewat identity provider eksternal (OpenID Connect authorization code + PKCE)
+type OIDCService struct{}
+
+// ErrOIDCInvalidState - State login tidak dikenal, sudah dipakai, atau kadaluarsa (HTTP 400)
+var ErrOIDCInvalidState = errors.New("invalid or expired OIDC login state")
+
+// ErrOIDCAuthorizationFailed - IdP mengembalikan error ke callback (misal user menolak akses) (HTTP 401)
+var ErrOIDCAuthorizationFailed = errors.New("authorization at identity provider failed")
+
+// ErrOIDCEmailNotVerified - Email dari IdP kosong / belum terverifikasi, akun tidak bisa dihubungkan / dibuat (HTTP 403)
+var ErrOIDCEmailNotVerified = errors.New("identity provider did not return a verified email")
+
+// ErrOIDCNoRole - Grup IdP user tidak ada di ROLE_MAPPING dan DEFAULT_ROLE kosong (HTTP 403)
+var ErrOIDCNoRole = errors.New("no role mapped for your identity provider groups")
+
+// ErrOIDCSignupDisabled - Belum ada akun dengan email ini dan ALLOW_SIGNUP=false (HTTP 403)
+var ErrOIDCSignupDisabled = errors.New("no account found for this identity")
+
+// ErrOIDCIdentityConflict - Akun sudah terhubung ke identitas lain di provider yang sama,
+// atau akun lokal dengan email yang sama belum diverifikasi sehingga tidak bisa dihubungkan (HTTP 409)
+var ErrOIDCIdentityConflict = errors.New("account is already linked to another identity at this provider")
+
+// oidcStateTTL - Batas waktu user menyelesaikan login di IdP (OIDC_STATE_TTL, default 10m)
+func oidcStateTTL() time.Duration {
+	return utils.EnvDuration("OIDC_STATE_TTL", 10*time.Minute)
+}
+
+// OIDCProviderInfo - Provider yang bisa dipakai login (untuk tombol "Login dengan ..." di frontend)
+type OIDCProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	LoginURL    string `json:"login_url"`
+}
+
+// OIDCCallbackInput - Query callback dari IdP
+type OIDCCallbackInput struct {
+	Code             string `form:"code"`
+	State            string `form:"state" binding:"required"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+// oidcIdentity - Data user dari ID token (+ userinfo)
+type oidcIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string
+	GroupsPresent bool // Claim grup dikirim IdP (kosong = user tidak punya grup)
+}
+
+// Providers - Provider dari OIDC_PROVIDERS yang konfigurasinya lengkap
+func (s *OIDCService) Providers() []OIDCProviderInfo {
+	providers := []OIDCProviderInfo{}
+	for _, name := range oidcProviderNames() {
+		cfg, err := oidcProvider(name)
+		if err != nil {
+			continue
+		}
+		providers = append(providers, OIDCProviderInfo{
+			Name:        cfg.Name,
+			DisplayName: cfg.DisplayName,
+			LoginURL:    "/auth/oidc/" + cfg.Name + "/login",
+		})
+	}
+	return providers
+}
+
+// AuthorizationURL - URL authorize IdP untuk memulai login. State, nonce, dan PKCE code verifier
+// disimpan (state sebagai hash) dan hanya bisa dipakai sekali dalam OIDC_STATE_TTL.
+func (s *OIDCService) AuthorizationURL(providerName string) (string, error) {
+	cfg, err := oidcProvider(providerName)
+	if err != nil {
+		return "", err
+	}
+	metadata, err := oidcDiscover(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	var values [3]string
+	for i := range values {
+		if values[i], err = generateOpaqueToken(); err != nil {
+			return "", err
+		}
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+	err = database.DB.Create(&models.OIDCLoginState{
+		Provider:     cfg.Name,
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oidcStateTTL()),
+	}).Error
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {cfg.ClientID},
+		"redirect_uri":          {cfg.RedirectURL},
+		"scope":                 {strings.Join(cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return metadata.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// consumeOIDCState - Ambil & hapus state login (sekali pakai, juga saat IdP mengembalikan error)
+func consumeOIDCState(providerName string, state string) (models.OIDCLoginState, error) {
+	var record models.OIDCLoginState
+	err := database.DB.Where("state_hash = ? AND provider = ?", hashToken(state), strings.ToLower(providerName)).
+		First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return record, ErrOIDCInvalidState
+	}
+	if err != nil {
+		return record, err
+	}
+
+	// Callback paralel dengan state yang sama: hanya satu yang berhasil menghapus
+	result := database.DB.Unscoped().Where("id = ?", record.ID).Delete(&models.OIDCLoginState{})
+	if result.Error != nil {
+		return record, result.Error
+	}
+	if result.RowsAffected == 0 || time.Now().After(record.ExpiresAt) {
+		return record, ErrOIDCInvalidState
+	}
+	return record, nil
+}
+
+// Callback - Selesaikan login OIDC: validasi state, tukar code (PKCE), verifikasi ID token,
+// cari / hubungkan / buat user, sinkron role dari grup IdP, lalu terbitkan token seperti login password.
+// User dengan 2FA lokal aktif (atau role wajib 2FA) tetap mendapat challenge 2FA.
+func (s *OIDCService) Callback(providerName string, input OIDCCallbackInput, meta ClientMeta, audit *AuditContext) (LoginResponse, error) {
+	cfg, err := oidcProvider(providerName)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	loginState, err := consumeOIDCState(cfg.Name, input.State)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	if input.Error != "" {
+		return LoginResponse{}, fmt.Errorf("%w: %s %s", ErrOIDCAuthorizationFailed, input.Error, input.ErrorDescription)
+	}
+	if input.Code == "" {
+		return LoginResponse{}, fmt.Errorf("%w: missing code", ErrOIDCAuthorizationFailed)
+	}
+
+	metadata, err := oidcDiscover(cfg)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	tokens, err := oidcExchangeCode(cfg, metadata, input.Code, loginState.CodeVerifier)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	claims, err := oidcVerifyIDToken(cfg, metadata, tokens.IDToken, loginState.Nonce)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	// Email / grup tidak ada di ID token: lengkapi dari userinfo
+	if _, hasGroups := claims[cfg.GroupsClaim]; (claimString(claims, "email") == "" || !hasGroups) &&
+		metadata.UserinfoEndpoint != "" && tokens.AccessToken != "" {
+		if err := oidcMergeUserinfo(metadata, tokens.AccessToken, claims); err != nil {
+			return LoginResponse{}, err
+		}
+	}
+	identity := oidcIdentityFromClaims(cfg, claims)
+
+	var user models.User
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		user, err = resolveOIDCUser(tx, cfg, identity, audit)
+		return err
+	})
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	if err := database.DB.Preload("Role").First(&user, "id = ?", user.ID).Error; err != nil {
+		return LoginResponse{}, err
+	}
+
+	// Status akun & 2FA sama seperti login password
+	if err := accountStatusError(user, time.Now()); err != nil {
+		return LoginResponse{}, err
+	}
+	enabled, err := twoFactorEnabled(database.DB, user.ID)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	if enabled || twoFactorRequired(user.Role.Name) {
+		challenge, err := issueLoginChallenge(user, !enabled)
+		if err != nil {
+			return LoginResponse{}, err
+		}
+		return LoginResponse{Challenge: challenge, User: user}, nil
+	}
+
+	pair, _, err := issueTokenPair(database.DB, user, uuid.New(), meta)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	return LoginResponse{Tokens: pair, User: user}, nil
+}
+
+// oidcIdentityFromClaims - Data user dari claims terverifikasi
+func oidcIdentityFromClaims(cfg oidcProviderConfig, claims jwt.MapClaims) oidcIdentity {
+	identity := oidcIdentity{
+		Subject:       claimString(claims, "sub"),
+		Email:         strings.ToLower(strings.TrimSpace(claimString(claims, "email"))),
+		EmailVerified: claimBool(claims, "email_verified"),
+		Name:          strings.TrimSpace(claimString(claims, "name")),
+	}
+	identity.Groups, identity.GroupsPresent = claimStrings(claims, cfg.GroupsClaim)
+	if identity.Name == "" {
+		identity.Name = claimString(claims, "preferred_username")
+	}
+	if identity.Name == "" {
+		identity.Name, _, _ = strings.Cut(identity.Email, "@")
+	}
+	return identity
+}
+
+// resolveOIDCUser - User untuk identitas IdP:
+//  1. Identitas sudah terhubung (provider + sub) -> user tersebut
+//  2. Email terverifikasi sama dengan user lama yang emailnya juga sudah diverifikasi -> identitas dihubungkan
+//     (email user lama belum diverifikasi -> ErrOIDCIdentityConflict)
+//  3. Belum ada -> user baru (ALLOW_SIGNUP) dengan role dari grup / DEFAULT_ROLE
+//
+// Role user lama diselaraskan dengan grup IdP (lihat syncOIDCRole).
+func resolveOIDCUser(tx *gorm.DB, cfg oidcProviderConfig, identity oidcIdentity, audit *AuditContext) (models.User, error) {
+	now := time.Now()
+	var user models.User
+
+	var link models.UserIdentity
+	if err := tx.Where("provider = ? AND subject = ?", cfg.Name, identity.Subject).Limit(1).Find(&link).Error; err != nil {
+		return user, err
+	}
+
+	switch {
+	case link.ID != uuid.Nil:
+		if err := tx.First(&user, "id = ?", link.UserID).Error; err != nil {
+			return user, err
+		}
+		audit.SetActor(user.ID.String())
+
+	default:
+		if identity.Email == "" || !identity.EmailVerified {
+			return user, ErrOIDCEmailNotVerified
+		}
+		if err := tx.Where("LOWER(email) = ?", identity.Email).Limit(1).Find(&user).Error; err != nil {
+			return user, err
+		}
+
+		if user.ID != uuid.Nil {
+			// Hanya akun yang emailnya sudah diverifikasi lokal: akun yang didaftarkan orang lain dengan
+			// email staf (belum diverifikasi) tidak boleh mewarisi identitas & role dari IdP
+			if user.EmailVerifiedAt == nil {
+				return user, fmt.Errorf("%w: email akun lokal belum diverifikasi, verifikasi email atau hubungi admin", ErrOIDCIdentityConflict)
+			}
+			// Satu identitas per provider per user
+			var linked int64
+			if err := tx.Model(&models.UserIdentity{}).Where("user_id = ? AND provider = ?", user.ID, cfg.Name).
+				Count(&linked).Error; err != nil {
+				return user, err
+			}
+			if linked > 0 {
+				return user, ErrOIDCIdentityConflict
+			}
+			audit.SetActor(user.ID.String())
+			if err := audit.Record(tx, models.AuditOIDCIdentityLinked, "user", &user.ID, map[string]interface{}{
+				"provider": cfg.Name, "subject": identity.Subject, "email": identity.Email,
+			}); err != nil {
+				return user, err
+			}
+		} else {
+			created, err := createOIDCUser(tx, cfg, identity, audit)
+			if err != nil {
+				return user, err
+			}
+			user = created
+		}
+
+		link = models.UserIdentity{UserID: user.ID, Provider: cfg.Name, Subject: identity.Subject}
+	}
+
+	// Email sudah diverifikasi IdP
+	if user.EmailVerifiedAt == nil && identity.EmailVerified && strings.EqualFold(user.Email, identity.Email) {
+		if err := tx.Model(&user).Update("email_verified_at", now).Error; err != nil {
+			return user, err
+		}
+	}
+
+	link.Email = identity.Email
+	link.LastLoginAt = &now
+	if err := tx.Save(&link).Error; err != nil {
+		return user, err
+	}
+	return user, syncOIDCRole(tx, cfg, &user, identity, audit)
+}
+
+// createOIDCUser - User baru dari login OIDC pertama. Password kosong (hash kosong tidak pernah cocok),
+// login hanya lewat IdP sampai user memakai lupa password.
+func createOIDCUser(tx *gorm.DB, cfg oidcProviderConfig, identity oidcIdentity, audit *AuditContext) (models.User, error) {
+	if !cfg.AllowSignup {
+		return models.User{}, ErrOIDCSignupDisabled
+	}
+	roleName := cfg.mappedRole(identity.Groups)
+	if roleName == "" {
+		roleName = cfg.DefaultRole
+	}
+	if roleName == "" {
+		return models.User{}, ErrOIDCNoRole
+	}
+	role, err := oidcRole(tx, roleName)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	now := time.Now()
+	user := models.User{
+		Name:            truncate(identity.Name, 100),
+		Email:           identity.Email,
+		Password:        "",
+		RoleID:          role.ID,
+		EmailVerifiedAt: &now,
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		return user, err
+	}
+	if err := ensureSellerProfileForRole(tx, user); err != nil {
+		return user, err
+	}
+
+	audit.SetActor(user.ID.String())
+	return user, audit.Record(tx, models.AuditOIDCUserCreated, "user", &user.ID, map[string]interface{}{
+		"provider": cfg.Name, "subject": identity.Subject, "email": identity.Email, "role": role.Name, "groups": identity.Groups,
+	})
+}
+
+// syncOIDCRole - Selaraskan role user lama dengan grup IdP (hanya jika IdP mengirim claim grup):
+// grup cocok dengan ROLE_MAPPING -> role tersebut; tidak cocok tapi role user saat ini diatur lewat
+// mapping (misal dikeluarkan dari grup admin) -> DEFAULT_ROLE, atau login ditolak jika DEFAULT_ROLE kosong.
+// Role yang tidak ada di mapping (diatur manual oleh admin) tidak diubah.
+func syncOIDCRole(tx *gorm.DB, cfg oidcProviderConfig, user *models.User, identity oidcIdentity, audit *AuditContext) error {
+	if !identity.GroupsPresent || len(cfg.RoleMappings) == 0 {
+		return nil
+	}
+	var current models.Role
+	if err := tx.First(&current, "id = ?", user.RoleID).Error; err != nil {
+		return err
+	}
+
+	target := cfg.mappedRole(identity.Groups)
+	if target == "" {
+		if !cfg.managesRole(current.Name) {
+			return nil
+		}
+		if cfg.DefaultRole == "" {
+			return ErrOIDCNoRole
+		}
+		target = cfg.DefaultRole
+	}
+	if target == current.Name {
+		return nil
+	}
+
+	role, err := oidcRole(tx, target)
+	if err != nil {
+		return err
+	}
+	if err := tx.Model(user).Update("role_id", role.ID).Error; err != nil {
+		return err
+	}
+	user.RoleID = role.ID
+	// Tidak boleh menghapus user terakhir yang bisa mengelola role
+	if err := ensureRoleManagerExists(tx); err != nil {
+		return err
+	}
+	if err := ensureSellerProfileForRole(tx, *user); err != nil {
+		return err
+	}
+	return audit.Record(tx, models.AuditOIDCRoleSynced, "user", &user.ID, map[string]interface{}{
+		"provider": cfg.Name,
+		"groups":   identity.Groups,
+		"before":   map[string]interface{}{"role": current.Name},
+		"after":    map[string]interface{}{"role": role.Name},
+	})
+}
+
+// oidcRole - Role berdasarkan nama dari ROLE_MAPPING / DEFAULT_ROLE (salah ketik di konfigurasi = error jelas)
+func oidcRole(tx *gorm.DB, name string) (models.Role, error) {
+	var role models.Role
+	if err := tx.Where("name = ?", name).Limit(1).Find(&role).Error; err != nil {
+		return role, err
+	}
+	if role.ID == uuid.Nil {
+		return role, fmt.Errorf("OIDC role mapping refers to unknown role %q", name)
+	}
+	return role, nil
+}
+
+// ensureSellerProfileForRole - Role dengan permission seller.listings langsung mendapat profil toko default
+func ensureSellerProfileForRole(tx *gorm.DB, user models.User) error {
+	isSeller, err := roleHasPermission(tx, user.RoleID, models.PermSellerListings)
+	if err != nil || !isSeller {
+		return err
+	}
+	_, err = ensureSellerProfile(tx, user)
+	return err
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// rolePermissionNames - Nama permission milik role (diurutkan), dipakai sebagai claim "perms" di access token
+func rolePermissionNames(tx *gorm.DB, roleID uuid.UUID) ([]string, error) {
+	names := []string{}
+	err := tx.Model(&models.Permission{}).
+		Joins("JOIN role_permissions rp ON rp.permission_id = permissions.id").
+		Where("rp.role_id = ?", roleID).
+		Order("permissions.name").
+		Pluck("permissions.name", &names).Error
+	return names, err
+}
+
+// permissionNamesByRoleName - Permission role berdasarkan nama role, untuk access token lama tanpa claim "perms"
+func permissionNamesByRoleName(roleName string) ([]string, error) {
+	var role models.Role
+	if err := database.DB.Where("name = ?", roleName).Limit(1).Find(&role).Error; err != nil {
+		return nil, err
+	}
+	if role.ID == uuid.Nil {
+		return []string{}, nil
+	}
+	return rolePermissionNames(database.DB, role.ID)
+}
+
+// roleHasPermission - Role memiliki permission tertentu
+func roleHasPermission(tx *gorm.DB, roleID uuid.UUID, permission string) (bool, error) {
+	var count int64
+	err := tx.Model(&models.Permission{}).
+		Joins("JOIN role_permissions rp ON rp.permission_id = permissions.id").
+		Where("rp.role_id = ? AND permissions.name = ?", roleID, permission).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasPermission - Daftar permission (dari claim token) memuat permission yang diminta
+func HasPermission(granted []string, permission string) bool {
+	for _, name := range granted {
+		if name == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Kebijakan saat harga modal naik melebihi harga jual etalase seller
+const (
+	PricePolicyDeactivate = "DEACTIVATE" // Nonaktifkan etalase yang rugi
+	PricePolicyReprice    = "REPRICE"    // Naikkan harga jual, persentase markup seller dipertahankan
+	PricePolicyNotify     = "NOTIFY"     // Biarkan, hanya kirim notifikasi ke seller
+)
+
+// Aksi yang dilakukan pada satu etalase
+const (
+	ListingDeactivated = "DEACTIVATED"
+	ListingRepriced    = "REPRICED"
+	ListingUnchanged   = "UNCHANGED"
+)
+
+// DefaultPricePolicy - Dari env MASTER_PRICE_POLICY, default DEACTIVATE agar tidak ada penjualan rugi
+func DefaultPricePolicy() string {
+	policy := strings.ToUpper(utils.EnvString("MASTER_PRICE_POLICY", PricePolicyDeactivate))
+	if validatePricePolicy(policy) != nil {
+		return PricePolicyDeactivate
+	}
+	return policy
+}
+
+// resolvePricePolicy - Policy per request, kosong = default
+func resolvePricePolicy(policy string) (string, error) {
+	if policy == "" {
+		return DefaultPricePolicy(), nil
+	}
+	policy = strings.ToUpper(policy)
+	return policy, validatePricePolicy(policy)
+}
+
+func validatePricePolicy(policy string) error {
+	switch policy {
+	case PricePolicyDeactivate, PricePolicyReprice, PricePolicyNotify:
+		return nil
+	}
+	return errors.New("price_policy must be DEACTIVATE, REPRICE or NOTIFY")
+}
+
+// AffectedListing - Etalase seller yang harga jualnya di bawah harga modal baru
+type AffectedListing struct {
+	SellerProductID string  `json:"seller_product_id"`
+	SellerID        string  `json:"seller_id"`
+	SellerName      string  `json:"seller_name"`
+	OldSellingPrice float64 `json:"old_selling_price"`
+	NewSellingPrice float64 `json:"new_selling_price"`
+	WasActive       bool    `json:"was_active"`
+	Action          string  `json:"action"`
+}
+
+// PriceChangeResult - Ringkasan penanganan perubahan harga modal
+type PriceChangeResult struct {
+	ProductID   string            `json:"product_id"`
+	ProductName string            `json:"product_name"`
+	OldPrice    float64           `json:"old_price"`
+	NewPrice    float64           `json:"new_price"`
+	Policy      string            `json:"policy"`
+	Affected    []AffectedListing `json:"affected_listings"`
+}
+
+// handleMasterPriceChange - Dipanggil setiap kali Product.Price berubah (update admin, import,
+// penerimaan PO). Wajib di dalam DB transaction yang sama dengan perubahan harga.
+// Alur: Cari etalase dengan selling_price < harga baru -> Terapkan policy -> Notifikasi per seller
+func handleMasterPriceChange(tx *gorm.DB, product models.Product, oldPrice float64, newPrice float64, policy string) (*PriceChangeResult, error) {
+	if oldPrice == newPrice {
+		return nil, nil
+	}
+
+	var listings []models.SellerProduct
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Seller").
+		Where("product_id = ? AND selling_price < ?", product.ID, newPrice).
+		Order("seller_id").
+		Find(&listings).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(listings) == 0 {
+		return nil, nil
+	}
+
+	result := &PriceChangeResult{
+		ProductID:   product.ID.String(),
+		ProductName: product.Name,
+		OldPrice:    oldPrice,
+		NewPrice:    newPrice,
+		Policy:      policy,
+		Affected:    []AffectedListing{},
+	}
+
+	for _, listing := range listings {
+		affected := AffectedListing{
+			SellerProductID: listing.ID.String(),
+			SellerID:        listing.SellerID.String(),
+			SellerName:      listing.Seller.Name,
+			OldSellingPrice: listing.SellingPrice,
+			NewSellingPrice: listing.SellingPrice,
+			WasActive:       listing.IsActive,
+			Action:          ListingUnchanged,
+		}
+
+		switch policy {
+		case PricePolicyDeactivate:
+			if listing.IsActive {
+				if err := tx.Model(&listing).Update("is_active", false).Error; err != nil {
+					return nil, err
+				}
+				affected.Action = ListingDeactivated
+			}
+		case PricePolicyReprice:
+			// Markup relatif terhadap harga modal lama dipertahankan, minimal sama dengan harga modal baru
+			ratio := 1.0
+			if oldPrice > 0 && listing.SellingPrice > oldPrice {
+				ratio = listing.SellingPrice / oldPrice
+			}
+			newSellingPrice := math.Ceil(newPrice*ratio*100) / 100
+			if err := changeSellingPrice(tx, &listing, newSellingPrice, models.PriceSourceMasterReprice, nil, nil); err != nil {
+				return nil, err
+			}
+			affected.NewSellingPrice = newSellingPrice
+			affected.Action = ListingRepriced
+		}
+		result.Affected = append(result.Affected, affected)
+	}
+
+	if err := notifySellersOfPriceChange(tx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// notifySellersOfPriceChange - Satu notifikasi per seller berisi semua etalasenya yang terdampak
+func notifySellersOfPriceChange(tx *gorm.DB, result *PriceChangeResult) error {
+	bySeller := make(map[string][]AffectedListing)
+	for _, affected := range result.Affected {
+		bySeller[affected.SellerID] = append(bySeller[affected.SellerID], affected)
+	}
+	sellerIDs := make([]string, 0, len(bySeller))
+	for id := range bySeller {
+		sellerIDs = append(sellerIDs, id)
+	}
+	sort.Strings(sellerIDs)
+
+	for _, sellerID := range sellerIDs {
+		listings := bySeller[sellerID]
+		lines := []string{fmt.Sprintf("Harga modal %s berubah dari %.2f menjadi %.2f.", result.ProductName, result.OldPrice, result.NewPrice)}
+		for _, l := range listings {
+			switch l.Action {
+			case ListingDeactivated:
+				lines = append(lines, fmt.Sprintf("- Etalase %s (harga jual %.2f) dinonaktifkan. Naikkan harga jual lalu aktifkan kembali.", l.SellerProductID, l.OldSellingPrice))
+			case ListingRepriced:
+				lines = append(lines, fmt.Sprintf("- Harga jual etalase %s disesuaikan dari %.2f menjadi %.2f.", l.SellerProductID, l.OldSellingPrice, l.NewSellingPrice))
+			default:
+				lines = append(lines, fmt.Sprintf("- Harga jual etalase %s (%.2f) kini di bawah harga modal, segera sesuaikan.", l.SellerProductID, l.OldSellingPrice))
+			}
+		}
+
+		sellerUUID, _ := uuid.Parse(sellerID)
+		err := createNotification(tx, sellerUUID, models.NotificationPriceChange,
+			"Harga modal "+result.ProductName+" berubah",
+			strings.Join(lines, "\n"),
+			map[string]interface{}{
+				"product_id":        result.ProductID,
+				"product_name":      result.ProductName,
+				"old_price":         result.OldPrice,
+				"new_price":         result.NewPrice,
+				"policy":            result.Policy,
+				"affected_listings": listings,
+			})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
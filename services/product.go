@@ -1,27 +1,79 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"technical-test-backend/database"
 	"technical-test-backend/models"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductService struct{}
 
+// ErrProductInUse - Produk master masih dipajang seller atau punya order pending (HTTP 409)
+var ErrProductInUse = errors.New("product is still in use")
+
 type CreateProductInput struct {
 	Name          string  `json:"name" binding:"required"`
+	SKU           string  `json:"sku" binding:"max=64"` // Kosong = di-generate otomatis
+	Barcode       string  `json:"barcode"`                 // Optional, EAN-8 / UPC-A / EAN-13
 	Stock         int     `json:"stock" binding:"required,min=0"`
 	Price         float64 `json:"price" binding:"required,min=1"`
 	ProductTypeID string  `json:"product_type_id" binding:"required"`
+	WarehouseID   string  `json:"warehouse_id"` // Gudang untuk stok awal, kosong = gudang default
+	SupplierID    string  `json:"supplier_id"`  // Supplier utama untuk perhitungan lead time reorder
 }
 
-func (s *ProductService) Create(input CreateProductInput) (models.Product, error) {
+// Create - Tambah produk master, stok awal dicatat sebagai movement ADJUSTMENT
+func (s *ProductService) Create(input CreateProductInput, actorID string) (models.Product, error) {
 	typeUUID, _ := uuid.Parse(input.ProductTypeID)
 	product := models.Product{
-		Name: input.Name, Stock: input.Stock, Price: input.Price, ProductTypeID: typeUUID,
+		Name: input.Name, SKU: strings.TrimSpace(input.SKU), Stock: 0, Price: input.Price, ProductTypeID: typeUUID,
+	}
+	if err := ensureSKUAvailable(database.DB, product.SKU, uuid.Nil); err != nil {
+		return product, err
+	}
+	if product.SKU == "" {
+		product.SKU = generateUniqueSKU(database.DB, typeUUID)
+	}
+	barcode, err := normalizeBarcode(database.DB, input.Barcode, uuid.Nil)
+	if err != nil {
+		return product, err
+	}
+	product.Barcode = barcode
+	if input.SupplierID != "" {
+		supplierID, err := resolveSupplierID(input.SupplierID)
+		if err != nil {
+			return product, err
+		}
+		product.SupplierID = supplierID
 	}
-	err := database.DB.Create(&product).Error
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&product).Error; err != nil {
+			return err
+		}
+		if input.Stock == 0 {
+			return nil
+		}
+		warehouseID, err := resolveWarehouseID(tx, input.WarehouseID)
+		if err != nil {
+			return err
+		}
+		movement, err := applyStockMovement(tx, stockChange{
+			ProductID:   product.ID,
+			WarehouseID: warehouseID,
+			Delta:       input.Stock,
+			Type:        models.MovementAdjustment,
+			Reason:      "Stok awal",
+			ActorID:     parseOptionalUUID(actorID),
+		})
+		product.Stock = movement.BalanceAfter
+		return err
+	})
 	return product, err
 }
 
@@ -31,23 +83,95 @@ func (s *ProductService) FindAll() ([]models.Product, error) {
 	return products, err
 }
 
-func (s *ProductService) Delete(id string) error {
-	return database.DB.Delete(&models.Product{}, "id = ?", id).Error
+// Delete - Soft delete produk master
+// Ditolak jika masih dipajang aktif oleh seller atau masih ada transaksi PENDING.
+// Etalase seller yang sudah nonaktif ikut di-soft delete, transaksi lama tetap utuh.
+// Dicatat di audit log sebagai PRODUCT_DELETED.
+func (s *ProductService) Delete(id string, audit *AuditContext) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var product models.Product
+		if err := tx.First(&product, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		var activeListings int64
+		tx.Model(&models.SellerProduct{}).
+			Where("product_id = ? AND is_active = ?", product.ID, true).
+			Count(&activeListings)
+		if activeListings > 0 {
+			return fmt.Errorf("%w: %d active seller listing(s)", ErrProductInUse, activeListings)
+		}
+
+		var pendingOrders int64
+		tx.Model(&models.Transaction{}).
+			Joins("JOIN seller_products ON seller_products.id = transactions.seller_product_id").
+			Where("seller_products.product_id = ? AND transactions.status = ?", product.ID, models.StatusPending).
+			Count(&pendingOrders)
+		if pendingOrders > 0 {
+			return fmt.Errorf("%w: %d pending transaction(s)", ErrProductInUse, pendingOrders)
+		}
+
+		if err := tx.Where("product_id = ?", product.ID).Delete(&models.SellerProduct{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&product).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditProductDeleted, "product", &product.ID, map[string]interface{}{
+			"before": map[string]interface{}{"name": product.Name, "sku": product.SKU, "price": product.Price, "product_type_id": product.ProductTypeID},
+		})
+	})
+}
+// ErrStockWarehouseRequired - Total stok baru mengurangi lebih dari stok gudang default,
+// admin harus menyebut warehouse_id yang stoknya dikurangi (HTTP 400)
+var ErrStockWarehouseRequired = errors.New("stock reduction exceeds default warehouse stock, specify warehouse_id")
+
+// ErrDuplicateSKU - SKU sudah dipakai produk lain
+var ErrDuplicateSKU = errors.New("sku already exists")
+
+// ensureSKUAvailable - SKU kosong selalu boleh, selain itu harus unik di antara produk aktif
+func ensureSKUAvailable(tx *gorm.DB, sku string, exceptID uuid.UUID) error {
+	if sku == "" {
+		return nil
+	}
+	var count int64
+	tx.Model(&models.Product{}).Where("sku = ? AND id <> ?", sku, exceptID).Count(&count)
+	if count > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateSKU, sku)
+	}
+	return nil
 }
+
 // Update Product - Admin dapat update produk master
 type UpdateProductInput struct {
 	Name          *string  `json:"name"`
-	Stock         *int     `json:"stock" binding:"omitempty,min=0"`
+	SKU           *string  `json:"sku" binding:"omitempty,max=64"`
+	Barcode       *string  `json:"barcode"` // String kosong = hapus barcode
+	PricePolicy   string   `json:"price_policy" binding:"omitempty,oneof=DEACTIVATE REPRICE NOTIFY"` // Penanganan etalase jika harga modal naik, kosong = MASTER_PRICE_POLICY
+	Stock         *int     `json:"stock" binding:"omitempty,min=0"` // Tanpa warehouse_id: total stok baru (selisih di gudang default), dengan warehouse_id: stok baru di gudang tersebut
+	StockReason   *string  `json:"stock_reason"` // Alasan koreksi stok (dicatat di ledger)
+	WarehouseID   *string  `json:"warehouse_id"` // Opsional, gudang yang stoknya diubah
 	Price         *float64 `json:"price" binding:"omitempty,min=1"`
 	ProductTypeID *string  `json:"product_type_id"`
+	SupplierID    *string  `json:"supplier_id"` // String kosong = lepas supplier
 }
 
-func (s *ProductService) Update(id string, input UpdateProductInput) (models.Product, error) {
+// Update - Perubahan stok tidak lagi menimpa kolom langsung,
+// selisihnya dicatat sebagai movement ADJUSTMENT di ledger. Tanpa warehouse_id, stock tetap berarti
+// total stok produk (seperti sebelum multi-gudang) dan selisihnya dibukukan di gudang default.
+// Jika harga modal berubah, etalase seller yang terdampak ditangani sesuai price policy.
+// Perubahan field produk dicatat di audit log sebagai PRODUCT_UPDATED (before/after).
+func (s *ProductService) Update(id string, actorID string, input UpdateProductInput, audit *AuditContext) (models.Product, *PriceChangeResult, error) {
 	var product models.Product
+	var priceChange *PriceChangeResult
 	
 	// Check if product exists
 	if err := database.DB.First(&product, "id = ?", id).Error; err != nil {
-		return product, err
+		return product, nil, err
+	}
+	policy, err := resolvePricePolicy(input.PricePolicy)
+	if err != nil {
+		return product, nil, err
 	}
 
 	// Update only provided fields
@@ -55,8 +179,22 @@ func (s *ProductService) Update(id string, input UpdateProductInput) (models.Pro
 	if input.Name != nil {
 		updates["name"] = *input.Name
 	}
-	if input.Stock != nil {
-		updates["stock"] = *input.Stock
+	if input.SKU != nil {
+		sku := strings.TrimSpace(*input.SKU)
+		if sku == "" {
+			return product, nil, errors.New("sku cannot be empty")
+		}
+		if err := ensureSKUAvailable(database.DB, sku, product.ID); err != nil {
+			return product, nil, err
+		}
+		updates["sku"] = sku
+	}
+	if input.Barcode != nil {
+		barcode, err := normalizeBarcode(database.DB, *input.Barcode, product.ID)
+		if err != nil {
+			return product, nil, err
+		}
+		updates["barcode"] = barcode
 	}
 	if input.Price != nil {
 		updates["price"] = *input.Price
@@ -64,18 +202,106 @@ func (s *ProductService) Update(id string, input UpdateProductInput) (models.Pro
 	if input.ProductTypeID != nil {
 		typeUUID, err := uuid.Parse(*input.ProductTypeID)
 		if err != nil {
-			return product, err
+			return product, nil, err
 		}
 		updates["product_type_id"] = typeUUID
 	}
+	if input.SupplierID != nil {
+		supplierID, err := resolveSupplierID(*input.SupplierID)
+		if err != nil {
+			return product, nil, err
+		}
+		updates["supplier_id"] = supplierID
+	}
 
-	if err := database.DB.Model(&product).Updates(updates).Error; err != nil {
-		return product, err
+	before := map[string]interface{}{
+		"name":            product.Name,
+		"sku":             product.SKU,
+		"barcode":         product.Barcode,
+		"price":           product.Price,
+		"product_type_id": product.ProductTypeID,
+		"supplier_id":     product.SupplierID,
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		oldPrice := product.Price
+		if len(updates) > 0 {
+			if err := tx.Model(&product).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if input.Price != nil && *input.Price != oldPrice {
+			var err error
+			if priceChange, err = handleMasterPriceChange(tx, product, oldPrice, *input.Price, policy); err != nil {
+				return err
+			}
+		}
+		// Perubahan stok sudah tercatat di ledger (dengan actor), audit log cukup field produk
+		if len(updates) > 0 {
+			changes := auditChanges(before, updates)
+			if priceChange != nil {
+				changes["price_change"] = priceChange
+			}
+			if err := audit.Record(tx, models.AuditProductUpdated, "product", &product.ID, changes); err != nil {
+				return err
+			}
+		}
+
+		if input.Stock == nil {
+			return nil
+		}
+
+		warehouseInput := ""
+		if input.WarehouseID != nil {
+			warehouseInput = strings.TrimSpace(*input.WarehouseID)
+		}
+		warehouseID, err := resolveWarehouseID(tx, warehouseInput)
+		if err != nil {
+			return err
+		}
+
+		// Hitung selisih dari stok terkini (dikunci) agar tidak balapan dengan ConfirmOrder
+		var current models.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, "id = ?", product.ID).Error; err != nil {
+			return err
+		}
+		quantity, err := warehouseQuantity(tx, warehouseID, product.ID)
+		if err != nil {
+			return err
+		}
+		delta := *input.Stock - quantity
+		if warehouseInput == "" {
+			// Total stok: pengurangan yang melebihi stok gudang default harus menyebut gudangnya
+			delta = *input.Stock - current.Stock
+			if quantity+delta < 0 {
+				return fmt.Errorf("%w: stok gudang default %d, total stok %d", ErrStockWarehouseRequired, quantity, current.Stock)
+			}
+		}
+		if delta == 0 {
+			return nil
+		}
+
+		reason := "Manual stock update"
+		if input.StockReason != nil && *input.StockReason != "" {
+			reason = *input.StockReason
+		}
+		_, err = applyStockMovement(tx, stockChange{
+			ProductID:   product.ID,
+			WarehouseID: warehouseID,
+			Delta:       delta,
+			Type:        models.MovementAdjustment,
+			Reason:      reason,
+			ActorID:     parseOptionalUUID(actorID),
+		})
+		return err
+	})
+	if err != nil {
+		return product, nil, err
 	}
 
 	// Reload with ProductType
 	database.DB.Preload("ProductType").First(&product, "id = ?", id)
-	return product, nil
+	return product, priceChange, nil
 }
 
 // FindAll with filters - Search and Category filter
@@ -86,7 +312,7 @@ func (s *ProductService) FindAllWithFilters(search string, productTypeID string)
 	
 	// Search filter
 	if search != "" {
-		query = query.Where("name ILIKE ?", "%"+search+"%")
+		query = query.Where("name ILIKE ? OR sku ILIKE ?", "%"+search+"%", "%"+search+"%")
 	}
 	
 	// Category/ProductType filter
@@ -98,15 +324,57 @@ func (s *ProductService) FindAllWithFilters(search string, productTypeID string)
 	return products, err
 }
 
+// LowStockItem - Produk stok rendah beserta rincian stok per gudang
+type LowStockItem struct {
+	models.Product
+	Warehouses []WarehouseStockLevel `json:"warehouses"`
+	OnOrder    int                   `json:"on_order"` // Sisa quantity di PO yang sudah dikirim ke supplier
+}
+
+// DefaultLowStockThreshold - Dipakai untuk produk yang reorder point-nya belum dihitung
+const DefaultLowStockThreshold = 10
+
 // GetLowStock - Get products with stock below threshold
-func (s *ProductService) GetLowStock(threshold int) ([]models.Product, error) {
+// threshold nil = pakai reorder point masing-masing produk (fallback DefaultLowStockThreshold)
+// Jika warehouseID diisi, threshold dibandingkan dengan stok di gudang tersebut
+func (s *ProductService) GetLowStock(threshold *int, warehouseID string) ([]LowStockItem, error) {
 	var products []models.Product
-	
-	err := database.DB.Preload("ProductType").
-		Where("stock <= ?", threshold).
-		Order("stock ASC").
-		Find(&products).Error
-	
-	return products, err
-}
 
+	limit := gorm.Expr("CASE WHEN products.reorder_point > 0 THEN products.reorder_point ELSE ? END", DefaultLowStockThreshold)
+	if threshold != nil {
+		limit = gorm.Expr("?", *threshold)
+	}
+
+	query := database.DB.Preload("ProductType")
+	if warehouseID != "" {
+		query = query.
+			Joins("LEFT JOIN warehouse_stocks ON warehouse_stocks.product_id = products.id AND warehouse_stocks.warehouse_id = ?", warehouseID).
+			Where("COALESCE(warehouse_stocks.quantity, 0) <= ?", limit).
+			Order("COALESCE(warehouse_stocks.quantity, 0) ASC")
+	} else {
+		query = query.Where("stock <= ?", limit).Order("stock ASC")
+	}
+	if err := query.Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(products))
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+	}
+	breakdown, err := getStockBreakdown(productIDs)
+	if err != nil {
+		return nil, err
+	}
+	onOrder := getOnOrderQuantities(productIDs)
+
+	items := []LowStockItem{}
+	for _, product := range products {
+		levels := breakdown[product.ID]
+		if levels == nil {
+			levels = []WarehouseStockLevel{}
+		}
+		items = append(items, LowStockItem{Product: product, Warehouses: levels, OnOrder: onOrder[product.ID]})
+	}
+	return items, nil
+}
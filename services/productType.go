@@ -1,12 +1,19 @@
 package services
 
 import (
+	"errors"
+	"fmt"
 	"technical-test-backend/database"
 	"technical-test-backend/models"
+
+	"gorm.io/gorm"
 )
 
 type ProductTypeService struct{}
 
+// ErrProductTypeInUse - Kategori masih dipakai oleh produk master (HTTP 409)
+var ErrProductTypeInUse = errors.New("product type is still in use")
+
 func (s *ProductTypeService) GetAll() ([]models.ProductType, error) {
 	var types []models.ProductType
 	err := database.DB.Find(&types).Error
@@ -19,16 +26,100 @@ func (s *ProductTypeService) Create(name string) (models.ProductType, error) {
 	return newType, err
 }
 
-func (s *ProductTypeService) Update(id, name string) (models.ProductType, error) {
+// Update - Ganti nama kategori, dicatat di audit log sebagai PRODUCT_TYPE_UPDATED
+func (s *ProductTypeService) Update(id, name string, audit *AuditContext) (models.ProductType, error) {
 	var productType models.ProductType
 	if err := database.DB.Where("id = ?", id).First(&productType).Error; err != nil {
 		return productType, err
 	}
+	before := map[string]interface{}{"name": productType.Name}
 	productType.Name = name
-	err := database.DB.Save(&productType).Error
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&productType).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditProductTypeUpdated, "product_type", &productType.ID,
+			auditChanges(before, map[string]interface{}{"name": name}))
+	})
 	return productType, err
 }
 
-func (s *ProductTypeService) Delete(id string) error {
-	return database.DB.Where("id = ?", id).Delete(&models.ProductType{}).Error
-}
\ No newline at end of file
+// Delete - Soft delete kategori
+// Ditolak jika masih ada produk master yang memakai kategori ini,
+// gunakan Merge untuk memindahkan produknya terlebih dahulu. Dicatat di audit log.
+func (s *ProductTypeService) Delete(id string, audit *AuditContext) error {
+	var productType models.ProductType
+	if err := database.DB.Where("id = ?", id).First(&productType).Error; err != nil {
+		return err
+	}
+
+	var productCount int64
+	database.DB.Model(&models.Product{}).Where("product_type_id = ?", productType.ID).Count(&productCount)
+	if productCount > 0 {
+		return fmt.Errorf("%w: %d product(s) still use this category", ErrProductTypeInUse, productCount)
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&productType).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditProductTypeDeleted, "product_type", &productType.ID, map[string]interface{}{
+			"before": map[string]interface{}{"name": productType.Name},
+		})
+	})
+}
+
+// MergeProductTypeInput - Input untuk memindahkan produk ke kategori lain
+type MergeProductTypeInput struct {
+	TargetProductTypeID string `json:"target_product_type_id" binding:"required"`
+	DeleteSource        bool   `json:"delete_source"` // Hapus kategori asal setelah dipindahkan
+}
+
+// MergeResult - Hasil operasi merge/reassign kategori
+type MergeResult struct {
+	Source        models.ProductType `json:"source"`
+	Target        models.ProductType `json:"target"`
+	MovedProducts int64              `json:"moved_products"`
+	SourceDeleted bool               `json:"source_deleted"`
+}
+
+// Merge - Pindahkan semua produk dari kategori sourceID ke kategori target
+// Alur: Validasi kedua kategori -> Update product_type_id -> (opsional) hapus kategori asal -> audit log
+func (s *ProductTypeService) Merge(sourceID string, input MergeProductTypeInput, audit *AuditContext) (MergeResult, error) {
+	var result MergeResult
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", sourceID).First(&result.Source).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", input.TargetProductTypeID).First(&result.Target).Error; err != nil {
+			return err
+		}
+		if result.Source.ID == result.Target.ID {
+			return errors.New("source and target product type must be different")
+		}
+
+		moved := tx.Model(&models.Product{}).
+			Where("product_type_id = ?", result.Source.ID).
+			Update("product_type_id", result.Target.ID)
+		if moved.Error != nil {
+			return moved.Error
+		}
+		result.MovedProducts = moved.RowsAffected
+
+		if input.DeleteSource {
+			if err := tx.Delete(&result.Source).Error; err != nil {
+				return err
+			}
+			result.SourceDeleted = true
+		}
+		return audit.Record(tx, models.AuditProductTypeMerged, "product_type", &result.Source.ID, map[string]interface{}{
+			"source":         map[string]interface{}{"id": result.Source.ID, "name": result.Source.Name},
+			"target":         map[string]interface{}{"id": result.Target.ID, "name": result.Target.Name},
+			"moved_products": result.MovedProducts,
+			"source_deleted": result.SourceDeleted,
+		})
+	})
+
+	return result, err
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrDuplicateBarcode - Barcode sudah dipakai produk lain
+var ErrDuplicateBarcode = errors.New("barcode already exists")
+
+// generateUniqueSKU - SKU otomatis dari 3 huruf kategori, dicoba ulang sampai tidak bentrok
+func generateUniqueSKU(tx *gorm.DB, productTypeID uuid.UUID) string {
+	var productType models.ProductType
+	tx.First(&productType, "id = ?", productTypeID)
+	prefix := utils.SKUPrefix(productType.Name)
+
+	for {
+		sku := utils.GenerateSKU(prefix)
+		if ensureSKUAvailable(tx, sku, uuid.Nil) == nil {
+			return sku
+		}
+	}
+}
+
+// normalizeBarcode - Trim & validasi checksum. String kosong = tanpa barcode (nil).
+func normalizeBarcode(tx *gorm.DB, barcode string, exceptID uuid.UUID) (*string, error) {
+	barcode = strings.TrimSpace(barcode)
+	if barcode == "" {
+		return nil, nil
+	}
+	if err := utils.ValidateGTIN(barcode); err != nil {
+		return nil, err
+	}
+
+	var count int64
+	tx.Model(&models.Product{}).Where("barcode = ? AND id <> ?", barcode, exceptID).Count(&count)
+	if count > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicateBarcode, barcode)
+	}
+	return &barcode, nil
+}
+
+// ProductCodeResult - Hasil scan SKU / barcode
+type ProductCodeResult struct {
+	models.Product
+	MatchedBy  string                `json:"matched_by"` // sku / barcode
+	Warehouses []WarehouseStockLevel `json:"warehouses"`
+}
+
+// GetByCode - Cari produk dari hasil scan: SKU (case-insensitive) atau barcode.
+// UPC-A 12 digit juga cocok dengan EAN-13 berawalan 0 (dan sebaliknya),
+// karena banyak scanner mengirim UPC sebagai EAN-13.
+func (s *ProductService) GetByCode(code string) (ProductCodeResult, error) {
+	code = strings.TrimSpace(code)
+	result := ProductCodeResult{}
+
+	barcodes := []string{code}
+	if len(code) == 12 {
+		barcodes = append(barcodes, "0"+code)
+	} else if len(code) == 13 && strings.HasPrefix(code, "0") {
+		barcodes = append(barcodes, code[1:])
+	}
+
+	err := database.DB.Preload("ProductType").Where("barcode IN ?", barcodes).First(&result.Product).Error
+	if err == nil {
+		result.MatchedBy = "barcode"
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = database.DB.Preload("ProductType").Where("UPPER(sku) = ?", strings.ToUpper(code)).First(&result.Product).Error
+		result.MatchedBy = "sku"
+	}
+	if err != nil {
+		return result, err
+	}
+
+	breakdown, err := getStockBreakdown([]uuid.UUID{result.ID})
+	if err != nil {
+		return result, err
+	}
+	result.Warehouses = breakdown[result.ID]
+	if result.Warehouses == nil {
+		result.Warehouses = []WarehouseStockLevel{}
+	}
+	return result, nil
+}
+
+// ProductLabel - Gambar label barcode siap cetak
+type ProductLabel struct {
+	ContentType string
+	Filename    string
+	Data        []byte
+}
+
+// GenerateLabel - Render label barcode produk
+// symbology: code128 (isi SKU) atau ean13 (isi barcode). Kosong = ean13 jika produk
+// punya barcode EAN-13/UPC-A, selain itu code128. format: png atau svg.
+func (s *ProductService) GenerateLabel(id string, symbology string, format string, scale int) (ProductLabel, error) {
+	var product models.Product
+	if err := database.DB.First(&product, "id = ?", id).Error; err != nil {
+		return ProductLabel{}, err
+	}
+
+	ean := ""
+	if product.Barcode != nil {
+		switch len(*product.Barcode) {
+		case 13:
+			ean = *product.Barcode
+		case 12:
+			ean = "0" + *product.Barcode // UPC-A = EAN-13 dengan digit awal 0
+		}
+	}
+	if symbology == "" {
+		symbology = "code128"
+		if ean != "" {
+			symbology = "ean13"
+		}
+	}
+
+	var modules []bool
+	var text string
+	var err error
+	switch symbology {
+	case "ean13":
+		if ean == "" {
+			return ProductLabel{}, errors.New("product has no EAN-13/UPC-A barcode, use symbology=code128")
+		}
+		modules, err = utils.EncodeEAN13(ean)
+		text = ean
+	case "code128":
+		if product.SKU == "" {
+			return ProductLabel{}, errors.New("product has no SKU")
+		}
+		modules, err = utils.EncodeCode128(product.SKU)
+		text = product.SKU
+	default:
+		return ProductLabel{}, errors.New("symbology must be code128 or ean13")
+	}
+	if err != nil {
+		return ProductLabel{}, err
+	}
+
+	if scale <= 0 || scale > 10 {
+		scale = 2
+	}
+	height := 40 * scale
+
+	var buf bytes.Buffer
+	label := ProductLabel{Filename: fmt.Sprintf("%s-%s.%s", text, symbology, format)}
+	switch format {
+	case "svg":
+		label.ContentType = "image/svg+xml"
+		err = utils.RenderBarcodeSVG(&buf, modules, scale, height, text)
+	case "png":
+		label.ContentType = "image/png"
+		err = utils.RenderBarcodePNG(&buf, modules, scale, height)
+	default:
+		return ProductLabel{}, errors.New("format must be png or svg")
+	}
+	if err != nil {
+		return ProductLabel{}, err
+	}
+	label.Data = buf.Bytes()
+	return label, nil
+}
@@ -0,0 +1,460 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrImportInvalid - File import punya baris yang tidak valid, tidak ada data yang disimpan (HTTP 422)
+var ErrImportInvalid = errors.New("import file contains invalid rows")
+
+// ProductFileColumns - Format kolom import/export produk master (urutan export)
+var ProductFileColumns = []string{"sku", "barcode", "name", "product_type", "price", "stock", "warehouse_code"}
+
+// ImportRowResult - Hasil validasi satu baris file (Row = nomor baris di file, header = 1)
+type ImportRowResult struct {
+	Row    int    `json:"row"`
+	SKU    string `json:"sku"`
+	Name   string `json:"name"`
+	Action string `json:"action,omitempty"` // create / update
+	// Jumlah etalase seller yang harga jualnya akan di bawah harga modal baru
+	AffectedListings int      `json:"affected_listings,omitempty"`
+	Errors           []string `json:"errors,omitempty"`
+}
+
+// ImportReport - Ringkasan import (dry run maupun commit)
+type ImportReport struct {
+	DryRun     bool              `json:"dry_run"`
+	Committed  bool              `json:"committed"`
+	TotalRows  int               `json:"total_rows"`
+	ToCreate   int               `json:"to_create"`
+	ToUpdate   int               `json:"to_update"`
+	ErrorCount int               `json:"error_count"`
+	Rows       []ImportRowResult `json:"rows"`
+
+	PricePolicy  string              `json:"price_policy"`
+	PriceChanges []PriceChangeResult `json:"price_changes,omitempty"`
+}
+
+// plannedImportRow - Baris yang sudah divalidasi dan siap disimpan
+type plannedImportRow struct {
+	existing      *models.Product
+	sku           string
+	barcode       *string
+	name          string
+	productTypeID uuid.UUID
+	price         float64
+	stock         *int
+	warehouseID   uuid.UUID
+}
+
+// ParseProductFile - Baca file CSV/XLSX menjadi baris string
+// format kosong = ditebak dari ekstensi filename
+func ParseProductFile(filename string, format string, data []byte) ([][]string, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	}
+
+	switch format {
+	case "xlsx":
+		return utils.ReadXLSX(bytes.NewReader(data), int64(len(data)))
+	case "csv":
+		data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // BOM dari Excel
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		// Excel dengan locale Indonesia menyimpan CSV dengan pemisah ';'
+		firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+		if bytes.Contains(firstLine, []byte(";")) && !bytes.Contains(firstLine, []byte(",")) {
+			reader.Comma = ';'
+		}
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv file: %w", err)
+		}
+		return rows, nil
+	default:
+		return nil, errors.New("unsupported file format, use csv or xlsx")
+	}
+}
+
+// ImportProducts - Validasi semua baris, lalu (jika bukan dry run dan tidak ada error)
+// create / upsert by SKU dalam satu DB transaction. Satu baris gagal = semua batal.
+// Perubahan stok dicatat sebagai movement ADJUSTMENT di gudang warehouse_code (kosong = default).
+// Kenaikan harga modal ditangani dengan pricePolicy (kosong = MASTER_PRICE_POLICY).
+func (s *ProductService) ImportProducts(rows [][]string, dryRun bool, actorID string, pricePolicy string) (ImportReport, error) {
+	report := ImportReport{DryRun: dryRun, Rows: []ImportRowResult{}}
+	policy, err := resolvePricePolicy(pricePolicy)
+	if err != nil {
+		return report, err
+	}
+	report.PricePolicy = policy
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		planned, err := planProductImport(tx, rows, &report)
+		if err != nil {
+			return err
+		}
+		if report.ErrorCount > 0 {
+			return ErrImportInvalid
+		}
+		if dryRun {
+			return nil
+		}
+
+		actor := parseOptionalUUID(actorID)
+		for _, row := range planned {
+			priceChange, err := applyImportRow(tx, row, actor, policy)
+			if err != nil {
+				return fmt.Errorf("sku %s: %w", row.sku, err)
+			}
+			if priceChange != nil {
+				report.PriceChanges = append(report.PriceChanges, *priceChange)
+			}
+		}
+		report.Committed = true
+		return nil
+	})
+	return report, err
+}
+
+// planProductImport - Validasi header & setiap baris, hasilnya ditulis ke report
+func planProductImport(tx *gorm.DB, rows [][]string, report *ImportReport) ([]plannedImportRow, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	columns := make(map[string]int)
+	for i, header := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+	for _, required := range []string{"sku", "name", "product_type", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column %q, expected columns: %s", required, strings.Join(ProductFileColumns, ","))
+		}
+	}
+	cell := func(row []string, name string) string {
+		idx, ok := columns[name]
+		if !ok || idx >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[idx])
+	}
+
+	// Lookup master data sekali di awal
+	var productTypes []models.ProductType
+	tx.Find(&productTypes)
+	typeByName := make(map[string]uuid.UUID, len(productTypes))
+	for _, pt := range productTypes {
+		typeByName[strings.ToLower(pt.Name)] = pt.ID
+	}
+
+	var warehouses []models.Warehouse
+	tx.Find(&warehouses)
+	warehouseByCode := make(map[string]models.Warehouse, len(warehouses))
+	var defaultWarehouse *models.Warehouse
+	for i, wh := range warehouses {
+		warehouseByCode[strings.ToUpper(wh.Code)] = wh
+		if wh.IsDefault {
+			defaultWarehouse = &warehouses[i]
+		}
+	}
+
+	skus := []string{}
+	for _, row := range rows[1:] {
+		if sku := cell(row, "sku"); sku != "" {
+			skus = append(skus, sku)
+		}
+	}
+	var existingProducts []models.Product
+	if len(skus) > 0 {
+		tx.Where("sku IN ?", skus).Find(&existingProducts)
+	}
+	existingBySKU := make(map[string]models.Product, len(existingProducts))
+	for _, p := range existingProducts {
+		existingBySKU[p.SKU] = p
+	}
+
+	barcodes := []string{}
+	for _, row := range rows[1:] {
+		if barcode := cell(row, "barcode"); barcode != "" {
+			barcodes = append(barcodes, barcode)
+		}
+	}
+	barcodeOwner := make(map[string]string)
+	if len(barcodes) > 0 {
+		var owners []models.Product
+		tx.Where("barcode IN ?", barcodes).Find(&owners)
+		for _, p := range owners {
+			barcodeOwner[*p.Barcode] = p.SKU
+		}
+	}
+	seenBarcode := make(map[string]int)
+
+	planned := []plannedImportRow{}
+	seen := make(map[string]int)
+	for i, row := range rows[1:] {
+		rowNumber := i + 2
+		if isBlankRow(row) {
+			continue
+		}
+		report.TotalRows++
+
+		result := ImportRowResult{Row: rowNumber, SKU: cell(row, "sku"), Name: cell(row, "name")}
+		plan := plannedImportRow{sku: result.SKU, name: result.Name}
+
+		switch {
+		case plan.sku == "":
+			result.Errors = append(result.Errors, "sku is required")
+		case len(plan.sku) > 64:
+			result.Errors = append(result.Errors, "sku must be at most 64 characters")
+		case seen[plan.sku] > 0:
+			result.Errors = append(result.Errors, fmt.Sprintf("duplicate sku, already used on row %d", seen[plan.sku]))
+		default:
+			seen[plan.sku] = rowNumber
+		}
+
+		if barcode := cell(row, "barcode"); barcode != "" {
+			if err := utils.ValidateGTIN(barcode); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+			} else if owner, ok := barcodeOwner[barcode]; ok && owner != plan.sku {
+				result.Errors = append(result.Errors, fmt.Sprintf("barcode already used by sku %s", owner))
+			} else if seenBarcode[barcode] > 0 {
+				result.Errors = append(result.Errors, fmt.Sprintf("duplicate barcode, already used on row %d", seenBarcode[barcode]))
+			} else {
+				seenBarcode[barcode] = rowNumber
+				plan.barcode = &barcode
+			}
+		}
+
+		if plan.name == "" {
+			result.Errors = append(result.Errors, "name is required")
+		} else if len(plan.name) > 100 {
+			result.Errors = append(result.Errors, "name must be at most 100 characters")
+		}
+
+		typeName := cell(row, "product_type")
+		if id, ok := typeByName[strings.ToLower(typeName)]; ok {
+			plan.productTypeID = id
+		} else if typeName == "" {
+			result.Errors = append(result.Errors, "product_type is required")
+		} else {
+			result.Errors = append(result.Errors, fmt.Sprintf("product type %q not found", typeName))
+		}
+
+		price, err := strconv.ParseFloat(cell(row, "price"), 64)
+		switch {
+		case err != nil:
+			result.Errors = append(result.Errors, "price must be a number")
+		case price < 1:
+			result.Errors = append(result.Errors, "price must be at least 1")
+		case price >= 1e8:
+			result.Errors = append(result.Errors, "price is too large")
+		default:
+			plan.price = price
+		}
+
+		if raw := cell(row, "stock"); raw != "" {
+			stock, err := strconv.Atoi(raw)
+			if err != nil || stock < 0 {
+				result.Errors = append(result.Errors, "stock must be a whole number >= 0")
+			} else {
+				plan.stock = &stock
+			}
+		}
+
+		if code := strings.ToUpper(cell(row, "warehouse_code")); code != "" {
+			if wh, ok := warehouseByCode[code]; ok {
+				plan.warehouseID = wh.ID
+			} else {
+				result.Errors = append(result.Errors, fmt.Sprintf("warehouse %q not found", code))
+			}
+		} else if plan.stock != nil {
+			if defaultWarehouse == nil {
+				result.Errors = append(result.Errors, "default warehouse is not configured")
+			} else {
+				plan.warehouseID = defaultWarehouse.ID
+			}
+		}
+
+		if existing, ok := existingBySKU[plan.sku]; ok {
+			plan.existing = &existing
+			result.Action = "update"
+			if plan.price > 0 && plan.price != existing.Price {
+				var affected int64
+				tx.Model(&models.SellerProduct{}).Where("product_id = ? AND selling_price < ?", existing.ID, plan.price).Count(&affected)
+				result.AffectedListings = int(affected)
+			}
+		} else {
+			result.Action = "create"
+		}
+
+		if len(result.Errors) > 0 {
+			report.ErrorCount++
+			result.Action = ""
+		} else if plan.existing != nil {
+			report.ToUpdate++
+		} else {
+			report.ToCreate++
+		}
+		report.Rows = append(report.Rows, result)
+		planned = append(planned, plan)
+	}
+
+	if report.TotalRows == 0 {
+		return nil, errors.New("file has no data rows")
+	}
+	return planned, nil
+}
+
+// applyImportRow - Simpan satu baris yang sudah lolos validasi
+func applyImportRow(tx *gorm.DB, row plannedImportRow, actorID *uuid.UUID, pricePolicy string) (*PriceChangeResult, error) {
+	product := models.Product{}
+	var priceChange *PriceChangeResult
+	if row.existing == nil {
+		product = models.Product{Name: row.name, SKU: row.sku, Barcode: row.barcode, Price: row.price, ProductTypeID: row.productTypeID}
+		if err := tx.Create(&product).Error; err != nil {
+			return nil, err
+		}
+	} else {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, "id = ?", row.existing.ID).Error; err != nil {
+			return nil, err
+		}
+		oldPrice := product.Price
+		updates := map[string]interface{}{
+			"name":            row.name,
+			"price":           row.price,
+			"product_type_id": row.productTypeID,
+		}
+		if row.barcode != nil {
+			updates["barcode"] = *row.barcode
+		}
+		if err := tx.Model(&product).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+		var err error
+		if priceChange, err = handleMasterPriceChange(tx, product, oldPrice, row.price, pricePolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	if row.stock == nil {
+		return priceChange, nil
+	}
+	quantity, err := warehouseQuantity(tx, row.warehouseID, product.ID)
+	if err != nil {
+		return nil, err
+	}
+	delta := *row.stock - quantity
+	if delta == 0 {
+		return priceChange, nil
+	}
+	_, err = applyStockMovement(tx, stockChange{
+		ProductID:   product.ID,
+		WarehouseID: row.warehouseID,
+		Delta:       delta,
+		Type:        models.MovementAdjustment,
+		Reason:      "Import produk",
+		ActorID:     actorID,
+	})
+	return priceChange, err
+}
+
+func isBlankRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ExportProducts - Semua produk master dalam format yang sama dengan import
+// Kolom stock berisi stok di gudang warehouseCode (kosong = gudang default),
+// sehingga file hasil export bisa langsung di-import ulang.
+func (s *ProductService) ExportProducts(warehouseCode string) ([][]interface{}, error) {
+	var warehouse models.Warehouse
+	query := database.DB.Where("is_default = ?", true)
+	if warehouseCode != "" {
+		query = database.DB.Where("code = ?", strings.ToUpper(warehouseCode))
+	}
+	if err := query.First(&warehouse).Error; err != nil {
+		return nil, errors.New("warehouse not found")
+	}
+
+	var results []struct {
+		SKU         string
+		Barcode     *string
+		Name        string
+		ProductType string
+		Price       float64
+		Stock       int
+	}
+	err := database.DB.Table("products").
+		Select("products.sku, products.barcode, products.name, product_types.name as product_type, products.price, COALESCE(warehouse_stocks.quantity, 0) as stock").
+		Joins("JOIN product_types ON product_types.id = products.product_type_id").
+		Joins("LEFT JOIN warehouse_stocks ON warehouse_stocks.product_id = products.id AND warehouse_stocks.warehouse_id = ?", warehouse.ID).
+		Where("products.deleted_at IS NULL").
+		Order("products.name ASC").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]interface{}, len(ProductFileColumns))
+	for i, col := range ProductFileColumns {
+		header[i] = col
+	}
+	rows := [][]interface{}{header}
+	for _, r := range results {
+		barcode := ""
+		if r.Barcode != nil {
+			barcode = *r.Barcode
+		}
+		rows = append(rows, []interface{}{r.SKU, barcode, r.Name, r.ProductType, r.Price, r.Stock, warehouse.Code})
+	}
+	return rows, nil
+}
+
+// WriteProductFile - Tulis hasil ExportProducts sebagai csv atau xlsx
+func WriteProductFile(w io.Writer, format string, rows [][]interface{}) error {
+	return WriteTableFile(w, format, "Products", rows)
+}
+
+// WriteTableFile - Tulis baris export (baris pertama = header) sebagai csv atau xlsx
+func WriteTableFile(w io.Writer, format string, sheet string, rows [][]interface{}) error {
+	if format == "xlsx" {
+		return utils.WriteXLSX(w, sheet, rows)
+	}
+
+	writer := csv.NewWriter(w)
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			switch val := v.(type) {
+			case float64:
+				record[i] = strconv.FormatFloat(val, 'f', -1, 64)
+			default:
+				record[i] = fmt.Sprint(val)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
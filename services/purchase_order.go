@@ -0,0 +1,382 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PurchaseOrderService menangani pembelian ke supplier dan penerimaan barang
+type PurchaseOrderService struct{}
+
+// ErrInvalidPOStatus - Aksi tidak diizinkan pada status PO saat ini (HTTP 409)
+var ErrInvalidPOStatus = errors.New("invalid purchase order status")
+
+// PurchaseOrderLineInput - Satu baris barang di PO
+type PurchaseOrderLineInput struct {
+	ProductID string  `json:"product_id" binding:"required"`
+	Quantity  int     `json:"quantity" binding:"required,min=1"`
+	UnitCost  float64 `json:"unit_cost" binding:"required,gt=0"`
+}
+
+// PurchaseOrderInput - Input create/update PO (hanya saat DRAFT)
+type PurchaseOrderInput struct {
+	SupplierID  string                   `json:"supplier_id" binding:"required"`
+	WarehouseID string                   `json:"warehouse_id"` // Kosong = gudang default
+	Notes       string                   `json:"notes"`
+	ExpectedAt  *time.Time               `json:"expected_at"`
+	Lines       []PurchaseOrderLineInput `json:"lines" binding:"required,min=1,dive"`
+}
+
+// ReceiveLineInput - Jumlah barang yang diterima untuk satu baris PO
+type ReceiveLineInput struct {
+	LineID   string `json:"line_id" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required,min=1"`
+}
+
+// ReceivePurchaseOrderInput - Input penerimaan barang (boleh sebagian)
+type ReceivePurchaseOrderInput struct {
+	Lines []ReceiveLineInput `json:"lines" binding:"required,min=1,dive"`
+	Note  string             `json:"note"`
+}
+
+// GetAll - Daftar PO, bisa difilter status dan supplier
+func (s *PurchaseOrderService) GetAll(status string, supplierID string) ([]models.PurchaseOrder, error) {
+	query := database.DB.Preload("Supplier").Preload("Warehouse").Preload("Lines.Product")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if supplierID != "" {
+		query = query.Where("supplier_id = ?", supplierID)
+	}
+
+	var orders []models.PurchaseOrder
+	err := query.Order("created_at DESC").Find(&orders).Error
+	return orders, err
+}
+
+func (s *PurchaseOrderService) GetByID(id string) (models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := database.DB.Preload("Supplier").Preload("Warehouse").Preload("Lines.Product").
+		First(&order, "id = ?", id).Error
+	return order, err
+}
+
+// Create - Buat PO baru dengan status DRAFT
+func (s *PurchaseOrderService) Create(actorID string, input PurchaseOrderInput) (models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		supplier, warehouseID, err := validatePurchaseOrderHeader(tx, input)
+		if err != nil {
+			return err
+		}
+		lines, err := buildPurchaseOrderLines(tx, input.Lines)
+		if err != nil {
+			return err
+		}
+
+		number, err := nextPurchaseOrderNumber(tx)
+		if err != nil {
+			return err
+		}
+
+		order = models.PurchaseOrder{
+			Number:      number,
+			SupplierID:  supplier.ID,
+			WarehouseID: warehouseID,
+			Status:      models.POStatusDraft,
+			Notes:       input.Notes,
+			ExpectedAt:  input.ExpectedAt,
+			CreatedByID: parseOptionalUUID(actorID),
+			Lines:       lines,
+		}
+		return tx.Create(&order).Error
+	})
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	return s.GetByID(order.ID.String())
+}
+
+// Update - Ubah header dan ganti seluruh baris PO selama masih DRAFT
+func (s *PurchaseOrderService) Update(id string, input PurchaseOrderInput) (models.PurchaseOrder, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var order models.PurchaseOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if order.Status != models.POStatusDraft {
+			return fmt.Errorf("%w: only DRAFT purchase orders can be edited (current: %s)", ErrInvalidPOStatus, order.Status)
+		}
+
+		supplier, warehouseID, err := validatePurchaseOrderHeader(tx, input)
+		if err != nil {
+			return err
+		}
+		lines, err := buildPurchaseOrderLines(tx, input.Lines)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("purchase_order_id = ?", order.ID).Delete(&models.PurchaseOrderLine{}).Error; err != nil {
+			return err
+		}
+		for i := range lines {
+			lines[i].PurchaseOrderID = order.ID
+		}
+		if err := tx.Create(&lines).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&order).Updates(map[string]interface{}{
+			"supplier_id":  supplier.ID,
+			"warehouse_id": warehouseID,
+			"notes":        input.Notes,
+			"expected_at":  input.ExpectedAt,
+		}).Error
+	})
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	return s.GetByID(id)
+}
+
+// Delete - Hapus PO yang masih DRAFT
+func (s *PurchaseOrderService) Delete(id string) error {
+	var order models.PurchaseOrder
+	if err := database.DB.First(&order, "id = ?", id).Error; err != nil {
+		return err
+	}
+	if order.Status != models.POStatusDraft {
+		return fmt.Errorf("%w: only DRAFT purchase orders can be deleted, close it instead", ErrInvalidPOStatus)
+	}
+	return database.DB.Select("Lines").Delete(&order).Error
+}
+
+// Send - DRAFT -> SENT (PO dikirim ke supplier)
+func (s *PurchaseOrderService) Send(id string) (models.PurchaseOrder, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var order models.PurchaseOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if order.Status != models.POStatusDraft {
+			return fmt.Errorf("%w: only DRAFT purchase orders can be sent (current: %s)", ErrInvalidPOStatus, order.Status)
+		}
+		now := time.Now()
+		return tx.Model(&order).Updates(map[string]interface{}{
+			"status":  models.POStatusSent,
+			"sent_at": &now,
+		}).Error
+	})
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	return s.GetByID(id)
+}
+
+// Receive - Terima barang dari supplier (penuh atau sebagian)
+// Alur per baris: Validasi sisa -> Hitung harga modal rata-rata tertimbang -> Update Price
+// -> Movement PURCHASE_RECEIPT ke gudang tujuan PO
+// Status menjadi RECEIVED jika semua baris lengkap, selain itu PARTIALLY_RECEIVED
+func (s *PurchaseOrderService) Receive(id string, actorID string, input ReceivePurchaseOrderInput) (models.PurchaseOrder, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var order models.PurchaseOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if order.Status != models.POStatusSent && order.Status != models.POStatusPartiallyReceived {
+			return fmt.Errorf("%w: goods can only be received for SENT or PARTIALLY_RECEIVED purchase orders (current: %s)", ErrInvalidPOStatus, order.Status)
+		}
+
+		var lines []models.PurchaseOrderLine
+		if err := tx.Where("purchase_order_id = ?", order.ID).Find(&lines).Error; err != nil {
+			return err
+		}
+		linesByID := make(map[string]*models.PurchaseOrderLine, len(lines))
+		for i := range lines {
+			linesByID[lines[i].ID.String()] = &lines[i]
+		}
+
+		reason := "Penerimaan " + order.Number
+		if input.Note != "" {
+			reason += ": " + input.Note
+		}
+
+		for _, received := range input.Lines {
+			line, ok := linesByID[received.LineID]
+			if !ok {
+				return fmt.Errorf("line %s does not belong to this purchase order", received.LineID)
+			}
+			remaining := line.Quantity - line.ReceivedQuantity
+			if received.Quantity > remaining {
+				return fmt.Errorf("line %s: receiving %d exceeds remaining quantity %d", received.LineID, received.Quantity, remaining)
+			}
+
+			var product models.Product
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, "id = ?", line.ProductID).Error; err != nil {
+				return err
+			}
+			oldPrice := product.Price
+			newPrice := weightedAverageCost(product.Stock, oldPrice, received.Quantity, line.UnitCost)
+			if err := tx.Model(&product).Update("price", newPrice).Error; err != nil {
+				return err
+			}
+			// Harga modal naik -> etalase seller yang rugi ditangani dengan MASTER_PRICE_POLICY
+			if _, err := handleMasterPriceChange(tx, product, oldPrice, newPrice, DefaultPricePolicy()); err != nil {
+				return err
+			}
+
+			if _, err := applyStockMovement(tx, stockChange{
+				ProductID:   product.ID,
+				WarehouseID: order.WarehouseID,
+				Delta:       received.Quantity,
+				Type:        models.MovementPurchaseReceipt,
+				Reason:      reason,
+				ActorID:     parseOptionalUUID(actorID),
+				ReferenceID: &order.ID,
+			}); err != nil {
+				return err
+			}
+
+			line.ReceivedQuantity += received.Quantity
+			if err := tx.Model(line).Update("received_quantity", line.ReceivedQuantity).Error; err != nil {
+				return err
+			}
+		}
+
+		status := models.POStatusReceived
+		for _, line := range lines {
+			if line.ReceivedQuantity < line.Quantity {
+				status = models.POStatusPartiallyReceived
+				break
+			}
+		}
+		updates := map[string]interface{}{"status": status}
+		if status == models.POStatusReceived {
+			now := time.Now()
+			updates["received_at"] = &now
+		}
+		return tx.Model(&order).Updates(updates).Error
+	})
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	return s.GetByID(id)
+}
+
+// Close - Tutup PO. Sisa barang yang belum diterima dianggap batal.
+func (s *PurchaseOrderService) Close(id string) (models.PurchaseOrder, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var order models.PurchaseOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if order.Status == models.POStatusClosed || order.Status == models.POStatusDraft {
+			return fmt.Errorf("%w: cannot close a %s purchase order", ErrInvalidPOStatus, order.Status)
+		}
+		now := time.Now()
+		return tx.Model(&order).Updates(map[string]interface{}{
+			"status":    models.POStatusClosed,
+			"closed_at": &now,
+		}).Error
+	})
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	return s.GetByID(id)
+}
+
+// weightedAverageCost - Harga modal baru = (stok lama * harga lama + qty masuk * harga beli) / total stok
+// Jika stok lama kosong, harga modal mengikuti harga beli terakhir
+func weightedAverageCost(oldStock int, oldPrice float64, receivedQty int, unitCost float64) float64 {
+	if oldStock <= 0 {
+		return math.Round(unitCost*100) / 100
+	}
+	total := float64(oldStock)*oldPrice + float64(receivedQty)*unitCost
+	return math.Round(total/float64(oldStock+receivedQty)*100) / 100
+}
+
+// validatePurchaseOrderHeader - Cek supplier aktif dan gudang tujuan
+func validatePurchaseOrderHeader(tx *gorm.DB, input PurchaseOrderInput) (models.Supplier, uuid.UUID, error) {
+	var supplier models.Supplier
+	if err := tx.First(&supplier, "id = ?", input.SupplierID).Error; err != nil {
+		return supplier, uuid.Nil, errors.New("supplier not found")
+	}
+	if !supplier.IsActive {
+		return supplier, uuid.Nil, errors.New("supplier is not active")
+	}
+	warehouseID, err := resolveWarehouseID(tx, input.WarehouseID)
+	return supplier, warehouseID, err
+}
+
+// buildPurchaseOrderLines - Validasi produk di setiap baris, satu produk hanya boleh muncul sekali
+func buildPurchaseOrderLines(tx *gorm.DB, inputs []PurchaseOrderLineInput) ([]models.PurchaseOrderLine, error) {
+	seen := make(map[uuid.UUID]bool, len(inputs))
+	lines := make([]models.PurchaseOrderLine, 0, len(inputs))
+	for _, in := range inputs {
+		var product models.Product
+		if err := tx.First(&product, "id = ?", in.ProductID).Error; err != nil {
+			return nil, fmt.Errorf("product %s not found", in.ProductID)
+		}
+		if seen[product.ID] {
+			return nil, fmt.Errorf("product %s appears more than once", product.Name)
+		}
+		seen[product.ID] = true
+		lines = append(lines, models.PurchaseOrderLine{
+			ProductID: product.ID,
+			Quantity:  in.Quantity,
+			UnitCost:  in.UnitCost,
+		})
+	}
+	return lines, nil
+}
+
+// purchaseOrderNumberLockID - Key pg_advisory_xact_lock agar pembuatan PO paralel tidak mendapat nomor yang sama
+const purchaseOrderNumberLockID = 460029
+
+// nextPurchaseOrderNumber - Format PO-YYYYMMDD-NNNN, urut per hari.
+// Harus dipanggil di dalam transaksi: lock dilepas saat commit, setelah PO dengan nomor ini tersimpan.
+func nextPurchaseOrderNumber(tx *gorm.DB) (string, error) {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", purchaseOrderNumberLockID).Error; err != nil {
+		return "", err
+	}
+	prefix := "PO-" + time.Now().Format("20060102") + "-"
+	var count int64
+	if err := tx.Unscoped().Model(&models.PurchaseOrder{}).Where("number LIKE ?", prefix+"%").Count(&count).Error; err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%04d", prefix, count+1), nil
+}
+
+// getOnOrderQuantities - Sisa quantity PO terbuka (SENT/PARTIALLY_RECEIVED) per produk
+func getOnOrderQuantities(productIDs []uuid.UUID) map[uuid.UUID]int {
+	result := make(map[uuid.UUID]int)
+	if len(productIDs) == 0 {
+		return result
+	}
+
+	var rows []struct {
+		ProductID uuid.UUID
+		OnOrder   int
+	}
+	database.DB.Table("purchase_order_lines").
+		Select("purchase_order_lines.product_id, SUM(purchase_order_lines.quantity - purchase_order_lines.received_quantity) as on_order").
+		Joins("JOIN purchase_orders ON purchase_orders.id = purchase_order_lines.purchase_order_id AND purchase_orders.deleted_at IS NULL").
+		Where("purchase_orders.status IN ? AND purchase_order_lines.deleted_at IS NULL AND purchase_order_lines.product_id IN ?",
+			[]string{models.POStatusSent, models.POStatusPartiallyReceived}, productIDs).
+		Group("purchase_order_lines.product_id").
+		Scan(&rows)
+
+	for _, r := range rows {
+		result[r.ProductID] = r.OnOrder
+	}
+	return result
+}
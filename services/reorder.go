@@ -0,0 +1,252 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReorderService menghitung reorder point & safety stock dari kecepatan penjualan
+type ReorderService struct{}
+
+// ReorderSettings - Parameter perhitungan, bisa diatur lewat environment variable
+type ReorderSettings struct {
+	LookbackDays    int     `json:"lookback_days"`     // REORDER_LOOKBACK_DAYS (default 30)
+	DefaultLeadTime int     `json:"default_lead_time"` // REORDER_DEFAULT_LEAD_TIME_DAYS, dipakai jika produk belum punya supplier (default 7)
+	CoverageDays    int     `json:"coverage_days"`     // REORDER_COVERAGE_DAYS, lama stok yang ingin dicover setelah barang tiba (default 30)
+	ServiceLevelZ   float64 `json:"service_level_z"`   // REORDER_SERVICE_LEVEL_Z, 1.65 ~ service level 95%
+}
+
+// LoadReorderSettings - Baca setting dari environment
+func LoadReorderSettings() ReorderSettings {
+	return ReorderSettings{
+		LookbackDays:    utils.EnvInt("REORDER_LOOKBACK_DAYS", 30),
+		DefaultLeadTime: utils.EnvInt("REORDER_DEFAULT_LEAD_TIME_DAYS", 7),
+		CoverageDays:    utils.EnvInt("REORDER_COVERAGE_DAYS", 30),
+		ServiceLevelZ:   utils.EnvFloat("REORDER_SERVICE_LEVEL_Z", 1.65),
+	}
+}
+
+// ReorderSuggestion - Satu baris laporan rekomendasi reorder
+type ReorderSuggestion struct {
+	ProductID           string   `json:"product_id"`
+	ProductName         string   `json:"product_name"`
+	SupplierID          string   `json:"supplier_id,omitempty"`
+	SupplierName        string   `json:"supplier_name,omitempty"`
+	Stock               int      `json:"stock"`
+	OnOrder             int      `json:"on_order"`
+	AvgDailySales       float64  `json:"avg_daily_sales"`
+	LeadTimeDays        int      `json:"lead_time_days"`
+	SafetyStock         int      `json:"safety_stock"`
+	ReorderPoint        int      `json:"reorder_point"`
+	DaysOfCover         *float64 `json:"days_of_cover"` // null jika belum ada penjualan
+	ProjectedStockoutAt *string  `json:"projected_stockout_at"`
+	RecommendedQuantity int      `json:"recommended_quantity"`
+	NeedsReorder        bool     `json:"needs_reorder"`
+	RunsOutInLeadTime   bool     `json:"runs_out_in_lead_time"`
+}
+
+// ReorderReport - Response laporan reorder
+type ReorderReport struct {
+	Settings ReorderSettings     `json:"settings"`
+	Count    int                 `json:"count"`
+	Data     []ReorderSuggestion `json:"data"`
+}
+
+// GetSuggestions - Hitung rekomendasi reorder secara live
+// Jika showAll false, hanya produk yang perlu dipesan ulang yang dikembalikan
+func (s *ReorderService) GetSuggestions(settings ReorderSettings, showAll bool) (ReorderReport, error) {
+	suggestions, err := computeReorderSuggestions(settings)
+	if err != nil {
+		return ReorderReport{}, err
+	}
+
+	data := []ReorderSuggestion{}
+	for _, item := range suggestions {
+		if showAll || item.NeedsReorder || item.RunsOutInLeadTime {
+			data = append(data, item)
+		}
+	}
+	return ReorderReport{Settings: settings, Count: len(data), Data: data}, nil
+}
+
+// ReorderRunResult - Ringkasan eksekusi job reorder
+type ReorderRunResult struct {
+	ProductsEvaluated int       `json:"products_evaluated"`
+	Flagged           int       `json:"flagged"`
+	NewlyFlagged      []string  `json:"newly_flagged"`
+	RanAt             time.Time `json:"ran_at"`
+}
+
+// Recalculate - Simpan reorder point, safety stock, dan rata-rata penjualan ke produk,
+// lalu tandai produk yang diproyeksikan habis dalam lead time supplier.
+// Dipanggil oleh job harian dan bisa dijalankan manual oleh admin.
+func (s *ReorderService) Recalculate(settings ReorderSettings) (ReorderRunResult, error) {
+	now := time.Now()
+	result := ReorderRunResult{NewlyFlagged: []string{}, RanAt: now}
+
+	suggestions, err := computeReorderSuggestions(settings)
+	if err != nil {
+		return result, err
+	}
+
+	var flaggedBefore []uuid.UUID
+	database.DB.Model(&models.Product{}).Where("reorder_flagged_at IS NOT NULL").Pluck("id", &flaggedBefore)
+	alreadyFlagged := make(map[string]bool, len(flaggedBefore))
+	for _, id := range flaggedBefore {
+		alreadyFlagged[id.String()] = true
+	}
+
+	for _, item := range suggestions {
+		updates := map[string]interface{}{
+			"avg_daily_sales":    item.AvgDailySales,
+			"safety_stock":       item.SafetyStock,
+			"reorder_point":      item.ReorderPoint,
+			"reorder_updated_at": now,
+		}
+		if item.RunsOutInLeadTime {
+			result.Flagged++
+			if !alreadyFlagged[item.ProductID] {
+				updates["reorder_flagged_at"] = now
+				result.NewlyFlagged = append(result.NewlyFlagged, item.ProductName)
+			}
+		} else {
+			updates["reorder_flagged_at"] = nil
+		}
+
+		if err := database.DB.Model(&models.Product{}).Where("id = ?", item.ProductID).Updates(updates).Error; err != nil {
+			return result, err
+		}
+		result.ProductsEvaluated++
+	}
+	return result, nil
+}
+
+// computeReorderSuggestions - Inti perhitungan:
+//
+//	avg   = total terjual (COMPLETED) dalam N hari / N
+//	sigma = standar deviasi penjualan harian (hari tanpa penjualan dihitung 0)
+//	safety stock  = ceil(Z * sigma * sqrt(lead time))
+//	reorder point = ceil(avg * lead time) + safety stock
+//	rekomendasi   = reorder point + avg * coverage days - (stok + on order), jika stok + on order <= reorder point
+func computeReorderSuggestions(settings ReorderSettings) ([]ReorderSuggestion, error) {
+	if settings.LookbackDays <= 0 {
+		settings.LookbackDays = 30
+	}
+
+	var products []models.Product
+	if err := database.DB.Preload("Supplier").Order("name ASC").Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	since := time.Now().AddDate(0, 0, -settings.LookbackDays+1)
+	since = time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, since.Location())
+
+	var rows []struct {
+		ProductID uuid.UUID
+		Day       time.Time
+		Quantity  int
+	}
+	err := database.DB.Raw(`
+		SELECT seller_products.product_id, DATE(transactions.created_at) as day, SUM(transactions.quantity) as quantity
+		FROM transactions
+		JOIN seller_products ON seller_products.id = transactions.seller_product_id
+		WHERE transactions.status = ? AND transactions.deleted_at IS NULL AND transactions.created_at >= ?
+		GROUP BY seller_products.product_id, DATE(transactions.created_at)
+	`, models.StatusCompleted, since).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	dailySales := make(map[uuid.UUID]map[string]int)
+	for _, r := range rows {
+		if dailySales[r.ProductID] == nil {
+			dailySales[r.ProductID] = make(map[string]int)
+		}
+		dailySales[r.ProductID][r.Day.Format("2006-01-02")] += r.Quantity
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(products))
+	for _, p := range products {
+		productIDs = append(productIDs, p.ID)
+	}
+	onOrder := getOnOrderQuantities(productIDs)
+
+	suggestions := make([]ReorderSuggestion, 0, len(products))
+	for _, p := range products {
+		series := make([]float64, settings.LookbackDays)
+		for i := range series {
+			day := since.AddDate(0, 0, i).Format("2006-01-02")
+			series[i] = float64(dailySales[p.ID][day])
+		}
+		avg, sigma := meanAndStdDev(series)
+
+		leadTime := settings.DefaultLeadTime
+		item := ReorderSuggestion{
+			ProductID:     p.ID.String(),
+			ProductName:   p.Name,
+			Stock:         p.Stock,
+			OnOrder:       onOrder[p.ID],
+			AvgDailySales: math.Round(avg*1000) / 1000,
+		}
+		if p.Supplier != nil {
+			leadTime = p.Supplier.LeadTimeDays
+			item.SupplierID = p.Supplier.ID.String()
+			item.SupplierName = p.Supplier.Name
+		}
+		item.LeadTimeDays = leadTime
+
+		item.SafetyStock = int(math.Ceil(settings.ServiceLevelZ * sigma * math.Sqrt(float64(leadTime))))
+		item.ReorderPoint = int(math.Ceil(avg*float64(leadTime))) + item.SafetyStock
+
+		if avg > 0 {
+			cover := math.Round(float64(p.Stock)/avg*10) / 10
+			item.DaysOfCover = &cover
+			stockout := time.Now().Add(time.Duration(float64(p.Stock) / avg * float64(24*time.Hour))).Format("2006-01-02")
+			item.ProjectedStockoutAt = &stockout
+			item.RunsOutInLeadTime = float64(p.Stock)/avg < float64(leadTime)
+
+			available := p.Stock + item.OnOrder
+			if available <= item.ReorderPoint {
+				item.NeedsReorder = true
+				target := item.ReorderPoint + int(math.Ceil(avg*float64(settings.CoverageDays)))
+				item.RecommendedQuantity = target - available
+			}
+		}
+
+		suggestions = append(suggestions, item)
+	}
+
+	// Yang paling cepat habis tampil di atas, produk tanpa penjualan di bawah
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		a, b := suggestions[i].DaysOfCover, suggestions[j].DaysOfCover
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return *a < *b
+	})
+	return suggestions, nil
+}
+
+// meanAndStdDev - Rata-rata dan standar deviasi populasi
+func meanAndStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"technical-test-backend/database"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type ReportService struct{}
@@ -127,12 +129,15 @@ func (s *ReportService) GetTopProducts(limit int) ([]TopProductItem, error) {
 
 // Top Sellers Report
 type TopSellerItem struct {
+	SellerID          string  `json:"seller_id"`
 	SellerName        string  `json:"seller_name"`
 	SellerEmail       string  `json:"seller_email"`
 	TotalProducts     int     `json:"total_products"`
 	TotalSales        float64 `json:"total_sales"`
 	TotalProfit       float64 `json:"total_profit"`
 	TotalTransactions int     `json:"total_transactions"`
+	AverageRating     float64 `json:"average_rating"` // Rata-rata rating ulasan yang tampil
+	ReviewCount       int64   `json:"review_count"`
 }
 
 func (s *ReportService) GetTopSellers(limit int) ([]TopSellerItem, error) {
@@ -141,6 +146,7 @@ func (s *ReportService) GetTopSellers(limit int) ([]TopSellerItem, error) {
 	}
 
 	var results []struct {
+		SellerID          uuid.UUID
 		SellerName        string
 		SellerEmail       string
 		TotalProducts     int
@@ -151,6 +157,7 @@ func (s *ReportService) GetTopSellers(limit int) ([]TopSellerItem, error) {
 
 	query := `
 		SELECT 
+			users.id as seller_id,
 			users.name as seller_name,
 			users.email as seller_email,
 			COUNT(DISTINCT seller_products.product_id) as total_products,
@@ -171,15 +178,27 @@ func (s *ReportService) GetTopSellers(limit int) ([]TopSellerItem, error) {
 		return nil, err
 	}
 
+	sellerIDs := make([]uuid.UUID, 0, len(results))
+	for _, r := range results {
+		sellerIDs = append(sellerIDs, r.SellerID)
+	}
+	ratings, err := getSellerRatings(sellerIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	var report []TopSellerItem
 	for _, r := range results {
 		report = append(report, TopSellerItem{
+			SellerID:          r.SellerID.String(),
 			SellerName:        r.SellerName,
 			SellerEmail:       r.SellerEmail,
 			TotalProducts:     r.TotalProducts,
 			TotalSales:        r.TotalSales,
 			TotalProfit:       r.TotalProfit,
 			TotalTransactions: r.TotalTransactions,
+			AverageRating:     ratings[r.SellerID].AverageRating,
+			ReviewCount:       ratings[r.SellerID].ReviewCount,
 		})
 	}
 
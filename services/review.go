@@ -0,0 +1,321 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReviewService menangani rating & ulasan pembeli, balasan seller, dan moderasi admin
+type ReviewService struct{}
+
+// ErrAlreadyReviewed - Transaksi sudah pernah diulas (HTTP 409)
+var ErrAlreadyReviewed = errors.New("transaction has already been reviewed")
+
+// ErrTransactionNotCompleted - Hanya transaksi COMPLETED yang bisa diulas (HTTP 409)
+var ErrTransactionNotCompleted = errors.New("only completed transactions can be reviewed")
+
+// ReviewInput - Rating 1-5 dan komentar opsional dari pembeli
+type ReviewInput struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5" example:"5"`
+	Comment string `json:"comment" binding:"max=2000" example:"Barang sesuai deskripsi, pengiriman cepat"`
+}
+
+// ReviewReplyInput - Balasan seller untuk ulasan
+type ReviewReplyInput struct {
+	Reply string `json:"reply" binding:"required,max=2000" example:"Terima kasih sudah berbelanja!"`
+}
+
+// ReviewModerationInput - Tampilkan / sembunyikan ulasan (admin)
+type ReviewModerationInput struct {
+	Status string `json:"status" binding:"required,oneof=VISIBLE HIDDEN" example:"HIDDEN"`
+	Reason string `json:"reason" binding:"max=500" example:"Mengandung kata kasar"`
+}
+
+// ReviewDetail - Response ulasan
+type ReviewDetail struct {
+	ID               string     `json:"id"`
+	TransactionID    string     `json:"transaction_id"`
+	SellerProductID  string     `json:"seller_product_id"`
+	ProductName      string     `json:"product_name"`
+	SellerName       string     `json:"seller_name"`
+	ReviewerName     string     `json:"reviewer_name"`
+	Rating           int        `json:"rating"`
+	Comment          string     `json:"comment"`
+	SellerReply      string     `json:"seller_reply"`
+	RepliedAt        *time.Time `json:"replied_at"`
+	Status           string     `json:"status"`
+	ModerationReason string     `json:"moderation_reason,omitempty"`
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// ListingReviews - Ringkasan rating dan ulasan yang tampil untuk satu etalase
+type ListingReviews struct {
+	SellerProductID string           `json:"seller_product_id"`
+	AverageRating   float64          `json:"average_rating"`
+	ReviewCount     int64            `json:"review_count"`
+	RatingBreakdown map[string]int64 `json:"rating_breakdown"` // "1".."5" -> jumlah ulasan
+	Reviews         []ReviewDetail   `json:"reviews"`
+}
+
+// ratingSummary - Rata-rata rating dan jumlah ulasan yang tampil (VISIBLE)
+type ratingSummary struct {
+	AverageRating float64
+	ReviewCount   int64
+}
+
+// getRatingSummaries - Agregasi rating ulasan VISIBLE, dikelompokkan per kolom
+// (seller_product_id atau seller_id). ID tanpa ulasan tidak ada di map.
+func getRatingSummaries(column string, ids []uuid.UUID) (map[uuid.UUID]ratingSummary, error) {
+	result := make(map[uuid.UUID]ratingSummary)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		GroupID       uuid.UUID
+		AverageRating float64
+		ReviewCount   int64
+	}
+	err := database.DB.Model(&models.Review{}).
+		Select(column+" AS group_id, AVG(rating) AS average_rating, COUNT(*) AS review_count").
+		Where("status = ?", models.ReviewVisible).
+		Where(column+" IN ?", ids).
+		Group(column).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.GroupID] = ratingSummary{
+			AverageRating: math.Round(row.AverageRating*10) / 10,
+			ReviewCount:   row.ReviewCount,
+		}
+	}
+	return result, nil
+}
+
+// getListingRatings - Rating per etalase seller
+func getListingRatings(listingIDs []uuid.UUID) (map[uuid.UUID]ratingSummary, error) {
+	return getRatingSummaries("seller_product_id", listingIDs)
+}
+
+// getSellerRatings - Rating per seller (semua etalase)
+func getSellerRatings(sellerIDs []uuid.UUID) (map[uuid.UUID]ratingSummary, error) {
+	return getRatingSummaries("seller_id", sellerIDs)
+}
+
+func toReviewDetail(review models.Review) ReviewDetail {
+	return ReviewDetail{
+		ID:               review.ID.String(),
+		TransactionID:    review.TransactionID.String(),
+		SellerProductID:  review.SellerProductID.String(),
+		ProductName:      review.SellerProduct.Product.Name,
+		SellerName:       review.SellerProduct.Seller.Name,
+		ReviewerName:     review.User.Name,
+		Rating:           review.Rating,
+		Comment:          review.Comment,
+		SellerReply:      review.SellerReply,
+		RepliedAt:        review.RepliedAt,
+		Status:           review.Status,
+		ModerationReason: review.ModerationReason,
+		ModeratedAt:      review.ModeratedAt,
+		CreatedAt:        review.CreatedAt,
+	}
+}
+
+func toReviewDetails(reviews []models.Review) []ReviewDetail {
+	result := make([]ReviewDetail, 0, len(reviews))
+	for _, review := range reviews {
+		result = append(result, toReviewDetail(review))
+	}
+	return result
+}
+
+// reviewQuery - Query ulasan beserta relasi yang dibutuhkan ReviewDetail, terbaru dulu
+func reviewQuery(db *gorm.DB) *gorm.DB {
+	return db.Preload("SellerProduct.Product").Preload("SellerProduct.Seller").Preload("User").
+		Order("reviews.created_at DESC")
+}
+
+// Create - Pembeli mengulas transaksi COMPLETED miliknya sendiri, satu kali per transaksi
+func (s *ReviewService) Create(transactionID string, userID string, input ReviewInput) (ReviewDetail, error) {
+	var review models.Review
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Kunci baris transaksi agar dua request bersamaan tidak sama-sama lolos cek duplikat
+		var transaction models.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("SellerProduct.Product").
+			First(&transaction, "id = ? AND user_id = ?", transactionID, userID).Error; err != nil {
+			return err
+		}
+		if transaction.Status != models.StatusCompleted {
+			return ErrTransactionNotCompleted
+		}
+
+		var count int64
+		if err := tx.Model(&models.Review{}).Where("transaction_id = ?", transaction.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAlreadyReviewed
+		}
+
+		review = models.Review{
+			TransactionID:   transaction.ID,
+			SellerProductID: transaction.SellerProductID,
+			SellerID:        transaction.SellerProduct.SellerID,
+			UserID:          transaction.UserID,
+			Rating:          input.Rating,
+			Comment:         input.Comment,
+			Status:          models.ReviewVisible,
+		}
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+
+		return createNotification(tx, review.SellerID, models.NotificationNewReview,
+			"Ulasan baru",
+			fmt.Sprintf("%s mendapat ulasan bintang %d", transaction.SellerProduct.Product.Name, review.Rating),
+			map[string]interface{}{
+				"review_id":         review.ID,
+				"seller_product_id": review.SellerProductID,
+				"rating":            review.Rating,
+			})
+	})
+	if err != nil {
+		return ReviewDetail{}, err
+	}
+
+	if err := reviewQuery(database.DB).First(&review, "reviews.id = ?", review.ID).Error; err != nil {
+		return ReviewDetail{}, err
+	}
+	return toReviewDetail(review), nil
+}
+
+// GetListingReviews - Ulasan yang tampil untuk satu etalase beserta ringkasan rating
+func (s *ReviewService) GetListingReviews(sellerProductID string) (ListingReviews, error) {
+	var listing models.SellerProduct
+	if err := database.DB.First(&listing, "id = ?", sellerProductID).Error; err != nil {
+		return ListingReviews{}, err
+	}
+
+	var reviews []models.Review
+	if err := reviewQuery(database.DB).
+		Where("seller_product_id = ? AND status = ?", listing.ID, models.ReviewVisible).
+		Find(&reviews).Error; err != nil {
+		return ListingReviews{}, err
+	}
+
+	result := ListingReviews{
+		SellerProductID: listing.ID.String(),
+		RatingBreakdown: map[string]int64{"1": 0, "2": 0, "3": 0, "4": 0, "5": 0},
+		Reviews:         make([]ReviewDetail, 0, len(reviews)),
+	}
+	total := 0
+	for _, review := range reviews {
+		detail := toReviewDetail(review)
+		detail.ModerationReason = ""
+		result.Reviews = append(result.Reviews, detail)
+		result.RatingBreakdown[strconv.Itoa(review.Rating)]++
+		total += review.Rating
+	}
+	result.ReviewCount = int64(len(reviews))
+	if result.ReviewCount > 0 {
+		result.AverageRating = math.Round(float64(total)/float64(result.ReviewCount)*10) / 10
+	}
+	return result, nil
+}
+
+// GetSellerReviews - Semua ulasan untuk etalase milik seller (termasuk yang disembunyikan admin)
+func (s *ReviewService) GetSellerReviews(sellerID string) ([]ReviewDetail, error) {
+	var reviews []models.Review
+	if err := reviewQuery(database.DB).Where("seller_id = ?", sellerID).Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return toReviewDetails(reviews), nil
+}
+
+// Reply - Seller membalas (atau mengubah balasan) ulasan di etalasenya
+func (s *ReviewService) Reply(reviewID string, sellerID string, input ReviewReplyInput) (ReviewDetail, error) {
+	var review models.Review
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("SellerProduct.Product").
+			First(&review, "id = ? AND seller_id = ?", reviewID, sellerID).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&review).Updates(map[string]interface{}{
+			"seller_reply": input.Reply,
+			"replied_at":   now,
+		}).Error; err != nil {
+			return err
+		}
+
+		return createNotification(tx, review.UserID, models.NotificationReviewReply,
+			"Seller membalas ulasan Anda",
+			fmt.Sprintf("Seller membalas ulasan Anda untuk %s", review.SellerProduct.Product.Name),
+			map[string]interface{}{
+				"review_id":         review.ID,
+				"seller_product_id": review.SellerProductID,
+			})
+	})
+	if err != nil {
+		return ReviewDetail{}, err
+	}
+
+	if err := reviewQuery(database.DB).First(&review, "reviews.id = ?", review.ID).Error; err != nil {
+		return ReviewDetail{}, err
+	}
+	return toReviewDetail(review), nil
+}
+
+// GetAll - Semua ulasan untuk moderasi (admin), filter status opsional
+func (s *ReviewService) GetAll(status string) ([]ReviewDetail, error) {
+	query := reviewQuery(database.DB)
+	if status != "" {
+		if status != models.ReviewVisible && status != models.ReviewHidden {
+			return nil, errors.New("invalid status, use VISIBLE or HIDDEN")
+		}
+		query = query.Where("status = ?", status)
+	}
+
+	var reviews []models.Review
+	if err := query.Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return toReviewDetails(reviews), nil
+}
+
+// Moderate - Admin menyembunyikan / menampilkan kembali ulasan.
+// Ulasan HIDDEN tidak tampil di marketplace dan tidak dihitung di rating.
+func (s *ReviewService) Moderate(reviewID string, adminID string, input ReviewModerationInput) (ReviewDetail, error) {
+	var review models.Review
+	if err := database.DB.First(&review, "id = ?", reviewID).Error; err != nil {
+		return ReviewDetail{}, err
+	}
+
+	now := time.Now()
+	if err := database.DB.Model(&review).Updates(map[string]interface{}{
+		"status":            input.Status,
+		"moderation_reason": input.Reason,
+		"moderated_by_id":   parseOptionalUUID(adminID),
+		"moderated_at":      now,
+	}).Error; err != nil {
+		return ReviewDetail{}, err
+	}
+
+	if err := reviewQuery(database.DB).First(&review, "reviews.id = ?", review.ID).Error; err != nil {
+		return ReviewDetail{}, err
+	}
+	return toReviewDetail(review), nil
+}
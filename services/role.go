@@ -0,0 +1,304 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RoleService menangani role & mapping permission role (RBAC)
+type RoleService struct{}
+
+// ErrUnknownPermission - Nama permission tidak dikenal (HTTP 400)
+var ErrUnknownPermission = errors.New("unknown permission")
+
+// ErrRoleNameTaken - Nama role sudah dipakai (HTTP 409)
+var ErrRoleNameTaken = errors.New("role name already exists")
+
+// ErrSystemRole - Role bawaan tidak bisa dihapus / diganti nama (HTTP 409)
+var ErrSystemRole = errors.New("system role cannot be renamed or deleted")
+
+// ErrRoleInUse - Role masih dipakai user (HTTP 409)
+var ErrRoleInUse = errors.New("role is still assigned to users")
+
+// ErrLastRoleManager - Perubahan akan membuat tidak ada user yang bisa mengelola role (HTTP 409)
+var ErrLastRoleManager = errors.New("at least one user must keep the roles.manage permission")
+
+// RoleInput - Input create/update role. Permissions menggantikan seluruh permission role.
+type RoleInput struct {
+	Name        string   `json:"name" binding:"required,max=50"`
+	Description string   `json:"description" binding:"max=255"`
+	Registrable bool     `json:"registrable"` // Boleh dipilih saat registrasi publik
+	Permissions []string `json:"permissions"` // Contoh: ["products.write", "inventory.read"]
+}
+
+// RoleDetail - Role beserta permission dan jumlah user
+type RoleDetail struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Registrable bool     `json:"registrable"`
+	IsSystem    bool     `json:"is_system"`
+	Permissions []string `json:"permissions"`
+	UserCount   int64    `json:"user_count"`
+}
+
+// ListPermissions - Semua permission yang bisa di-assign ke role
+func (s *RoleService) ListPermissions() ([]models.Permission, error) {
+	var permissions []models.Permission
+	err := database.DB.Order("name ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+// ListRoles - Semua role beserta permission & jumlah user
+func (s *RoleService) ListRoles() ([]RoleDetail, error) {
+	var roles []models.Role
+	if err := database.DB.Order("name ASC").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	details := make([]RoleDetail, 0, len(roles))
+	for _, role := range roles {
+		detail, err := roleDetail(database.DB, role)
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, detail)
+	}
+	return details, nil
+}
+
+// GetRole - Detail satu role
+func (s *RoleService) GetRole(id string) (RoleDetail, error) {
+	var role models.Role
+	if err := database.DB.First(&role, "id = ?", id).Error; err != nil {
+		return RoleDetail{}, err
+	}
+	return roleDetail(database.DB, role)
+}
+
+// CreateRole - Role baru dengan permission yang dipilih, dicatat di audit log
+func (s *RoleService) CreateRole(input RoleInput, audit *AuditContext) (RoleDetail, error) {
+	if _, err := audit.actor(); err != nil {
+		return RoleDetail{}, err
+	}
+
+	var detail RoleDetail
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		name := strings.TrimSpace(input.Name)
+		if err := checkRoleName(tx, name, uuid.Nil); err != nil {
+			return err
+		}
+		permissions, err := findPermissions(tx, input.Permissions)
+		if err != nil {
+			return err
+		}
+
+		role := models.Role{Name: name, Description: input.Description, Registrable: input.Registrable}
+		if err := tx.Create(&role).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&role).Association("Permissions").Replace(permissions); err != nil {
+			return err
+		}
+		if detail, err = roleDetail(tx, role); err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditRoleCreated, "role", &role.ID, detail)
+	})
+	return detail, err
+}
+
+// UpdateRole - Ubah data role & ganti seluruh permission-nya, dicatat di audit log (sebelum & sesudah).
+// User dengan role ini mendapat permission baru saat access token berikutnya diterbitkan.
+func (s *RoleService) UpdateRole(id string, input RoleInput, audit *AuditContext) (RoleDetail, error) {
+	if _, err := audit.actor(); err != nil {
+		return RoleDetail{}, err
+	}
+
+	var detail RoleDetail
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var role models.Role
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&role, "id = ?", id).Error; err != nil {
+			return err
+		}
+		before, err := roleDetail(tx, role)
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimSpace(input.Name)
+		if name != role.Name {
+			if role.IsSystem {
+				return ErrSystemRole
+			}
+			if err := checkRoleName(tx, name, role.ID); err != nil {
+				return err
+			}
+		}
+		permissions, err := findPermissions(tx, input.Permissions)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&role).Updates(map[string]interface{}{
+			"name":        name,
+			"description": input.Description,
+			"registrable": input.Registrable,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&role).Association("Permissions").Replace(permissions); err != nil {
+			return err
+		}
+		if err := ensureRoleManagerExists(tx); err != nil {
+			return err
+		}
+
+		if detail, err = roleDetail(tx, role); err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditRoleUpdated, "role", &role.ID, map[string]interface{}{
+			"before": before,
+			"after":  detail,
+		})
+	})
+	return detail, err
+}
+
+// DeleteRole - Hapus permanen role non-system yang tidak dipakai user, dicatat di audit log
+func (s *RoleService) DeleteRole(id string, audit *AuditContext) error {
+	if _, err := audit.actor(); err != nil {
+		return err
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var role models.Role
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&role, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if role.IsSystem {
+			return ErrSystemRole
+		}
+
+		// Termasuk user yang sudah di-soft delete (masih mereferensikan role_id)
+		var users int64
+		if err := tx.Unscoped().Model(&models.User{}).Where("role_id = ?", role.ID).Count(&users).Error; err != nil {
+			return err
+		}
+		if users > 0 {
+			return fmt.Errorf("%w: %d user(s)", ErrRoleInUse, users)
+		}
+
+		detail, err := roleDetail(tx, role)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&role).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+		// Hard delete agar nama role (unique) bisa dipakai lagi
+		if err := tx.Unscoped().Delete(&role).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditRoleDeleted, "role", &role.ID, detail)
+	})
+}
+
+// roleDetail - Susun RoleDetail dari role (permission diurutkan)
+func roleDetail(tx *gorm.DB, role models.Role) (RoleDetail, error) {
+	permissions, err := rolePermissionNames(tx, role.ID)
+	if err != nil {
+		return RoleDetail{}, err
+	}
+	var users int64
+	if err := tx.Model(&models.User{}).Where("role_id = ?", role.ID).Count(&users).Error; err != nil {
+		return RoleDetail{}, err
+	}
+	return RoleDetail{
+		ID:          role.ID.String(),
+		Name:        role.Name,
+		Description: role.Description,
+		Registrable: role.Registrable,
+		IsSystem:    role.IsSystem,
+		Permissions: permissions,
+		UserCount:   users,
+	}, nil
+}
+
+// checkRoleName - Nama role wajib diisi dan belum dipakai role lain (case-insensitive)
+func checkRoleName(tx *gorm.DB, name string, exceptID uuid.UUID) error {
+	if name == "" {
+		return errors.New("role name is required")
+	}
+	var count int64
+	if err := tx.Unscoped().Model(&models.Role{}).
+		Where("LOWER(name) = LOWER(?) AND id <> ?", name, exceptID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRoleNameTaken
+	}
+	return nil
+}
+
+// findPermissions - Ambil permission berdasarkan nama, nama yang tidak dikenal = ErrUnknownPermission
+func findPermissions(tx *gorm.DB, names []string) ([]models.Permission, error) {
+	unique := make(map[string]bool, len(names))
+	for _, name := range names {
+		unique[strings.TrimSpace(name)] = true
+	}
+	if len(unique) == 0 {
+		return []models.Permission{}, nil
+	}
+	wanted := make([]string, 0, len(unique))
+	for name := range unique {
+		wanted = append(wanted, name)
+	}
+
+	var permissions []models.Permission
+	if err := tx.Where("name IN ?", wanted).Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	if len(permissions) != len(wanted) {
+		found := make(map[string]bool, len(permissions))
+		for _, permission := range permissions {
+			found[permission.Name] = true
+		}
+		var unknown []string
+		for _, name := range wanted {
+			if !found[name] {
+				unknown = append(unknown, name)
+			}
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPermission, strings.Join(unknown, ", "))
+	}
+	return permissions, nil
+}
+
+// ensureRoleManagerExists - Minimal satu user aktif harus tetap bisa mengelola role (mencegah terkunci dari RBAC)
+func ensureRoleManagerExists(tx *gorm.DB) error {
+	var count int64
+	err := tx.Model(&models.User{}).
+		Joins("JOIN role_permissions rp ON rp.role_id = users.role_id").
+		Joins("JOIN permissions ON permissions.id = rp.permission_id").
+		Where("permissions.name = ?", models.PermRolesManage).
+		Where("users.id NOT IN ("+inactiveUsersSQL+")", time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrLastRoleManager
+	}
+	return nil
+}
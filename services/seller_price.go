@@ -0,0 +1,416 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SellerPriceService menangani histori harga jual & jadwal perubahan harga etalase seller
+type SellerPriceService struct{}
+
+// ErrPriceScheduleOverlap - Jadwal bertabrakan dengan jadwal lain yang masih PENDING / ACTIVE (HTTP 409)
+var ErrPriceScheduleOverlap = errors.New("price schedule overlaps an existing schedule")
+
+// ErrInvalidPriceScheduleStatus - Jadwal sudah selesai / dibatalkan (HTTP 409)
+var ErrInvalidPriceScheduleStatus = errors.New("invalid price schedule status")
+
+// changeSellingPrice - Semua perubahan selling_price lewat sini agar histori selalu tercatat
+// dan pembeli yang menyimpan etalase di wishlist dikabari saat harga turun.
+// Wajib dipanggil di dalam DB transaction, listing sebaiknya sudah di-lock.
+func changeSellingPrice(tx *gorm.DB, listing *models.SellerProduct, newPrice float64, source string, actorID *uuid.UUID, scheduleID *uuid.UUID) error {
+	oldPrice := listing.SellingPrice
+	if oldPrice == newPrice {
+		return nil
+	}
+	if err := tx.Model(listing).Update("selling_price", newPrice).Error; err != nil {
+		return err
+	}
+	if err := tx.Create(&models.SellerProductPriceHistory{
+		SellerProductID: listing.ID,
+		OldPrice:        oldPrice,
+		NewPrice:        newPrice,
+		Source:          source,
+		ScheduleID:      scheduleID,
+		ChangedByID:     actorID,
+	}).Error; err != nil {
+		return err
+	}
+	return notifyWishlistPriceDrop(tx, *listing, oldPrice, newPrice)
+}
+
+// recordInitialPrice - Histori pertama saat produk dipajang (old_price = 0)
+func recordInitialPrice(tx *gorm.DB, listing models.SellerProduct, actorID *uuid.UUID) error {
+	return tx.Create(&models.SellerProductPriceHistory{
+		SellerProductID: listing.ID,
+		NewPrice:        listing.SellingPrice,
+		Source:          models.PriceSourceInitial,
+		ChangedByID:     actorID,
+	}).Error
+}
+
+// findSellerListing - Etalase milik seller (dengan Product), optional di-lock
+func findSellerListing(tx *gorm.DB, sellerProductID string, sellerID string, lock bool) (models.SellerProduct, error) {
+	var listing models.SellerProduct
+	query := tx.Preload("Product")
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	if err := query.First(&listing, "id = ? AND seller_id = ?", sellerProductID, sellerID).Error; err != nil {
+		return listing, errors.New("seller product not found or unauthorized")
+	}
+	return listing, nil
+}
+
+// PriceHistoryItem - Satu baris histori harga jual
+type PriceHistoryItem struct {
+	ID          string    `json:"id"`
+	OldPrice    float64   `json:"old_price"`
+	NewPrice    float64   `json:"new_price"`
+	Source      string    `json:"source"`
+	ScheduleID  *string   `json:"schedule_id"`
+	ChangedByID *string   `json:"changed_by_id"`
+	ChangedAt   time.Time `json:"changed_at"`
+}
+
+// GetPriceHistory - Histori harga jual etalase milik seller (terbaru dulu)
+func (s *SellerPriceService) GetPriceHistory(sellerProductID string, sellerID string, limit int) ([]PriceHistoryItem, error) {
+	listing, err := findSellerListing(database.DB, sellerProductID, sellerID, false)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var histories []models.SellerProductPriceHistory
+	if err := database.DB.Where("seller_product_id = ?", listing.ID).
+		Order("created_at DESC").Limit(limit).Find(&histories).Error; err != nil {
+		return nil, err
+	}
+
+	result := []PriceHistoryItem{}
+	for _, h := range histories {
+		item := PriceHistoryItem{
+			ID:        h.ID.String(),
+			OldPrice:  h.OldPrice,
+			NewPrice:  h.NewPrice,
+			Source:    h.Source,
+			ChangedAt: h.CreatedAt,
+		}
+		if h.ScheduleID != nil {
+			id := h.ScheduleID.String()
+			item.ScheduleID = &id
+		}
+		if h.ChangedByID != nil {
+			id := h.ChangedByID.String()
+			item.ChangedByID = &id
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// PriceScheduleInput - Jadwal harga jual. Tanpa end_at harga baru berlaku permanen,
+// dengan end_at harga dikembalikan ke harga sebelumnya saat jadwal berakhir.
+type PriceScheduleInput struct {
+	SellingPrice float64    `json:"selling_price" binding:"required,min=1"`
+	StartAt      time.Time  `json:"start_at" binding:"required" example:"2025-02-01T00:00:00+07:00"`
+	EndAt        *time.Time `json:"end_at" example:"2025-02-07T23:59:59+07:00"`
+}
+
+// GetPriceSchedules - Semua jadwal harga etalase milik seller (start_at terbaru dulu)
+func (s *SellerPriceService) GetPriceSchedules(sellerProductID string, sellerID string) ([]models.SellerProductPriceSchedule, error) {
+	listing, err := findSellerListing(database.DB, sellerProductID, sellerID, false)
+	if err != nil {
+		return nil, err
+	}
+	var schedules []models.SellerProductPriceSchedule
+	err = database.DB.Where("seller_product_id = ?", listing.ID).Order("start_at DESC").Find(&schedules).Error
+	return schedules, err
+}
+
+// CreatePriceSchedule - Jadwalkan perubahan harga jual
+// Alur: Validasi waktu -> Validasi harga >= harga modal -> Cek bentrok jadwal lain -> Simpan PENDING
+func (s *SellerPriceService) CreatePriceSchedule(sellerProductID string, sellerID string, input PriceScheduleInput) (models.SellerProductPriceSchedule, error) {
+	schedule := models.SellerProductPriceSchedule{}
+	if !input.StartAt.After(time.Now()) {
+		return schedule, errors.New("start_at must be in the future")
+	}
+	if input.EndAt != nil && !input.EndAt.After(input.StartAt) {
+		return schedule, errors.New("end_at must be after start_at")
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock etalase agar dua jadwal yang bentrok tidak lolos bersamaan
+		listing, err := findSellerListing(tx, sellerProductID, sellerID, true)
+		if err != nil {
+			return err
+		}
+		if input.SellingPrice < listing.Product.Price {
+			return errors.New("selling price cannot be lower than base price")
+		}
+
+		var open []models.SellerProductPriceSchedule
+		if err := tx.Where("seller_product_id = ? AND status IN ?", listing.ID,
+			[]string{models.PriceScheduleStatusPending, models.PriceScheduleStatusActive}).
+			Find(&open).Error; err != nil {
+			return err
+		}
+		for _, other := range open {
+			if priceSchedulesOverlap(input.StartAt, input.EndAt, other.StartAt, other.EndAt) {
+				return fmt.Errorf("%w: schedule %s (%s)", ErrPriceScheduleOverlap, other.ID, other.Status)
+			}
+		}
+
+		sellerUUID, _ := uuid.Parse(sellerID)
+		schedule = models.SellerProductPriceSchedule{
+			SellerProductID: listing.ID,
+			SellingPrice:    input.SellingPrice,
+			StartAt:         input.StartAt,
+			EndAt:           input.EndAt,
+			Status:          models.PriceScheduleStatusPending,
+			CreatedByID:     sellerUUID,
+		}
+		return tx.Create(&schedule).Error
+	})
+	return schedule, err
+}
+
+// priceSchedulesOverlap - Jadwal permanen (tanpa end) dianggap satu titik waktu di start_at,
+// jadwal sementara berlaku [start, end). Dua jadwal sementara boleh bersambung.
+func priceSchedulesOverlap(aStart time.Time, aEnd *time.Time, bStart time.Time, bEnd *time.Time) bool {
+	switch {
+	case aEnd == nil && bEnd == nil:
+		return aStart.Equal(bStart)
+	case aEnd == nil:
+		return !aStart.Before(bStart) && aStart.Before(*bEnd)
+	case bEnd == nil:
+		return !bStart.Before(aStart) && bStart.Before(*aEnd)
+	}
+	return aStart.Before(*bEnd) && bStart.Before(*aEnd)
+}
+
+// CancelPriceSchedule - Batalkan jadwal. Jadwal PENDING cukup dibatalkan,
+// jadwal ACTIVE diakhiri sekarang (harga dikembalikan seperti saat end_at).
+func (s *SellerPriceService) CancelPriceSchedule(sellerProductID string, scheduleID string, sellerID string) (models.SellerProductPriceSchedule, error) {
+	var schedule models.SellerProductPriceSchedule
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		listing, err := findSellerListing(tx, sellerProductID, sellerID, true)
+		if err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&schedule, "id = ? AND seller_product_id = ?", scheduleID, listing.ID).Error; err != nil {
+			return err
+		}
+
+		switch schedule.Status {
+		case models.PriceScheduleStatusPending:
+			return tx.Model(&schedule).Update("status", models.PriceScheduleStatusCancelled).Error
+		case models.PriceScheduleStatusActive:
+			actorID := parseOptionalUUID(sellerID)
+			return endPriceSchedule(tx, &schedule, &listing, models.PriceScheduleStatusCancelled, actorID)
+		}
+		return fmt.Errorf("%w: only PENDING or ACTIVE schedules can be cancelled (current: %s)", ErrInvalidPriceScheduleStatus, schedule.Status)
+	})
+	return schedule, err
+}
+
+// PriceScheduleRunResult - Ringkasan satu kali eksekusi scheduler harga
+type PriceScheduleRunResult struct {
+	Started int       `json:"started"`
+	Ended   int       `json:"ended"`
+	Failed  int       `json:"failed"`
+	RanAt   time.Time `json:"ran_at"`
+}
+
+// ApplyDueSchedules - Dipanggil scheduler: akhiri jadwal ACTIVE yang lewat end_at,
+// lalu terapkan jadwal PENDING yang sudah lewat start_at. Setiap jadwal diproses di
+// transaksi sendiri dengan SKIP LOCKED, jadi aman dijalankan di beberapa instance.
+func (s *SellerPriceService) ApplyDueSchedules(now time.Time) (PriceScheduleRunResult, error) {
+	result := PriceScheduleRunResult{RanAt: now}
+	var firstErr error
+	keep := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	var endingIDs []uuid.UUID
+	database.DB.Model(&models.SellerProductPriceSchedule{}).
+		Where("status = ? AND end_at <= ?", models.PriceScheduleStatusActive, now).
+		Order("end_at").Pluck("id", &endingIDs)
+	for _, id := range endingIDs {
+		ended, err := endDueSchedule(id, now)
+		keep(err)
+		if ended {
+			result.Ended++
+		}
+	}
+
+	var startingIDs []uuid.UUID
+	database.DB.Model(&models.SellerProductPriceSchedule{}).
+		Where("status = ? AND start_at <= ?", models.PriceScheduleStatusPending, now).
+		Order("start_at").Pluck("id", &startingIDs)
+	for _, id := range startingIDs {
+		status, err := startDueSchedule(id, now)
+		keep(err)
+		switch status {
+		case models.PriceScheduleStatusActive, models.PriceScheduleStatusCompleted:
+			result.Started++
+		case models.PriceScheduleStatusFailed:
+			result.Failed++
+		}
+	}
+	return result, firstErr
+}
+
+// lockDueSchedule - Lock jadwal dengan status tertentu, dilewati jika sedang diproses instance lain
+func lockDueSchedule(tx *gorm.DB, id uuid.UUID, status string) (models.SellerProductPriceSchedule, bool, error) {
+	var schedule models.SellerProductPriceSchedule
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("id = ? AND status = ?", id, status).Limit(1).Find(&schedule).Error
+	return schedule, schedule.ID != uuid.Nil, err
+}
+
+func endDueSchedule(id uuid.UUID, now time.Time) (bool, error) {
+	ended := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		schedule, ok, err := lockDueSchedule(tx, id, models.PriceScheduleStatusActive)
+		if err != nil || !ok {
+			return err
+		}
+		var listing models.SellerProduct
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Product").
+			First(&listing, "id = ?", schedule.SellerProductID).Error; err != nil {
+			// Etalase sudah dihapus, tidak ada harga yang perlu dikembalikan
+			return tx.Model(&schedule).Updates(map[string]interface{}{
+				"status":      models.PriceScheduleStatusCompleted,
+				"reverted_at": now,
+			}).Error
+		}
+		ended = true
+		return endPriceSchedule(tx, &schedule, &listing, models.PriceScheduleStatusCompleted, nil)
+	})
+	return ended, err
+}
+
+// endPriceSchedule - Kembalikan harga ke PreviousPrice, kecuali seller sudah mengubah
+// harga secara manual selama jadwal berlaku. Harga tidak pernah dikembalikan di bawah harga modal.
+func endPriceSchedule(tx *gorm.DB, schedule *models.SellerProductPriceSchedule, listing *models.SellerProduct, finalStatus string, actorID *uuid.UUID) error {
+	if schedule.PreviousPrice != nil && listing.SellingPrice == schedule.SellingPrice {
+		target := *schedule.PreviousPrice
+		if target < listing.Product.Price {
+			target = listing.Product.Price
+		}
+		if err := changeSellingPrice(tx, listing, target, models.PriceSourceScheduleEnd, actorID, &schedule.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Model(schedule).Updates(map[string]interface{}{
+		"status":      finalStatus,
+		"reverted_at": time.Now(),
+	}).Error
+}
+
+func startDueSchedule(id uuid.UUID, now time.Time) (string, error) {
+	status := ""
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		schedule, ok, err := lockDueSchedule(tx, id, models.PriceScheduleStatusPending)
+		if err != nil || !ok {
+			return err
+		}
+
+		var listing models.SellerProduct
+		reason := ""
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Product").
+			First(&listing, "id = ?", schedule.SellerProductID).Error; err != nil {
+			reason = "seller product no longer exists"
+		} else if schedule.EndAt != nil && !schedule.EndAt.After(now) {
+			reason = "schedule window ended before it could be applied"
+		} else if schedule.SellingPrice < listing.Product.Price {
+			reason = fmt.Sprintf("selling price %.2f is below the current base price %.2f", schedule.SellingPrice, listing.Product.Price)
+		}
+
+		if reason != "" {
+			status = models.PriceScheduleStatusFailed
+			if err := tx.Model(&schedule).Updates(map[string]interface{}{
+				"status":         status,
+				"failure_reason": reason,
+			}).Error; err != nil {
+				return err
+			}
+			if listing.ID == uuid.Nil {
+				return nil
+			}
+			return createNotification(tx, listing.SellerID, models.NotificationPriceScheduleFailed,
+				"Jadwal harga "+listing.Product.Name+" gagal diterapkan",
+				fmt.Sprintf("Jadwal harga %.2f mulai %s tidak diterapkan: %s.", schedule.SellingPrice, schedule.StartAt.Format(time.RFC3339), reason),
+				map[string]interface{}{
+					"schedule_id":       schedule.ID.String(),
+					"seller_product_id": listing.ID.String(),
+					"selling_price":     schedule.SellingPrice,
+					"reason":            reason,
+				})
+		}
+
+		previous := listing.SellingPrice
+		if err := changeSellingPrice(tx, &listing, schedule.SellingPrice, models.PriceSourceSchedule, nil, &schedule.ID); err != nil {
+			return err
+		}
+		status = models.PriceScheduleStatusCompleted
+		if schedule.EndAt != nil {
+			status = models.PriceScheduleStatusActive
+		}
+		return tx.Model(&schedule).Updates(map[string]interface{}{
+			"status":         status,
+			"previous_price": previous,
+			"applied_at":     now,
+		}).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// priceWasWindow - Harga coret hanya ditampilkan untuk penurunan harga dalam
+// PRICE_WAS_WINDOW_DAYS hari terakhir (default 30)
+func priceWasWindow() time.Duration {
+	return time.Duration(utils.EnvInt("PRICE_WAS_WINDOW_DAYS", 30)) * 24 * time.Hour
+}
+
+// getWasPrices - Harga coret ("was Rp X, now Rp Y") per etalase: harga sebelum perubahan
+// terakhir, jika perubahan terakhir itu menurunkan harga dan masih dalam window.
+func getWasPrices(listingIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
+	result := make(map[uuid.UUID]float64)
+	if len(listingIDs) == 0 {
+		return result, nil
+	}
+
+	var latest []models.SellerProductPriceHistory
+	err := database.DB.Raw(`
+		SELECT DISTINCT ON (seller_product_id) seller_product_id, old_price, new_price, source, created_at
+		FROM seller_product_price_histories
+		WHERE seller_product_id IN ? AND deleted_at IS NULL
+		ORDER BY seller_product_id, created_at DESC`, listingIDs).Scan(&latest).Error
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-priceWasWindow())
+	for _, h := range latest {
+		if h.Source != models.PriceSourceInitial && h.OldPrice > h.NewPrice && h.CreatedAt.After(since) {
+			result[h.SellerProductID] = h.OldPrice
+		}
+	}
+	return result, nil
+}
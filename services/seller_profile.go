@@ -0,0 +1,382 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SellerProfileService menangani profil toko seller dan halaman toko publik
+type SellerProfileService struct{}
+
+// ErrSlugTaken - Slug toko sudah dipakai seller lain (HTTP 409)
+var ErrSlugTaken = errors.New("shop slug is already taken")
+
+// ErrSellerOnVacation - Seller sedang libur, order baru ditolak (HTTP 409)
+var ErrSellerOnVacation = errors.New("seller is on vacation")
+
+// Urutan hari untuk validasi & tampilan jam operasional
+var operatingDays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// OperatingHourInput - Jam buka satu hari, open/close format HH:MM
+type OperatingHourInput struct {
+	Day    string `json:"day" binding:"required,oneof=monday tuesday wednesday thursday friday saturday sunday" example:"monday"`
+	Open   string `json:"open" example:"09:00"`
+	Close  string `json:"close" example:"17:00"`
+	Closed bool   `json:"closed"`
+}
+
+// UpdateSellerProfileInput - Field yang dikirim saja yang diubah
+type UpdateSellerProfileInput struct {
+	ShopName        *string               `json:"shop_name" binding:"omitempty,min=3,max=100" example:"Toko Elektronik Jaya"`
+	Slug            *string               `json:"slug" binding:"omitempty,max=120" example:"toko-elektronik-jaya"`
+	Description     *string               `json:"description" example:"Pusat gadget original bergaransi resmi"`
+	LogoURL         *string               `json:"logo_url" binding:"omitempty,url,max=255" example:"https://cdn.example.com/logo.png"`
+	City            *string               `json:"city" binding:"omitempty,max=100" example:"Jakarta"`
+	OperatingHours  *[]OperatingHourInput `json:"operating_hours" binding:"omitempty,dive"`
+	VacationMode    *bool                 `json:"vacation_mode"`
+	VacationMessage *string               `json:"vacation_message" binding:"omitempty,max=255" example:"Libur lebaran, kembali 15 April"`
+	VacationUntil   *time.Time            `json:"vacation_until" example:"2025-04-15T00:00:00+07:00"`
+}
+
+// SellerProfileDetail - Response profil toko
+type SellerProfileDetail struct {
+	SellerID        string                `json:"seller_id"`
+	ShopName        string                `json:"shop_name"`
+	Slug            string                `json:"slug"`
+	Description     string                `json:"description"`
+	LogoURL         string                `json:"logo_url"`
+	City            string                `json:"city"`
+	OperatingHours  models.OperatingHours `json:"operating_hours"`
+	IsOpenNow       bool                  `json:"is_open_now"`
+	VacationMode    bool                  `json:"vacation_mode"` // Setting seller
+	OnVacation      bool                  `json:"on_vacation"`   // Mode libur sedang berlaku (vacation_until belum lewat)
+	VacationMessage string                `json:"vacation_message"`
+	VacationUntil   *time.Time            `json:"vacation_until"`
+	JoinedAt        time.Time             `json:"joined_at"`
+}
+
+// ShopPage - Halaman toko publik: profil, rating, dan etalase aktif (paginated)
+type ShopPage struct {
+	Profile        SellerProfileDetail `json:"profile"`
+	AverageRating  float64             `json:"average_rating"`
+	ReviewCount    int64               `json:"review_count"`
+	CompletedSales int64               `json:"completed_sales"`
+	Listings       []MarketplaceItem   `json:"listings"`
+	Pagination     Pagination          `json:"pagination"`
+}
+
+// Pagination - Info halaman untuk response list
+type Pagination struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+}
+
+func toSellerProfileDetail(profile models.SellerProfile, now time.Time) SellerProfileDetail {
+	hours := profile.OperatingHours
+	if hours == nil {
+		hours = models.OperatingHours{}
+	}
+	onVacation := profile.OnVacation(now)
+	return SellerProfileDetail{
+		SellerID:        profile.UserID.String(),
+		ShopName:        profile.ShopName,
+		Slug:            profile.Slug,
+		Description:     profile.Description,
+		LogoURL:         profile.LogoURL,
+		City:            profile.City,
+		OperatingHours:  hours,
+		IsOpenNow:       !onVacation && profile.OperatingHours.IsOpenAt(now),
+		VacationMode:    profile.VacationMode,
+		OnVacation:      onVacation,
+		VacationMessage: profile.VacationMessage,
+		VacationUntil:   profile.VacationUntil,
+		JoinedAt:        profile.User.CreatedAt,
+	}
+}
+
+// uniqueShopSlug - Slug dari nama toko yang belum dipakai seller lain
+func uniqueShopSlug(tx *gorm.DB, shopName string, userID uuid.UUID) string {
+	base := utils.Slugify(shopName)
+	if base == "" {
+		base = "toko"
+	}
+	return utils.UniqueSlug(base, func(slug string) bool {
+		var count int64
+		tx.Model(&models.SellerProfile{}).Where("slug = ? AND user_id <> ?", slug, userID).Count(&count)
+		return count > 0
+	})
+}
+
+// ensureSellerProfile - Profil toko seller, dibuat dari nama user jika belum ada
+func ensureSellerProfile(tx *gorm.DB, user models.User) (models.SellerProfile, error) {
+	var profile models.SellerProfile
+	if err := tx.Where("user_id = ?", user.ID).Limit(1).Find(&profile).Error; err != nil {
+		return profile, err
+	}
+	if profile.ID != uuid.Nil {
+		return profile, nil
+	}
+
+	profile = models.SellerProfile{
+		UserID:   user.ID,
+		ShopName: user.Name,
+		Slug:     uniqueShopSlug(tx, user.Name, user.ID),
+		City:     user.City,
+	}
+	if err := tx.Create(&profile).Error; err != nil {
+		return profile, err
+	}
+	return profile, nil
+}
+
+// getSellerProfiles - Profil toko per seller (seller tanpa profil tidak ada di map)
+func getSellerProfiles(sellerIDs []uuid.UUID) (map[uuid.UUID]models.SellerProfile, error) {
+	result := make(map[uuid.UUID]models.SellerProfile)
+	if len(sellerIDs) == 0 {
+		return result, nil
+	}
+	var profiles []models.SellerProfile
+	if err := database.DB.Where("user_id IN ?", sellerIDs).Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	for _, profile := range profiles {
+		result[profile.UserID] = profile
+	}
+	return result, nil
+}
+
+// excludeUnavailableSellers - Sembunyikan etalase seller yang sedang libur atau akunnya
+// ditangguhkan / nonaktif (query atas seller_products)
+func excludeUnavailableSellers(query *gorm.DB, now time.Time) *gorm.DB {
+	return query.Where(`seller_products.seller_id NOT IN (SELECT user_id FROM seller_profiles
+		WHERE vacation_mode = ? AND (vacation_until IS NULL OR vacation_until > ?) AND deleted_at IS NULL)`, true, now).
+		Where("seller_products.seller_id NOT IN ("+inactiveUsersSQL+")", now)
+}
+
+// checkSellerAvailable - Tolak order jika akun seller ditangguhkan / nonaktif atau seller sedang libur
+func checkSellerAvailable(tx *gorm.DB, sellerID uuid.UUID) error {
+	var seller models.User
+	if err := tx.Select("id", "status", "status_reason", "suspended_until").First(&seller, "id = ?", sellerID).Error; err != nil {
+		return err
+	}
+	if accountStatusError(seller, time.Now()) != nil {
+		return ErrSellerUnavailable
+	}
+
+	var profile models.SellerProfile
+	if err := tx.Where("user_id = ?", sellerID).Limit(1).Find(&profile).Error; err != nil {
+		return err
+	}
+	if profile.OnVacation(time.Now()) {
+		message := profile.ShopName + " sedang libur"
+		if profile.VacationUntil != nil {
+			message += " sampai " + profile.VacationUntil.Format("2006-01-02 15:04")
+		}
+		return fmt.Errorf("%w: %s", ErrSellerOnVacation, message)
+	}
+	return nil
+}
+
+// validateOperatingHours - Hari unik, jam HH:MM, open < close untuk hari yang buka
+func validateOperatingHours(input []OperatingHourInput) (models.OperatingHours, error) {
+	byDay := make(map[string]models.OperatingHour)
+	for _, in := range input {
+		if _, exists := byDay[in.Day]; exists {
+			return nil, fmt.Errorf("operating_hours: duplicate day %s", in.Day)
+		}
+		hour := models.OperatingHour{Day: in.Day, Closed: in.Closed}
+		if !in.Closed {
+			opensAt, err := time.Parse("15:04", in.Open)
+			if err != nil {
+				return nil, fmt.Errorf("operating_hours %s: open must be HH:MM", in.Day)
+			}
+			closesAt, err := time.Parse("15:04", in.Close)
+			if err != nil {
+				return nil, fmt.Errorf("operating_hours %s: close must be HH:MM", in.Day)
+			}
+			if !opensAt.Before(closesAt) {
+				return nil, fmt.Errorf("operating_hours %s: open must be before close", in.Day)
+			}
+			hour.Open = opensAt.Format("15:04")
+			hour.Close = closesAt.Format("15:04")
+		}
+		byDay[in.Day] = hour
+	}
+
+	hours := models.OperatingHours{}
+	for _, day := range operatingDays {
+		if hour, ok := byDay[day]; ok {
+			hours = append(hours, hour)
+		}
+	}
+	return hours, nil
+}
+
+// GetMyProfile - Profil toko seller yang login (dibuat otomatis jika belum ada)
+func (s *SellerProfileService) GetMyProfile(sellerID string) (SellerProfileDetail, error) {
+	var user models.User
+	if err := database.DB.First(&user, "id = ?", sellerID).Error; err != nil {
+		return SellerProfileDetail{}, err
+	}
+	profile, err := ensureSellerProfile(database.DB, user)
+	if err != nil {
+		return SellerProfileDetail{}, err
+	}
+	profile.User = user
+	return toSellerProfileDetail(profile, time.Now()), nil
+}
+
+// UpdateMyProfile - Ubah profil toko, termasuk jam operasional & mode libur
+func (s *SellerProfileService) UpdateMyProfile(sellerID string, input UpdateSellerProfileInput) (SellerProfileDetail, error) {
+	var user models.User
+	if err := database.DB.First(&user, "id = ?", sellerID).Error; err != nil {
+		return SellerProfileDetail{}, err
+	}
+
+	var profile models.SellerProfile
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if profile, err = ensureSellerProfile(tx, user); err != nil {
+			return err
+		}
+
+		updates := make(map[string]interface{})
+		if input.ShopName != nil {
+			updates["shop_name"] = strings.TrimSpace(*input.ShopName)
+		}
+		if input.Slug != nil {
+			slug := strings.ToLower(strings.TrimSpace(*input.Slug))
+			if !utils.ValidSlug(slug) {
+				return errors.New("slug may only contain lowercase letters, digits and single dashes")
+			}
+			var count int64
+			if err := tx.Model(&models.SellerProfile{}).Where("slug = ? AND user_id <> ?", slug, user.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("%w: %s", ErrSlugTaken, slug)
+			}
+			updates["slug"] = slug
+		}
+		if input.Description != nil {
+			updates["description"] = *input.Description
+		}
+		if input.LogoURL != nil {
+			updates["logo_url"] = *input.LogoURL
+		}
+		if input.City != nil {
+			updates["city"] = *input.City
+		}
+		if input.OperatingHours != nil {
+			hours, err := validateOperatingHours(*input.OperatingHours)
+			if err != nil {
+				return err
+			}
+			profile.OperatingHours = hours
+			if err := tx.Model(&profile).Select("operating_hours").Updates(&profile).Error; err != nil {
+				return err
+			}
+		}
+		if input.VacationMode != nil {
+			updates["vacation_mode"] = *input.VacationMode
+		}
+		if input.VacationMessage != nil {
+			updates["vacation_message"] = *input.VacationMessage
+		}
+		if input.VacationUntil != nil {
+			if !input.VacationUntil.After(time.Now()) {
+				return errors.New("vacation_until must be in the future")
+			}
+			updates["vacation_until"] = *input.VacationUntil
+		} else if input.VacationMode != nil && !*input.VacationMode {
+			updates["vacation_until"] = nil
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Model(&profile).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return tx.First(&profile, "id = ?", profile.ID).Error
+	})
+	if err != nil {
+		return SellerProfileDetail{}, err
+	}
+	profile.User = user
+	return toSellerProfileDetail(profile, time.Now()), nil
+}
+
+// GetShop - Halaman toko publik berdasarkan slug. Saat seller libur, etalase tidak ditampilkan.
+// Seller yang ditangguhkan / nonaktif = 404.
+func (s *SellerProfileService) GetShop(slug string, page int, limit int) (ShopPage, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	var profile models.SellerProfile
+	if err := database.DB.Preload("User").First(&profile, "slug = ?", slug).Error; err != nil {
+		return ShopPage{}, err
+	}
+
+	// Toko seller yang ditangguhkan / nonaktif tidak tampil sama sekali
+	now := time.Now()
+	if accountStatusError(profile.User, now) != nil {
+		return ShopPage{}, gorm.ErrRecordNotFound
+	}
+	result := ShopPage{
+		Profile:    toSellerProfileDetail(profile, now),
+		Listings:   []MarketplaceItem{},
+		Pagination: Pagination{Page: page, Limit: limit},
+	}
+
+	scores, err := getSellerScores([]uuid.UUID{profile.UserID})
+	if err != nil {
+		return result, err
+	}
+	score := scores[profile.UserID]
+	result.AverageRating = score.AverageRating
+	result.ReviewCount = score.ReviewCount
+	result.CompletedSales = score.CompletedSales
+
+	if result.Profile.OnVacation {
+		return result, nil
+	}
+
+	query := database.DB.Model(&models.SellerProduct{}).
+		Joins("JOIN products ON seller_products.product_id = products.id").
+		Where("seller_products.seller_id = ? AND seller_products.is_active = ? AND products.stock > 0", profile.UserID, true).
+		Session(&gorm.Session{})
+	if err := query.Count(&result.Pagination.Total).Error; err != nil {
+		return result, err
+	}
+	result.Pagination.TotalPages = int((result.Pagination.Total + int64(limit) - 1) / int64(limit))
+
+	var listings []models.SellerProduct
+	if err := query.Preload("Product.ProductType").Preload("Seller").
+		Order("seller_products.created_at DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&listings).Error; err != nil {
+		return result, err
+	}
+	items, err := toMarketplaceItems(listings)
+	if err != nil {
+		return result, err
+	}
+	if items != nil {
+		result.Listings = items
+	}
+	return result, nil
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// ErrUnknownSigningKey - kid di header JWT tidak dikenal atau kuncinya sudah kadaluarsa (HTTP 401)
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// signingKeyLockID - Key pg_advisory_xact_lock agar rotasi dari beberapa instance tidak membuat kunci ganda
+const signingKeyLockID = 460046
+
+// verificationKey - Public key untuk verifikasi satu kid
+type verificationKey struct {
+	Algorithm string
+	Public    crypto.PublicKey
+	ExpiresAt *time.Time
+}
+
+// keyring - Snapshot kunci dari tabel signing_keys: satu kunci aktif untuk sign + semua kunci verifikasi
+type keyring struct {
+	SignerKID string
+	SignerAlg string
+	Signer    crypto.Signer
+	Keys      map[string]verificationKey
+	KIDs      []string // Urutan kunci, terbaru dulu
+	LoadedAt  time.Time
+}
+
+var (
+	keyringMu       sync.RWMutex
+	cachedKeyring   *keyring
+	lastForceReload time.Time
+)
+
+// jwtAlgorithm - Algoritma tanda tangan access token (JWT_ALGORITHM: RS256 default, EdDSA, HS256 legacy)
+func jwtAlgorithm() string {
+	switch alg := utils.EnvString("JWT_ALGORITHM", models.SigningAlgRS256); alg {
+	case models.SigningAlgEdDSA, models.SigningAlgHS256:
+		return alg
+	default:
+		return models.SigningAlgRS256
+	}
+}
+
+// jwtIssuer - Claim "iss" (JWT_ISSUER, kosong = tidak dipasang & tidak dicek)
+func jwtIssuer() string {
+	return utils.EnvString("JWT_ISSUER", "")
+}
+
+// signingKeyRotationInterval - Umur kunci aktif sebelum diganti (JWT_KEY_ROTATION_INTERVAL, default 30 hari)
+func signingKeyRotationInterval() time.Duration {
+	return utils.EnvDuration("JWT_KEY_ROTATION_INTERVAL", 30*24*time.Hour)
+}
+
+// retiredKeyLifetime - Kunci yang di-rotate tetap diterima selama umur access token + 5 menit toleransi jam
+func retiredKeyLifetime() time.Duration {
+	return accessTokenTTL() + 5*time.Minute
+}
+
+// loadKeyring - Kunci dari cache, dibaca ulang dari database setelah JWT_KEY_CACHE_TTL (default 1m)
+// agar rotasi di instance lain ikut terbaca
+func loadKeyring(force bool) (*keyring, error) {
+	keyringMu.RLock()
+	current := cachedKeyring
+	keyringMu.RUnlock()
+	if current != nil && !force && time.Since(current.LoadedAt) < utils.EnvDuration("JWT_KEY_CACHE_TTL", time.Minute) {
+		return current, nil
+	}
+
+	var rows []models.SigningKey
+	now := time.Now()
+	if err := database.DB.Where("expires_at IS NULL OR expires_at > ?", now).
+		Order("created_at DESC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	ring := &keyring{Keys: make(map[string]verificationKey, len(rows)), LoadedAt: now}
+	for _, row := range rows {
+		public, err := utils.ParsePublicKeyPEM(row.PublicKey)
+		if err != nil {
+			log.Printf("[auth] signing key %s: %v", row.KID, err)
+			continue
+		}
+		ring.Keys[row.KID] = verificationKey{Algorithm: row.Algorithm, Public: public, ExpiresAt: row.ExpiresAt}
+		ring.KIDs = append(ring.KIDs, row.KID)
+
+		if ring.Signer != nil || row.RetiredAt != nil || row.Algorithm != jwtAlgorithm() {
+			continue
+		}
+		signer, err := decodeSigningKey(row)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", row.KID, err)
+		}
+		ring.SignerKID, ring.SignerAlg, ring.Signer = row.KID, row.Algorithm, signer
+	}
+
+	keyringMu.Lock()
+	cachedKeyring = ring
+	keyringMu.Unlock()
+	return ring, nil
+}
+
+// decodeSigningKey - Private key dari baris signing_keys (didekripsi jika Encrypted)
+func decodeSigningKey(row models.SigningKey) (crypto.Signer, error) {
+	privatePEM := row.PrivateKey
+	if row.Encrypted {
+		var err error
+		if privatePEM, err = utils.Decrypt(row.PrivateKey); err != nil {
+			return nil, err
+		}
+	}
+	return utils.ParsePrivateKeyPEM(privatePEM)
+}
+
+// createSigningKey - Pasangan kunci baru untuk algoritma aktif. Private key dienkripsi dengan
+// APP_ENCRYPTION_KEY jika diset, tanpa kunci enkripsi disimpan apa adanya (dengan peringatan di log).
+func createSigningKey(tx *gorm.DB, alg string) (models.SigningKey, error) {
+	privatePEM, publicPEM, err := utils.GenerateKeyPair(alg)
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+	public, err := utils.ParsePublicKeyPEM(publicPEM)
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+	jwk, err := utils.PublicJWK(public, alg, "")
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+
+	row := models.SigningKey{KID: jwk.Kid, Algorithm: alg, PrivateKey: privatePEM, PublicKey: publicPEM}
+	encrypted, err := utils.Encrypt(privatePEM)
+	switch {
+	case err == nil:
+		row.PrivateKey, row.Encrypted = encrypted, true
+	case errors.Is(err, utils.ErrEncryptionKeyMissing):
+		log.Printf("[auth] APP_ENCRYPTION_KEY kosong, private key JWT %s disimpan tanpa enkripsi", jwk.Kid)
+	default:
+		return models.SigningKey{}, err
+	}
+	return row, tx.Create(&row).Error
+}
+
+// rotateSigningKey - Buat kunci aktif baru jika belum ada, algoritma berubah, sudah melewati
+// JWT_KEY_ROTATION_INTERVAL, atau force. Kunci aktif sebelumnya berhenti dipakai sign dan
+// tetap diterima sampai access token terakhirnya kadaluarsa.
+func rotateSigningKey(now time.Time, force bool) (bool, error) {
+	alg := jwtAlgorithm()
+	if alg == models.SigningAlgHS256 {
+		return false, nil
+	}
+
+	rotated := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", signingKeyLockID).Error; err != nil {
+			return err
+		}
+
+		var active []models.SigningKey
+		if err := tx.Where("retired_at IS NULL").Order("created_at DESC").Find(&active).Error; err != nil {
+			return err
+		}
+		keep := ""
+		if !force && len(active) > 0 && active[0].Algorithm == alg && now.Sub(active[0].CreatedAt) < signingKeyRotationInterval() {
+			keep = active[0].KID
+		} else {
+			row, err := createSigningKey(tx, alg)
+			if err != nil {
+				return err
+			}
+			keep = row.KID
+			rotated = true
+		}
+
+		// Semua kunci aktif lain (kunci lama / algoritma lama / kunci ganda) berhenti dipakai sign,
+		// private key-nya tidak dibutuhkan lagi dan langsung dihapus
+		expiresAt := now.Add(retiredKeyLifetime())
+		return tx.Model(&models.SigningKey{}).
+			Where("retired_at IS NULL AND kid <> ?", keep).
+			Updates(map[string]interface{}{"retired_at": now, "expires_at": expiresAt, "private_key": "", "encrypted": false}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	if _, err := loadKeyring(true); err != nil {
+		return rotated, err
+	}
+	return rotated, nil
+}
+
+// currentSigner - Kunci aktif untuk sign, dibuat otomatis jika belum ada (start pertama / algoritma diganti)
+func currentSigner() (*keyring, error) {
+	ring, err := loadKeyring(false)
+	if err != nil {
+		return nil, err
+	}
+	if ring.Signer != nil {
+		return ring, nil
+	}
+	if _, err := rotateSigningKey(time.Now(), false); err != nil {
+		return nil, err
+	}
+	if ring, err = loadKeyring(false); err != nil {
+		return nil, err
+	}
+	if ring.Signer == nil {
+		return nil, errors.New("no active signing key")
+	}
+	return ring, nil
+}
+
+// signAccessToken - Tanda tangani claims dengan kunci aktif (header kid), atau JWT_SECRET untuk HS256
+func signAccessToken(claims AccessClaims) (string, error) {
+	if jwtAlgorithm() == models.SigningAlgHS256 {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("JWT_SECRET")))
+	}
+
+	ring, err := currentSigner()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(ring.SignerAlg), claims)
+	token.Header["kid"] = ring.SignerKID
+	return token.SignedString(ring.Signer)
+}
+
+// accessTokenKey - jwt.Keyfunc: public key sesuai kid & algoritma token.
+// kid yang belum dikenal memicu baca ulang kunci (maks. sekali per 10 detik, mencegah banjir query dari kid palsu).
+func accessTokenKey(token *jwt.Token) (interface{}, error) {
+	if jwtAlgorithm() == models.SigningAlgHS256 {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("metode signing tidak valid")
+		}
+		return []byte(os.Getenv("JWT_SECRET")), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrUnknownSigningKey
+	}
+	ring, err := loadKeyring(false)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := ring.Keys[kid]
+	if !ok {
+		keyringMu.Lock()
+		reload := time.Since(lastForceReload) > 10*time.Second
+		if reload {
+			lastForceReload = time.Now()
+		}
+		keyringMu.Unlock()
+		if !reload {
+			return nil, ErrUnknownSigningKey
+		}
+		if ring, err = loadKeyring(true); err != nil {
+			return nil, err
+		}
+		if key, ok = ring.Keys[kid]; !ok {
+			return nil, ErrUnknownSigningKey
+		}
+	}
+	if key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt) {
+		return nil, ErrUnknownSigningKey
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("metode signing tidak valid")
+	}
+	return key.Public, nil
+}
+
+// accessTokenMethods - Algoritma yang diterima saat parse (mencegah alg confusion, misal "none" / HS256 dengan public key)
+func accessTokenMethods() []string {
+	if jwtAlgorithm() == models.SigningAlgHS256 {
+		return []string{models.SigningAlgHS256}
+	}
+	return []string{models.SigningAlgRS256, models.SigningAlgEdDSA}
+}
+
+// JWKS - Public key yang masih diterima (aktif + yang baru di-rotate) untuk verifikasi access token oleh service lain
+func (s *AuthService) JWKS() (utils.JWKSet, error) {
+	set := utils.JWKSet{Keys: []utils.JWK{}}
+	if jwtAlgorithm() == models.SigningAlgHS256 {
+		return set, nil
+	}
+	if _, err := currentSigner(); err != nil {
+		return set, err
+	}
+	ring, err := loadKeyring(false)
+	if err != nil {
+		return set, err
+	}
+
+	now := time.Now()
+	for _, kid := range ring.KIDs {
+		key := ring.Keys[kid]
+		if key.ExpiresAt != nil && now.After(*key.ExpiresAt) {
+			continue
+		}
+		jwk, err := utils.PublicJWK(key.Public, key.Algorithm, kid)
+		if err != nil {
+			return set, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+// RotateSigningKeys - Dipanggil job berkala: rotasi kunci yang sudah melewati JWT_KEY_ROTATION_INTERVAL
+// dan hapus permanen kunci yang sudah tidak diterima lagi
+func (s *AuthService) RotateSigningKeys(now time.Time) (bool, error) {
+	rotated, err := rotateSigningKey(now, false)
+	if err != nil {
+		return rotated, err
+	}
+	err = database.DB.Unscoped().Where("expires_at < ?", now).Delete(&models.SigningKey{}).Error
+	return rotated, err
+}
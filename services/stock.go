@@ -0,0 +1,323 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StockService menangani ledger pergerakan stok gudang
+type StockService struct{}
+
+// ErrInsufficientStock - Movement akan membuat stok negatif
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// stockChange - Parameter internal untuk applyStockMovement
+type stockChange struct {
+	ProductID   uuid.UUID
+	WarehouseID uuid.UUID
+	Delta       int
+	Type        string
+	Reason      string
+	ActorID     *uuid.UUID
+	ReferenceID *uuid.UUID
+}
+
+// applyStockMovement - Satu-satunya jalur untuk mengubah Product.Stock & WarehouseStock
+// Wajib dipanggil di dalam DB transaction.
+// Alur: Lock row produk -> Lock stok gudang -> Validasi saldo -> Update keduanya -> Catat movement
+func applyStockMovement(tx *gorm.DB, change stockChange) (models.StockMovement, error) {
+	var product models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&product, "id = ?", change.ProductID).Error; err != nil {
+		return models.StockMovement{}, err
+	}
+
+	// Pastikan row stok gudang ada, lalu kunci
+	level := models.WarehouseStock{WarehouseID: change.WarehouseID, ProductID: product.ID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&level).Error; err != nil {
+		return models.StockMovement{}, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&level, "warehouse_id = ? AND product_id = ?", change.WarehouseID, product.ID).Error; err != nil {
+		return models.StockMovement{}, err
+	}
+
+	warehouseBalance := level.Quantity + change.Delta
+	if warehouseBalance < 0 {
+		return models.StockMovement{}, fmt.Errorf("%w: %s has %d in warehouse, requested %d", ErrInsufficientStock, product.Name, level.Quantity, -change.Delta)
+	}
+	balance := product.Stock + change.Delta
+	if balance < 0 {
+		return models.StockMovement{}, fmt.Errorf("%w: %s has %d, requested %d", ErrInsufficientStock, product.Name, product.Stock, -change.Delta)
+	}
+
+	if err := tx.Model(&level).Update("quantity", warehouseBalance).Error; err != nil {
+		return models.StockMovement{}, err
+	}
+	wasOutOfStock := product.Stock <= 0
+	if err := tx.Model(&product).Update("stock", balance).Error; err != nil {
+		return models.StockMovement{}, err
+	}
+	// Stok habis -> tersedia: kabari pembeli yang menyimpan produk ini di wishlist.
+	// Transfer antar gudang tidak mengubah total (TRANSFER_OUT lalu TRANSFER_IN), total 0 sementara bukan stok habis
+	isTransfer := change.Type == models.MovementTransferOut || change.Type == models.MovementTransferIn
+	if wasOutOfStock && balance > 0 && !isTransfer {
+		if err := notifyWishlistBackInStock(tx, product); err != nil {
+			return models.StockMovement{}, err
+		}
+	}
+
+	warehouseID := change.WarehouseID
+	movement := models.StockMovement{
+		ProductID:             product.ID,
+		WarehouseID:           &warehouseID,
+		Type:                  change.Type,
+		Quantity:              change.Delta,
+		BalanceAfter:          balance,
+		WarehouseBalanceAfter: warehouseBalance,
+		Reason:                change.Reason,
+		ActorID:               change.ActorID,
+		ReferenceID:           change.ReferenceID,
+	}
+	if err := tx.Create(&movement).Error; err != nil {
+		return models.StockMovement{}, err
+	}
+	return movement, nil
+}
+
+// warehouseQuantity - Stok produk di satu gudang, 0 jika belum ada baris stok gudang.
+// Error database lain dikembalikan (bukan dianggap 0) agar selisih STOCKTAKE / set stok tidak salah.
+func warehouseQuantity(tx *gorm.DB, warehouseID uuid.UUID, productID uuid.UUID) (int, error) {
+	var level models.WarehouseStock
+	err := tx.Where("warehouse_id = ? AND product_id = ?", warehouseID, productID).First(&level).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return level.Quantity, err
+}
+
+// resolveWarehouseID - Pakai warehouseID jika dikirim, jika kosong pakai gudang default
+func resolveWarehouseID(tx *gorm.DB, warehouseID string) (uuid.UUID, error) {
+	var warehouse models.Warehouse
+	if warehouseID != "" {
+		if err := tx.First(&warehouse, "id = ?", warehouseID).Error; err != nil {
+			return uuid.Nil, errors.New("warehouse not found")
+		}
+		return warehouse.ID, nil
+	}
+	if err := tx.Where("is_default = ?", true).First(&warehouse).Error; err != nil {
+		return uuid.Nil, errors.New("default warehouse is not configured")
+	}
+	return warehouse.ID, nil
+}
+
+// parseOptionalUUID - Helper untuk actor/reference yang boleh kosong
+func parseOptionalUUID(id string) *uuid.UUID {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+// RecordMovementInput - Input movement manual oleh admin
+// Quantity: RETURN/PURCHASE_RECEIPT = jumlah masuk, DAMAGE = jumlah keluar,
+// ADJUSTMENT = delta bertanda (+/-), STOCKTAKE = hasil hitung fisik di gudang (saldo baru)
+type RecordMovementInput struct {
+	Type        string `json:"type" binding:"required,oneof=PURCHASE_RECEIPT RETURN ADJUSTMENT DAMAGE STOCKTAKE"`
+	Quantity    *int   `json:"quantity" binding:"required"`
+	Reason      string `json:"reason" binding:"required"`
+	WarehouseID string `json:"warehouse_id"` // Kosong = gudang default
+	ReferenceID string `json:"reference_id"`
+}
+
+// RecordMovement - Admin mencatat pergerakan stok manual
+func (s *StockService) RecordMovement(productID string, actorID string, input RecordMovementInput) (models.StockMovement, error) {
+	pUUID, err := uuid.Parse(productID)
+	if err != nil {
+		return models.StockMovement{}, errors.New("invalid product ID")
+	}
+
+	var movement models.StockMovement
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var product models.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, "id = ?", pUUID).Error; err != nil {
+			return err
+		}
+
+		warehouseID, err := resolveWarehouseID(tx, input.WarehouseID)
+		if err != nil {
+			return err
+		}
+
+		qty := *input.Quantity
+		var delta int
+		switch input.Type {
+		case models.MovementPurchaseReceipt, models.MovementReturn:
+			if qty <= 0 {
+				return errors.New("quantity must be greater than 0")
+			}
+			delta = qty
+		case models.MovementDamage:
+			if qty <= 0 {
+				return errors.New("quantity must be greater than 0")
+			}
+			delta = -qty
+		case models.MovementAdjustment:
+			if qty == 0 {
+				return errors.New("adjustment quantity cannot be 0")
+			}
+			delta = qty
+		case models.MovementStocktake:
+			if qty < 0 {
+				return errors.New("counted stock cannot be negative")
+			}
+			counted, err := warehouseQuantity(tx, warehouseID, product.ID)
+			if err != nil {
+				return err
+			}
+			delta = qty - counted
+		}
+
+		movement, err = applyStockMovement(tx, stockChange{
+			ProductID:   product.ID,
+			WarehouseID: warehouseID,
+			Delta:       delta,
+			Type:        input.Type,
+			Reason:      input.Reason,
+			ActorID:     parseOptionalUUID(actorID),
+			ReferenceID: parseOptionalUUID(input.ReferenceID),
+		})
+		return err
+	})
+
+	return movement, err
+}
+
+// StockMovementDetail - Response histori stok
+type StockMovementDetail struct {
+	ID                    string    `json:"id"`
+	Type                  string    `json:"type"`
+	Quantity              int       `json:"quantity"`
+	BalanceAfter          int       `json:"balance_after"`
+	Warehouse             string    `json:"warehouse,omitempty"`
+	WarehouseBalanceAfter int       `json:"warehouse_balance_after"`
+	Reason                string    `json:"reason"`
+	ActorName             string    `json:"actor_name,omitempty"`
+	ReferenceID           string    `json:"reference_id,omitempty"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+// GetStockHistory - Histori pergerakan stok satu produk (terbaru dulu)
+func (s *StockService) GetStockHistory(productID string, warehouseID string, movementType string, limit int) ([]StockMovementDetail, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var product models.Product
+	if err := database.DB.First(&product, "id = ?", productID).Error; err != nil {
+		return nil, err
+	}
+
+	query := database.DB.Preload("Actor").Preload("Warehouse").Where("product_id = ?", product.ID)
+	if warehouseID != "" {
+		query = query.Where("warehouse_id = ?", warehouseID)
+	}
+	if movementType != "" {
+		query = query.Where("type = ?", movementType)
+	}
+
+	var movements []models.StockMovement
+	if err := query.Order("created_at DESC").Limit(limit).Find(&movements).Error; err != nil {
+		return nil, err
+	}
+
+	result := []StockMovementDetail{}
+	for _, m := range movements {
+		detail := StockMovementDetail{
+			ID:                    m.ID.String(),
+			Type:                  m.Type,
+			Quantity:              m.Quantity,
+			BalanceAfter:          m.BalanceAfter,
+			WarehouseBalanceAfter: m.WarehouseBalanceAfter,
+			Reason:                m.Reason,
+			CreatedAt:             m.CreatedAt,
+		}
+		if m.Warehouse != nil {
+			detail.Warehouse = m.Warehouse.Name
+		}
+		if m.Actor != nil {
+			detail.ActorName = m.Actor.Name
+		}
+		if m.ReferenceID != nil {
+			detail.ReferenceID = m.ReferenceID.String()
+		}
+		result = append(result, detail)
+	}
+	return result, nil
+}
+
+// StockConsistencyItem - Hasil cek Product.Stock vs total ledger & total stok per gudang
+type StockConsistencyItem struct {
+	ProductID      string `json:"product_id"`
+	ProductName    string `json:"product_name"`
+	Stock          int    `json:"stock"`
+	LedgerTotal    int    `json:"ledger_total"`
+	WarehouseTotal int    `json:"warehouse_total"`
+	Difference     int    `json:"difference"`
+	IsConsistent   bool   `json:"is_consistent"`
+}
+
+// CheckConsistency - Bandingkan Product.Stock dengan SUM(stock_movements.quantity)
+// dan SUM(warehouse_stocks.quantity)
+// Jika showAll false, hanya produk yang tidak konsisten yang dikembalikan
+func (s *StockService) CheckConsistency(showAll bool) ([]StockConsistencyItem, error) {
+	var results []struct {
+		ProductID      string
+		ProductName    string
+		Stock          int
+		LedgerTotal    int
+		WarehouseTotal int
+	}
+
+	query := `
+		SELECT * FROM (
+			SELECT
+				products.id as product_id,
+				products.name as product_name,
+				products.stock,
+				(SELECT COALESCE(SUM(quantity), 0) FROM stock_movements WHERE stock_movements.product_id = products.id) as ledger_total,
+				(SELECT COALESCE(SUM(quantity), 0) FROM warehouse_stocks WHERE warehouse_stocks.product_id = products.id) as warehouse_total
+			FROM products
+			WHERE products.deleted_at IS NULL
+		) stock_check
+	`
+	if !showAll {
+		query += " WHERE stock <> ledger_total OR stock <> warehouse_total"
+	}
+
+	if err := database.DB.Raw(query + " ORDER BY product_name").Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	report := []StockConsistencyItem{}
+	for _, r := range results {
+		report = append(report, StockConsistencyItem{
+			ProductID:      r.ProductID,
+			ProductName:    r.ProductName,
+			Stock:          r.Stock,
+			LedgerTotal:    r.LedgerTotal,
+			WarehouseTotal: r.WarehouseTotal,
+			Difference:     r.Stock - r.LedgerTotal,
+			IsConsistent:   r.Stock == r.LedgerTotal && r.Stock == r.WarehouseTotal,
+		})
+	}
+	return report, nil
+}
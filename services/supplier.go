@@ -0,0 +1,124 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SupplierService menangani master data supplier
+type SupplierService struct{}
+
+// ErrSupplierInUse - Supplier masih punya purchase order yang belum selesai (HTTP 409)
+var ErrSupplierInUse = errors.New("supplier is still in use")
+
+// SupplierInput - Input create/update supplier
+type SupplierInput struct {
+	Name         string `json:"name" binding:"required"`
+	ContactName  string `json:"contact_name"`
+	Email        string `json:"email" binding:"omitempty,email"`
+	Phone        string `json:"phone"`
+	Address      string `json:"address"`
+	LeadTimeDays int    `json:"lead_time_days" binding:"omitempty,min=0"`
+	IsActive     *bool  `json:"is_active"`
+}
+
+func (s *SupplierService) GetAll(search string) ([]models.Supplier, error) {
+	var suppliers []models.Supplier
+	query := database.DB.Order("name ASC")
+	if search != "" {
+		query = query.Where("name ILIKE ?", "%"+search+"%")
+	}
+	err := query.Find(&suppliers).Error
+	return suppliers, err
+}
+
+func (s *SupplierService) GetByID(id string) (models.Supplier, error) {
+	var supplier models.Supplier
+	err := database.DB.First(&supplier, "id = ?", id).Error
+	return supplier, err
+}
+
+func (s *SupplierService) Create(input SupplierInput) (models.Supplier, error) {
+	supplier := models.Supplier{
+		Name:         input.Name,
+		ContactName:  input.ContactName,
+		Email:        input.Email,
+		Phone:        input.Phone,
+		Address:      input.Address,
+		LeadTimeDays: input.LeadTimeDays,
+		IsActive:     input.IsActive == nil || *input.IsActive,
+	}
+	if supplier.LeadTimeDays == 0 {
+		supplier.LeadTimeDays = 7
+	}
+	err := database.DB.Create(&supplier).Error
+	return supplier, err
+}
+
+func (s *SupplierService) Update(id string, input SupplierInput) (models.Supplier, error) {
+	var supplier models.Supplier
+	if err := database.DB.First(&supplier, "id = ?", id).Error; err != nil {
+		return supplier, err
+	}
+
+	updates := map[string]interface{}{
+		"name":         input.Name,
+		"contact_name": input.ContactName,
+		"email":        input.Email,
+		"phone":        input.Phone,
+		"address":      input.Address,
+	}
+	if input.LeadTimeDays > 0 {
+		updates["lead_time_days"] = input.LeadTimeDays
+	}
+	if input.IsActive != nil {
+		updates["is_active"] = *input.IsActive
+	}
+
+	if err := database.DB.Model(&supplier).Updates(updates).Error; err != nil {
+		return supplier, err
+	}
+	database.DB.First(&supplier, "id = ?", id)
+	return supplier, nil
+}
+
+// Delete - Soft delete supplier yang tidak punya PO terbuka, relasi supplier utama di produk dilepas
+func (s *SupplierService) Delete(id string) error {
+	var supplier models.Supplier
+	if err := database.DB.First(&supplier, "id = ?", id).Error; err != nil {
+		return err
+	}
+
+	var openOrders int64
+	database.DB.Model(&models.PurchaseOrder{}).
+		Where("supplier_id = ? AND status IN ?", supplier.ID, []string{models.POStatusDraft, models.POStatusSent, models.POStatusPartiallyReceived}).
+		Count(&openOrders)
+	if openOrders > 0 {
+		return fmt.Errorf("%w: %d open purchase order(s)", ErrSupplierInUse, openOrders)
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		// Produk yang memakai supplier ini kembali ke lead time default
+		if err := tx.Model(&models.Product{}).Where("supplier_id = ?", supplier.ID).Update("supplier_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&supplier).Error
+	})
+}
+
+// resolveSupplierID - Validasi supplier untuk produk, string kosong = tanpa supplier
+func resolveSupplierID(id string) (*uuid.UUID, error) {
+	if id == "" {
+		return nil, nil
+	}
+	var supplier models.Supplier
+	if err := database.DB.First(&supplier, "id = ?", id).Error; err != nil {
+		return nil, errors.New("supplier not found")
+	}
+	return &supplier.ID, nil
+}
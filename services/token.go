@@ -0,0 +1,318 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidRefreshToken - Refresh token tidak dikenal, kadaluarsa, atau sudah dicabut (HTTP 401)
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+// ErrRefreshTokenReuse - Refresh token yang sudah di-rotate dipakai lagi, sesi dicabut (HTTP 401)
+var ErrRefreshTokenReuse = errors.New("refresh token reuse detected, session revoked")
+
+// ErrTokenRevoked - Access token sudah dicabut (logout) (HTTP 401)
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// ClientMeta - Info klien yang dicatat bersama refresh token
+type ClientMeta struct {
+	IP        string
+	UserAgent string
+}
+
+// AccessClaims - Claims access token: sub (user ID), role, perms, jti, iat, exp
+type AccessClaims struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"perms"` // Permission role saat token diterbitkan (nil = token lama)
+	jwt.RegisteredClaims
+}
+
+// TokenPair - Access token berumur pendek + refresh token untuk memperpanjang sesi
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int64     `json:"expires_in"` // Detik sampai access token kadaluarsa
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// RefreshInput - Tukar refresh token dengan pasangan token baru
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutInput - Refresh token sesi ini (opsional) dan opsi keluar dari semua perangkat
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token"`
+	AllDevices   bool   `json:"all_devices"`
+}
+
+// accessTokenTTL - Umur access token (ACCESS_TOKEN_TTL, default 15m)
+func accessTokenTTL() time.Duration {
+	return utils.EnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute)
+}
+
+// refreshTokenTTL - Umur refresh token (REFRESH_TOKEN_TTL, default 30 hari)
+func refreshTokenTTL() time.Duration {
+	return utils.EnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+}
+
+// hashToken - SHA-256 hex dari refresh token (token asli tidak pernah disimpan)
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateOpaqueToken - 32 byte acak, base64url tanpa padding
+func generateOpaqueToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// issueAccessToken - JWT berumur pendek (JWT_ALGORITHM, header kid) dengan jti unik untuk denylist.
+// Perubahan permission role berlaku untuk token baru (paling lambat ACCESS_TOKEN_TTL setelah diubah).
+func issueAccessToken(user models.User, permissions []string, now time.Time) (string, time.Time, error) {
+	if permissions == nil {
+		permissions = []string{}
+	}
+	expiresAt := now.Add(accessTokenTTL())
+	claims := AccessClaims{
+		Role:        user.Role.Name,
+		Permissions: permissions,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    jwtIssuer(),
+			Subject:   user.ID.String(),
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := signAccessToken(claims)
+	return token, expiresAt, err
+}
+
+// issueTokenPair - Access token + refresh token baru dalam family (sesi) yang diberikan
+func issueTokenPair(tx *gorm.DB, user models.User, familyID uuid.UUID, meta ClientMeta) (TokenPair, models.RefreshToken, error) {
+	now := time.Now()
+	// Login, refresh & login 2FA semuanya lewat sini: akun yang ditangguhkan / nonaktif tidak dapat token baru
+	if err := accountStatusError(user, now); err != nil {
+		return TokenPair{}, models.RefreshToken{}, err
+	}
+	permissions, err := rolePermissionNames(tx, user.RoleID)
+	if err != nil {
+		return TokenPair{}, models.RefreshToken{}, err
+	}
+	accessToken, accessExpiresAt, err := issueAccessToken(user, permissions, now)
+	if err != nil {
+		return TokenPair{}, models.RefreshToken{}, err
+	}
+
+	refreshToken, err := generateOpaqueToken()
+	if err != nil {
+		return TokenPair{}, models.RefreshToken{}, err
+	}
+	record := models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(refreshTokenTTL()),
+		IP:        meta.IP,
+		UserAgent: truncate(meta.UserAgent, 255),
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return TokenPair{}, models.RefreshToken{}, err
+	}
+
+	return TokenPair{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(accessExpiresAt.Sub(now).Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: record.ExpiresAt,
+	}, record, nil
+}
+
+// truncate - Potong string agar muat di kolom varchar
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// ParseAccessToken - Validasi signature (kid & algoritma) & expiry access token, lalu cek denylist jti.
+// Dipakai AuthMiddleware pada setiap request.
+func ParseAccessToken(tokenString string) (*AccessClaims, error) {
+	claims := &AccessClaims{}
+	options := []jwt.ParserOption{jwt.WithValidMethods(accessTokenMethods())}
+	if issuer := jwtIssuer(); issuer != "" {
+		options = append(options, jwt.WithIssuer(issuer))
+	}
+	// Signature dicek dengan public key sesuai kid (atau JWT_SECRET untuk HS256)
+	token, err := jwt.ParseWithClaims(tokenString, claims, accessTokenKey, options...)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid || claims.Subject == "" {
+		return nil, errors.New("token invalid claims")
+	}
+
+	// Token lama (sebelum jti diterapkan) tidak punya jti dan tetap berlaku sampai expired
+	if claims.ID != "" {
+		var count int64
+		if err := database.DB.Model(&models.RevokedToken{}).Where("jti = ?", claims.ID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	// Token lama (sebelum permission diterapkan) tidak punya claim perms: ambil dari role saat ini
+	if claims.Permissions == nil {
+		permissions, err := permissionNamesByRoleName(claims.Role)
+		if err != nil {
+			return nil, err
+		}
+		claims.Permissions = permissions
+	}
+	return claims, nil
+}
+
+// revokeAccessToken - Masukkan jti ke denylist sampai access token kadaluarsa
+func revokeAccessToken(tx *gorm.DB, jti string, userID uuid.UUID, expiresAt time.Time, reason string) error {
+	if jti == "" {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+		Reason:    reason,
+	}).Error
+}
+
+// revokeRefreshTokens - Cabut semua refresh token aktif yang cocok dengan query (family / user)
+func revokeRefreshTokens(tx *gorm.DB, reason string, query string, args ...interface{}) error {
+	return tx.Model(&models.RefreshToken{}).
+		Where("revoked_at IS NULL").
+		Where(query, args...).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason}).Error
+}
+
+// RefreshTokens - Rotasi refresh token: token lama dicabut, pasangan token baru diterbitkan
+// dalam family yang sama. Token yang sudah di-rotate dipakai lagi = kemungkinan dicuri,
+// seluruh family dicabut sehingga pencuri maupun pemilik harus login ulang.
+func (s *AuthService) RefreshTokens(input RefreshInput, meta ClientMeta) (TokenPair, error) {
+	var pair TokenPair
+	reuse := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var current models.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&current, "token_hash = ?", hashToken(input.RefreshToken)).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		if current.RevokedAt != nil {
+			if current.RevokedReason == models.TokenRevokedRotated {
+				// Commit pencabutan family, error dikembalikan setelah transaksi selesai
+				reuse = true
+				return revokeRefreshTokens(tx, models.TokenRevokedReuseDetected, "family_id = ?", current.FamilyID)
+			}
+			return ErrInvalidRefreshToken
+		}
+		if time.Now().After(current.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+
+		var user models.User
+		if err := tx.Preload("Role").First(&user, "id = ?", current.UserID).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		var next models.RefreshToken
+		var err error
+		if pair, next, err = issueTokenPair(tx, user, current.FamilyID, meta); err != nil {
+			return err
+		}
+		return tx.Model(&current).Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"revoked_reason": models.TokenRevokedRotated,
+			"replaced_by_id": next.ID,
+		}).Error
+	})
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if reuse {
+		return TokenPair{}, ErrRefreshTokenReuse
+	}
+	return pair, nil
+}
+
+// Logout - Cabut access token yang sedang dipakai (jti) dan sesi refresh token-nya.
+// AllDevices mencabut semua refresh token user (access token lain berakhir sendiri dalam ACCESS_TOKEN_TTL).
+func (s *AuthService) Logout(userID string, jti string, accessExpiresAt time.Time, input LogoutInput) error {
+	uUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := revokeAccessToken(tx, jti, uUUID, accessExpiresAt, models.TokenRevokedLogout); err != nil {
+			return err
+		}
+
+		if input.AllDevices {
+			return revokeRefreshTokens(tx, models.TokenRevokedLogout, "user_id = ?", uUUID)
+		}
+		if input.RefreshToken == "" {
+			return nil
+		}
+
+		var current models.RefreshToken
+		if err := tx.Where("token_hash = ? AND user_id = ?", hashToken(input.RefreshToken), uUUID).
+			Limit(1).Find(&current).Error; err != nil {
+			return err
+		}
+		if current.ID == uuid.Nil {
+			return nil // Token tidak dikenal: logout tetap dianggap berhasil
+		}
+		return revokeRefreshTokens(tx, models.TokenRevokedLogout, "family_id = ?", current.FamilyID)
+	})
+}
+
+// PurgeExpiredTokens - Hapus permanen refresh token, denylist, token email, dan state login OIDC yang sudah kadaluarsa,
+// serta penghitung login gagal yang sudah usang
+func (s *AuthService) PurgeExpiredTokens(now time.Time) (int64, error) {
+	var total int64
+	for _, model := range []interface{}{&models.RefreshToken{}, &models.RevokedToken{}, &models.UserToken{}, &models.OIDCLoginState{}} {
+		result := database.DB.Unscoped().Where("expires_at < ?", now).Delete(model)
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += result.RowsAffected
+	}
+
+	// Penghitung login gagal yang sudah usang (di luar LOGIN_ATTEMPT_WINDOW dan tidak diblokir)
+	purged, err := loginAttemptStore().Purge(now.Add(-loginAttemptWindow()))
+	return total + purged, err
+}
@@ -2,10 +2,13 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"technical-test-backend/database"
 	"technical-test-backend/models"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -37,32 +40,56 @@ func (s *TransactionService) CreateOrder(input CreateOrderInput) (models.Transac
 		return models.Transaction{}, errors.New("produk tidak aktif")
 	}
 
-	// Hitung Kalkulasi Keuangan
-	qty := float64(input.Quantity)
-	
-	// Uang Masuk dari Pembeli (Harga Seller * Qty)
-	grandTotal := item.SellingPrice * qty 
-	
-	// Jatah Admin (Harga Modal * Qty)
-	totalAdminFee := item.Product.Price * qty 
-	
-	// Jatah Seller (Sisa uang)
-	totalSellerProfit := grandTotal - totalAdminFee
+	// Validasi Seller tidak sedang libur
+	if err := checkSellerAvailable(database.DB, item.SellerID); err != nil {
+		return models.Transaction{}, err
+	}
 
-	// Simpan Transaksi dengan Detail Keuangan
 	transaction := models.Transaction{
 		UserID:          userUUID,
 		SellerProductID: sellerProductUUID,
 		Quantity:        input.Quantity,
 		Status:          models.StatusPending,
-		
-		// Simpan Snapshot Keuangan
-		TotalPrice:   grandTotal,
-		AdminFee:     totalAdminFee,
-		SellerProfit: totalSellerProfit,
 	}
 
-	if err := database.DB.Create(&transaction).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Harga Flash Sale: kuota dipotong atomic, pembeli bersamaan tidak bisa melebihi kuota.
+		// Kuota yang sudah habis sebelum order dibuat = harga normal.
+		unitPrice := item.SellingPrice
+		running, err := getRunningFlashSaleItems(tx, []uuid.UUID{item.ID}, time.Now())
+		if err != nil {
+			return err
+		}
+		if flash, ok := running[item.ID]; ok && flash.QuotaSold < flash.Quota {
+			reserved, err := reserveFlashSaleQuota(tx, flash.ID, input.Quantity)
+			if err != nil {
+				return err
+			}
+			if !reserved {
+				var current models.FlashSaleItem
+				tx.First(&current, "id = ?", flash.ID)
+				return fmt.Errorf("%w: sisa kuota flash sale %d", ErrFlashSaleQuotaExceeded, max(current.Quota-current.QuotaSold, 0))
+			}
+			unitPrice = flashSalePrice(flash.FlashSaleItem, item)
+			transaction.FlashSaleItemID = &flash.ID
+		}
+
+		// Hitung Kalkulasi Keuangan
+		qty := float64(input.Quantity)
+
+		// Uang Masuk dari Pembeli (Harga Seller / Harga Promo * Qty)
+		transaction.TotalPrice = unitPrice * qty
+
+		// Jatah Admin (Harga Modal * Qty)
+		transaction.AdminFee = item.Product.Price * qty
+
+		// Jatah Seller (Sisa uang)
+		transaction.SellerProfit = transaction.TotalPrice - transaction.AdminFee
+
+		// Simpan Transaksi dengan Snapshot Keuangan
+		return tx.Create(&transaction).Error
+	})
+	if err != nil {
 		return models.Transaction{}, err
 	}
 
@@ -139,15 +166,35 @@ func (s *TransactionService) GetCustomerTransactions(customerID string) ([]Custo
 
 	return transactions, nil
 }
+// orderAuditData - Detail order untuk audit log perubahan status
+func orderAuditData(transaction models.Transaction, fromStatus string, extra map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{
+		"before":            map[string]interface{}{"status": fromStatus},
+		"after":             map[string]interface{}{"status": transaction.Status},
+		"seller_product_id": transaction.SellerProductID,
+		"buyer_id":          transaction.UserID,
+		"quantity":          transaction.Quantity,
+		"total_price":       transaction.TotalPrice,
+		"seller_profit":     transaction.SellerProfit,
+	}
+	for key, value := range extra {
+		data[key] = value
+	}
+	return data
+}
+
 // SELLER CONFIRM (Potong Stok Admin)
-func (s *TransactionService) ConfirmOrder(transactionID string, sellerID string) error {
+// Konfirmasi (dan pembatalan otomatis karena stok habis) dicatat di audit log.
+func (s *TransactionService) ConfirmOrder(transactionID string, sellerID string, audit *AuditContext) error {
 	txUUID, _ := uuid.Parse(transactionID)
 
 	txDB := database.DB.Begin()
 
-	// Cek Transaksi milik Seller ini
+	// Cek Transaksi milik Seller ini. Baris transaksi dikunci sampai commit agar pengecekan PENDING
+	// & perubahan status tidak balapan dengan pembatalan oleh pembeli (CancelTransaction)
 	var transaction models.Transaction
 	if err := txDB.Preload("SellerProduct").
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "transactions"}}).
 		Joins("JOIN seller_products ON seller_products.id = transactions.seller_product_id").
 		Where("transactions.id = ? AND seller_products.seller_id = ?", txUUID, sellerID).
 		First(&transaction).Error; err != nil {
@@ -168,27 +215,59 @@ func (s *TransactionService) ConfirmOrder(transactionID string, sellerID string)
 		return errors.New("produk master hilang")
 	}
 
-	if masterProduct.Stock < transaction.Quantity {
-		// Auto Cancel jika stok admin habis
+	// Pilih gudang: terdekat dari alamat pembeli, lalu stok terbanyak
+	var buyer models.User
+	txDB.First(&buyer, "id = ?", transaction.UserID)
+	allocations, err := allocateStock(txDB, masterProduct.ID, transaction.Quantity, buyer)
+	if masterProduct.Stock < transaction.Quantity || errors.Is(err, ErrInsufficientStock) {
+		// Auto Cancel jika stok admin habis, kuota flash sale dikembalikan
 		transaction.Status = models.StatusCancelled
-		txDB.Save(&transaction)
-		txDB.Commit()
-		return errors.New("stok gudang pusat habis")
+		if err := txDB.Save(&transaction).Error; err != nil {
+			txDB.Rollback(); return err
+		}
+		if transaction.FlashSaleItemID != nil {
+			if err := releaseFlashSaleQuota(txDB, *transaction.FlashSaleItemID, transaction.Quantity); err != nil {
+				txDB.Rollback(); return err
+			}
+		}
+		if err := audit.Record(txDB, models.AuditOrderCancelled, "transaction", &transaction.ID,
+			orderAuditData(transaction, models.StatusPending, map[string]interface{}{"reason": "stok gudang habis"})); err != nil {
+			txDB.Rollback(); return err
+		}
+		if err := txDB.Commit().Error; err != nil {
+			return err
+		}
+		return errors.New("stok gudang habis")
+	}
+	if err != nil {
+		txDB.Rollback(); return err
 	}
 
-	// Kurangi Stok & Selesaikan
-	masterProduct.Stock -= transaction.Quantity
+	// Kurangi Stok tiap gudang lewat ledger (movement SALE) & Selesaikan
+	for _, allocation := range allocations {
+		if _, err := applyStockMovement(txDB, stockChange{
+			ProductID:   masterProduct.ID,
+			WarehouseID: allocation.WarehouseID,
+			Delta:       -allocation.Quantity,
+			Type:        models.MovementSale,
+			Reason:      "Order confirmed",
+			ActorID:     parseOptionalUUID(sellerID),
+			ReferenceID: &transaction.ID,
+		}); err != nil {
+			txDB.Rollback(); return err
+		}
+	}
 	transaction.Status = models.StatusCompleted
 
-	if err := txDB.Save(&masterProduct).Error; err != nil {
+	if err := txDB.Save(&transaction).Error; err != nil {
 		txDB.Rollback(); return err
 	}
-	if err := txDB.Save(&transaction).Error; err != nil {
+	if err := audit.Record(txDB, models.AuditOrderConfirmed, "transaction", &transaction.ID,
+		orderAuditData(transaction, models.StatusPending, map[string]interface{}{"allocations": allocations})); err != nil {
 		txDB.Rollback(); return err
 	}
 	
-	txDB.Commit()
-	return nil
+	return txDB.Commit().Error
 }
 
 // GET Seller Transactions - List semua transaksi dari produk seller
@@ -343,8 +422,8 @@ func (s *TransactionService) GetTransactionDetail(transactionID string) (Transac
 	}, nil
 }
 
-// CancelTransaction - Cancel transaction by customer
-func (s *TransactionService) CancelTransaction(transactionID string, userID string) error {
+// CancelTransaction - Cancel transaction by customer, dicatat di audit log
+func (s *TransactionService) CancelTransaction(transactionID string, userID string, audit *AuditContext) error {
 	txUUID, err := uuid.Parse(transactionID)
 	if err != nil {
 		return errors.New("invalid transaction ID")
@@ -366,11 +445,22 @@ func (s *TransactionService) CancelTransaction(transactionID string, userID stri
 		return errors.New("only pending transactions can be cancelled")
 	}
 
-	// Update status to CANCELLED
-	transaction.Status = models.StatusCancelled
-	if err := database.DB.Save(&transaction).Error; err != nil {
-		return err
-	}
-
-	return nil
+	// Update status to CANCELLED, kuota flash sale dikembalikan
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&transaction).Where("status = ?", models.StatusPending).Update("status", models.StatusCancelled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("only pending transactions can be cancelled")
+		}
+		if transaction.FlashSaleItemID != nil {
+			if err := releaseFlashSaleQuota(tx, *transaction.FlashSaleItemID, transaction.Quantity); err != nil {
+				return err
+			}
+		}
+		transaction.Status = models.StatusCancelled
+		return audit.Record(tx, models.AuditOrderCancelled, "transaction", &transaction.ID,
+			orderAuditData(transaction, models.StatusPending, map[string]interface{}{"reason": "dibatalkan pembeli"}))
+	})
 }
\ No newline at end of file
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTwoFactorNotAllowed - Role user tidak termasuk TWO_FACTOR_ROLES (HTTP 403)
+var ErrTwoFactorNotAllowed = errors.New("two-factor authentication is not available for this role")
+
+// ErrTwoFactorAlreadyEnabled - 2FA sudah aktif (HTTP 409)
+var ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+
+// ErrTwoFactorNotEnabled - 2FA belum aktif / enrolment belum dimulai (HTTP 409)
+var ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+
+// ErrTwoFactorRequired - Role wajib 2FA, tidak bisa dinonaktifkan (HTTP 409)
+var ErrTwoFactorRequired = errors.New("two-factor authentication is required for this role")
+
+// ErrInvalidTwoFactorCode - Kode TOTP / recovery code salah atau sudah dipakai (HTTP 400, login: 401)
+var ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+
+// ErrInvalidPassword - Konfirmasi password salah (HTTP 401)
+var ErrInvalidPassword = errors.New("password salah")
+
+// recoveryCodeCount - Jumlah recovery code per user
+const recoveryCodeCount = 10
+
+// TwoFactorService menangani enrolment & pengelolaan TOTP 2FA
+type TwoFactorService struct{}
+
+// TwoFactorStatus - Status 2FA user yang sedang login
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	Allowed                bool       `json:"allowed"`  // Role boleh memakai 2FA
+	Required               bool       `json:"required"` // Role wajib 2FA (TWO_FACTOR_REQUIRED_ROLES)
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorSetup - Secret baru untuk aplikasi authenticator, tampilkan provisioning_uri sebagai QR code
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TwoFactorCodeInput - Kode 6 digit dari aplikasi authenticator
+type TwoFactorCodeInput struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTwoFactorInput - Password + kode TOTP atau recovery code
+type DisableTwoFactorInput struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// RecoveryCodesResult - Recovery code baru, hanya ditampilkan sekali
+type RecoveryCodesResult struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// roleListEnv - Daftar nama role dari env (dipisah koma)
+func roleListEnv(key string, fallback string) []string {
+	var roles []string
+	for _, role := range strings.Split(utils.EnvString(key, fallback), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+func roleInList(role string, roles []string) bool {
+	for _, r := range roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// twoFactorRequired - Role wajib 2FA (TWO_FACTOR_REQUIRED_ROLES, default kosong = opsional untuk semua)
+func twoFactorRequired(role string) bool {
+	return roleInList(role, roleListEnv("TWO_FACTOR_REQUIRED_ROLES", ""))
+}
+
+// twoFactorAllowed - Role boleh mengaktifkan 2FA (TWO_FACTOR_ROLES, default Admin & Seller).
+// Role yang wajib 2FA selalu boleh.
+func twoFactorAllowed(role string) bool {
+	return twoFactorRequired(role) || roleInList(role, roleListEnv("TWO_FACTOR_ROLES", "Admin,Seller"))
+}
+
+// loadUserWithRole - User beserta role untuk cek kebijakan 2FA
+func loadUserWithRole(tx *gorm.DB, userID interface{}) (models.User, error) {
+	var user models.User
+	err := tx.Preload("Role").First(&user, "id = ?", userID).Error
+	return user, err
+}
+
+// getTwoFactor - Data 2FA user (found false jika belum pernah enrolment)
+func getTwoFactor(tx *gorm.DB, userID uuid.UUID, lock bool) (models.UserTwoFactor, bool, error) {
+	var row models.UserTwoFactor
+	query := tx.Where("user_id = ?", userID)
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	if err := query.Limit(1).Find(&row).Error; err != nil {
+		return row, false, err
+	}
+	return row, row.ID != uuid.Nil, nil
+}
+
+// twoFactorEnabled - 2FA user aktif (enrolment sudah dikonfirmasi)
+func twoFactorEnabled(tx *gorm.DB, userID uuid.UUID) (bool, error) {
+	row, found, err := getTwoFactor(tx, userID, false)
+	return found && row.EnabledAt != nil, err
+}
+
+// startTwoFactorSetup - Buat secret baru (menggantikan enrolment yang belum dikonfirmasi)
+func startTwoFactorSetup(tx *gorm.DB, user models.User) (TwoFactorSetup, error) {
+	if !twoFactorAllowed(user.Role.Name) {
+		return TwoFactorSetup{}, ErrTwoFactorNotAllowed
+	}
+	row, found, err := getTwoFactor(tx, user.ID, true)
+	if err != nil {
+		return TwoFactorSetup{}, err
+	}
+	if found && row.EnabledAt != nil {
+		return TwoFactorSetup{}, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return TwoFactorSetup{}, err
+	}
+	encrypted, err := utils.Encrypt(secret)
+	if err != nil {
+		return TwoFactorSetup{}, err
+	}
+	if found {
+		err = tx.Model(&row).Updates(map[string]interface{}{"secret_encrypted": encrypted, "last_used_step": 0}).Error
+	} else {
+		err = tx.Create(&models.UserTwoFactor{UserID: user.ID, SecretEncrypted: encrypted}).Error
+	}
+	if err != nil {
+		return TwoFactorSetup{}, err
+	}
+
+	issuer := utils.EnvString("TWO_FACTOR_ISSUER", "Inventory App")
+	return TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(issuer, user.Email, secret),
+	}, nil
+}
+
+// confirmTwoFactorSetup - Aktifkan 2FA dengan kode pertama dari authenticator, terbitkan recovery code
+func confirmTwoFactorSetup(tx *gorm.DB, user models.User, code string, ip string) ([]string, error) {
+	row, found, err := getTwoFactor(tx, user.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if row.EnabledAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	ok, err := verifyTOTP(tx, &row, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	if err := tx.Model(&row).Update("enabled_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+	codes, err := generateRecoveryCodes(tx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return codes, createAuditLog(tx, &user.ID, models.AuditTwoFactorEnabled, "user", &user.ID, ip, nil)
+}
+
+// verifyTOTP - Cocokkan kode dengan secret user (toleransi ±1 langkah).
+// Langkah yang sudah pernah dipakai ditolak (kode yang sama tidak bisa dipakai dua kali).
+func verifyTOTP(tx *gorm.DB, row *models.UserTwoFactor, code string) (bool, error) {
+	secret, err := utils.Decrypt(row.SecretEncrypted)
+	if err != nil {
+		return false, err
+	}
+	step, ok := utils.ValidateTOTP(secret, code, time.Now(), 1)
+	if !ok || step <= row.LastUsedStep {
+		return false, nil
+	}
+	row.LastUsedStep = step
+	return true, tx.Model(row).Update("last_used_step", step).Error
+}
+
+// normalizeRecoveryCode - Huruf kecil tanpa tanda hubung / spasi
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+}
+
+// generateRecoveryCodes - Ganti semua recovery code user dengan recoveryCodeCount kode baru (format xxxxx-xxxxx)
+func generateRecoveryCodes(tx *gorm.DB, userID uuid.UUID) ([]string, error) {
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, recoveryCodeCount)
+	rows := make([]models.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(buf))[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		rows = append(rows, models.RecoveryCode{UserID: userID, CodeHash: hashToken(raw)})
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// useRecoveryCode - Tandai recovery code terpakai, false jika tidak cocok / sudah dipakai
+func useRecoveryCode(tx *gorm.DB, userID uuid.UUID, code string) (bool, error) {
+	var row models.RecoveryCode
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashToken(normalizeRecoveryCode(code))).
+		Limit(1).Find(&row).Error; err != nil {
+		return false, err
+	}
+	if row.ID == uuid.Nil {
+		return false, nil
+	}
+	return true, tx.Model(&row).Update("used_at", time.Now()).Error
+}
+
+// verifySecondFactor - Kode TOTP, atau recovery code jika bukan 6 digit.
+// recovery true jika yang cocok adalah recovery code.
+func verifySecondFactor(tx *gorm.DB, row *models.UserTwoFactor, code string) (ok bool, recovery bool, err error) {
+	if len(strings.ReplaceAll(strings.TrimSpace(code), " ", "")) == utils.TOTPDigits {
+		ok, err = verifyTOTP(tx, row, code)
+		return ok, false, err
+	}
+	ok, err = useRecoveryCode(tx, row.UserID, code)
+	return ok, ok, err
+}
+
+// GetStatus - Status 2FA user yang sedang login
+func (s *TwoFactorService) GetStatus(userID string) (TwoFactorStatus, error) {
+	user, err := loadUserWithRole(database.DB, userID)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+	row, found, err := getTwoFactor(database.DB, user.ID, false)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+
+	status := TwoFactorStatus{
+		Allowed:  twoFactorAllowed(user.Role.Name),
+		Required: twoFactorRequired(user.Role.Name),
+	}
+	if found && row.EnabledAt != nil {
+		status.Enabled = true
+		status.EnabledAt = row.EnabledAt
+		database.DB.Model(&models.RecoveryCode{}).
+			Where("user_id = ? AND used_at IS NULL", user.ID).Count(&status.RecoveryCodesRemaining)
+	}
+	return status, nil
+}
+
+// Setup - Mulai enrolment: secret baru + provisioning URI, 2FA aktif setelah Enable
+func (s *TwoFactorService) Setup(userID string) (TwoFactorSetup, error) {
+	var setup TwoFactorSetup
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := loadUserWithRole(tx, userID)
+		if err != nil {
+			return err
+		}
+		setup, err = startTwoFactorSetup(tx, user)
+		return err
+	})
+	return setup, err
+}
+
+// Enable - Konfirmasi enrolment dengan kode dari authenticator, kembalikan recovery code
+func (s *TwoFactorService) Enable(userID string, input TwoFactorCodeInput, ip string) (RecoveryCodesResult, error) {
+	var codes []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := loadUserWithRole(tx, userID)
+		if err != nil {
+			return err
+		}
+		codes, err = confirmTwoFactorSetup(tx, user, input.Code, ip)
+		return err
+	})
+	return RecoveryCodesResult{RecoveryCodes: codes}, err
+}
+
+// Disable - Matikan 2FA (butuh password + kode / recovery code). Ditolak untuk role wajib 2FA.
+func (s *TwoFactorService) Disable(userID string, input DisableTwoFactorInput, ip string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := loadUserWithRole(tx, userID)
+		if err != nil {
+			return err
+		}
+		if twoFactorRequired(user.Role.Name) {
+			return ErrTwoFactorRequired
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
+			return ErrInvalidPassword
+		}
+
+		row, found, err := getTwoFactor(tx, user.ID, true)
+		if err != nil {
+			return err
+		}
+		if !found || row.EnabledAt == nil {
+			return ErrTwoFactorNotEnabled
+		}
+		ok, _, err := verifySecondFactor(tx, &row, input.Code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+
+		if err := deleteTwoFactor(tx, user.ID); err != nil {
+			return err
+		}
+		return createAuditLog(tx, &user.ID, models.AuditTwoFactorDisabled, "user", &user.ID, ip, nil)
+	})
+}
+
+// RegenerateRecoveryCodes - Ganti semua recovery code (butuh kode TOTP)
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID string, input TwoFactorCodeInput, ip string) (RecoveryCodesResult, error) {
+	var codes []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := loadUserWithRole(tx, userID)
+		if err != nil {
+			return err
+		}
+		row, found, err := getTwoFactor(tx, user.ID, true)
+		if err != nil {
+			return err
+		}
+		if !found || row.EnabledAt == nil {
+			return ErrTwoFactorNotEnabled
+		}
+		ok, err := verifyTOTP(tx, &row, input.Code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+
+		if codes, err = generateRecoveryCodes(tx, user.ID); err != nil {
+			return err
+		}
+		return createAuditLog(tx, &user.ID, models.AuditRecoveryCodesRegenerated, "user", &user.ID, ip, nil)
+	})
+	return RecoveryCodesResult{RecoveryCodes: codes}, err
+}
+
+// ResetForUser - Admin menghapus 2FA user (perangkat & recovery code hilang).
+// User dengan role wajib 2FA akan diminta enrolment ulang saat login berikutnya.
+func (s *TwoFactorService) ResetForUser(targetUserID string, audit *AuditContext) error {
+	if _, err := audit.actor(); err != nil {
+		return err
+	}
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.First(&user, "id = ?", targetUserID).Error; err != nil {
+			return err
+		}
+		enabled, err := twoFactorEnabled(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			return ErrTwoFactorNotEnabled
+		}
+
+		if err := deleteTwoFactor(tx, user.ID); err != nil {
+			return err
+		}
+		// Sesi yang sedang berjalan ikut dicabut
+		if err := revokeRefreshTokens(tx, models.TokenRevokedLogout, "user_id = ?", user.ID); err != nil {
+			return err
+		}
+		return audit.Record(tx, models.AuditTwoFactorReset, "user", &user.ID, map[string]interface{}{
+			"email": user.Email,
+		})
+	})
+}
+
+// deleteTwoFactor - Hapus permanen secret & recovery code user
+func deleteTwoFactor(tx *gorm.DB, userID uuid.UUID) error {
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.UserTwoFactor{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+}
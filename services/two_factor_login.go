@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"technical-test-backend/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidLoginChallenge - Challenge token login 2FA tidak dikenal, kadaluarsa, atau sudah dipakai (HTTP 401)
+var ErrInvalidLoginChallenge = errors.New("invalid or expired login challenge")
+
+// LoginChallenge - Dikembalikan /auth/login jika user harus memasukkan kode 2FA (atau enrolment dulu)
+type LoginChallenge struct {
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int64  `json:"expires_in"`     // Detik sampai challenge kadaluarsa
+	SetupRequired  bool   `json:"setup_required"` // Role wajib 2FA tapi user belum enrolment
+}
+
+// TwoFactorLoginInput - Langkah kedua login: challenge token + kode TOTP 6 digit atau recovery code
+type TwoFactorLoginInput struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// LoginChallengeInput - Challenge token untuk enrolment 2FA wajib saat login
+type LoginChallengeInput struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+// twoFactorChallengeTTL - Umur challenge token login (TWO_FACTOR_CHALLENGE_TTL, default 5m)
+func twoFactorChallengeTTL() time.Duration {
+	return utils.EnvDuration("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute)
+}
+
+// issueLoginChallenge - Challenge token sekali pakai setelah password benar
+func issueLoginChallenge(user models.User, setupRequired bool) (*LoginChallenge, error) {
+	var token string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		token, err = createUserToken(tx, user.ID, models.UserTokenLoginChallenge, user.Email, twoFactorChallengeTTL())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &LoginChallenge{
+		ChallengeToken: token,
+		ExpiresIn:      int64(twoFactorChallengeTTL().Seconds()),
+		SetupRequired:  setupRequired,
+	}, nil
+}
+
+// loadLoginChallenge - Kunci challenge token yang masih berlaku beserta user-nya
+func loadLoginChallenge(tx *gorm.DB, token string) (models.UserToken, models.User, error) {
+	challenge, err := findUserToken(tx, token, models.UserTokenLoginChallenge)
+	if err != nil {
+		if errors.Is(err, ErrInvalidUserToken) {
+			return challenge, models.User{}, ErrInvalidLoginChallenge
+		}
+		return challenge, models.User{}, err
+	}
+	user, err := loadUserWithRole(tx, challenge.UserID)
+	if err != nil {
+		return challenge, user, ErrInvalidLoginChallenge
+	}
+	return challenge, user, nil
+}
+
+// VerifyTwoFactorLogin - Langkah kedua login: kode TOTP / recovery code ditukar dengan access + refresh token.
+// Kode salah dihitung sebagai login gagal (backoff & lockout yang sama dengan password salah).
+func (s *AuthService) VerifyTwoFactorLogin(input TwoFactorLoginInput, meta ClientMeta) (LoginResponse, error) {
+	var response LoginResponse
+	var user models.User
+	failed := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var challenge models.UserToken
+		var err error
+		if challenge, user, err = loadLoginChallenge(tx, input.ChallengeToken); err != nil {
+			return err
+		}
+		if err := checkLoginAllowed(user.Email, meta.IP, time.Now()); err != nil {
+			return err
+		}
+
+		row, found, err := getTwoFactor(tx, user.ID, true)
+		if err != nil {
+			return err
+		}
+		if !found || row.EnabledAt == nil {
+			return ErrTwoFactorNotEnabled
+		}
+		ok, recovery, err := verifySecondFactor(tx, &row, input.Code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			failed = true
+			return ErrInvalidTwoFactorCode
+		}
+		if recovery {
+			if err := createAuditLog(tx, &user.ID, models.AuditRecoveryCodeUsed, "user", &user.ID, meta.IP, nil); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&challenge).Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		tokens, _, err := issueTokenPair(tx, user, uuid.New(), meta)
+		response = LoginResponse{Tokens: tokens, User: user}
+		return err
+	})
+	if failed {
+		recordLoginFailure(user.Email, meta, &user.ID, time.Now())
+	}
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	resetLoginFailures(user.Email)
+	return response, nil
+}
+
+// StartChallengeSetup - Enrolment 2FA wajib saat login (challenge dengan setup_required = true)
+func (s *AuthService) StartChallengeSetup(input LoginChallengeInput) (TwoFactorSetup, error) {
+	var setup TwoFactorSetup
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		_, user, err := loadLoginChallenge(tx, input.ChallengeToken)
+		if err != nil {
+			return err
+		}
+		setup, err = startTwoFactorSetup(tx, user)
+		return err
+	})
+	return setup, err
+}
+
+// ConfirmChallengeSetup - Konfirmasi enrolment 2FA wajib dengan kode pertama, lalu login selesai
+// (access + refresh token dan recovery code dikembalikan sekaligus)
+func (s *AuthService) ConfirmChallengeSetup(input TwoFactorLoginInput, meta ClientMeta) (LoginResponse, error) {
+	var response LoginResponse
+	var user models.User
+	failed := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var challenge models.UserToken
+		var err error
+		if challenge, user, err = loadLoginChallenge(tx, input.ChallengeToken); err != nil {
+			return err
+		}
+		if err := checkLoginAllowed(user.Email, meta.IP, time.Now()); err != nil {
+			return err
+		}
+
+		codes, err := confirmTwoFactorSetup(tx, user, input.Code, meta.IP)
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			failed = true
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&challenge).Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		tokens, _, err := issueTokenPair(tx, user, uuid.New(), meta)
+		response = LoginResponse{Tokens: tokens, User: user, RecoveryCodes: codes}
+		return err
+	})
+	if failed {
+		recordLoginFailure(user.Email, meta, &user.ID, time.Now())
+	}
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	resetLoginFailures(user.Email)
+	return response, nil
+}
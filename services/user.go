@@ -4,8 +4,10 @@ import (
 	"errors"
 	"technical-test-backend/database"
 	"technical-test-backend/models"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type UserService struct{}
@@ -15,14 +17,15 @@ type CreateAdminInput struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
+	RoleID   string `json:"role_id"` // Opsional, kosong = role (sistem) dengan permission users.manage
 }
 
 // CREATE ADMIN (Hanya bisa dilakukan oleh Admin lain)
+// Role tidak dicari dari nama (role Admin bisa di-rename lewat API role)
 func (s *UserService) CreateAdmin(input CreateAdminInput) error {
-	// Cari Role ID untuk "Admin"
-	var role models.Role
-	if err := database.DB.Where("name = ?", "Admin").First(&role).Error; err != nil {
-		return errors.New("role admin tidak ditemukan di database")
+	role, err := resolveAdminRole(database.DB, input.RoleID)
+	if err != nil {
+		return err
 	}
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
@@ -40,12 +43,53 @@ func (s *UserService) CreateAdmin(input CreateAdminInput) error {
 	return nil
 }
 
+// resolveAdminRole - Role dari role_id, atau role yang memiliki permission users.manage
+// (role sistem lebih dulu, lalu yang paling lama) jika role_id kosong
+func resolveAdminRole(tx *gorm.DB, roleID string) (models.Role, error) {
+	var role models.Role
+	if roleID != "" {
+		if err := tx.First(&role, "id = ?", roleID).Error; err != nil {
+			return role, errors.New("role tidak ditemukan")
+		}
+		return role, nil
+	}
+	err := tx.Joins("JOIN role_permissions rp ON rp.role_id = roles.id").
+		Joins("JOIN permissions ON permissions.id = rp.permission_id AND permissions.deleted_at IS NULL").
+		Where("permissions.name = ?", models.PermUsersManage).
+		Order("roles.is_system DESC, roles.created_at ASC").
+		First(&role).Error
+	if err != nil {
+		return role, errors.New("tidak ada role dengan permission " + models.PermUsersManage)
+	}
+	return role, nil
+}
+
 // DELETE USER (Fitur Admin)
-func (s *UserService) DeleteUser(userID string) error {
-	if err := database.DB.Delete(&models.User{}, "id = ?", userID).Error; err != nil {
+// Bukan hard delete: data pribadi dianonimkan & akun dinonaktifkan permanen, baris user tetap ada
+// agar transaksi, review, dan etalase yang merujuk user ini tidak error (FK) atau yatim.
+func (s *UserService) DeleteUser(userID string, audit *AuditContext) error {
+	actorUUID, err := audit.actor()
+	if err != nil {
 		return err
 	}
-	return nil
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := lockManagedUser(tx, userID, actorUUID)
+		if err != nil {
+			return err
+		}
+		before := map[string]interface{}{"status": user.StatusAt(time.Now()), "role_id": user.RoleID}
+		if err := anonymizeUser(tx, &user, time.Now()); err != nil {
+			return err
+		}
+		if err := ensureRoleManagerExists(tx); err != nil {
+			return err
+		}
+		// Email asli tidak dicatat (data pribadi), cukup ID user
+		return audit.Record(tx, models.AuditUserAnonymized, "user", &user.ID, map[string]interface{}{
+			"before": before,
+		})
+	})
 }
 
 // LIST USERS
@@ -74,11 +118,13 @@ type UpdateUserInput struct {
 	RoleID *string `json:"role_id"`
 }
 
-func (s *UserService) UpdateUser(userID string, input UpdateUserInput) (models.User, error) {
+// UpdateUser - Perubahan (termasuk ganti role) dicatat di audit log sebagai USER_UPDATED (before/after)
+func (s *UserService) UpdateUser(userID string, input UpdateUserInput, audit *AuditContext) (models.User, error) {
 	var user models.User
 	if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
 		return user, err
 	}
+	before := map[string]interface{}{"name": user.Name, "email": user.Email, "role_id": user.RoleID.String()}
 
 	updates := make(map[string]interface{})
 	if input.Name != nil {
@@ -91,7 +137,19 @@ func (s *UserService) UpdateUser(userID string, input UpdateUserInput) (models.U
 		updates["role_id"] = *input.RoleID
 	}
 
-	if err := database.DB.Model(&user).Updates(updates).Error; err != nil {
+	// Ganti role tidak boleh menghapus user terakhir yang bisa mengelola role
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			return err
+		}
+		if input.RoleID != nil {
+			if err := ensureRoleManagerExists(tx); err != nil {
+				return err
+			}
+		}
+		return audit.Record(tx, models.AuditUserUpdated, "user", &user.ID, auditChanges(before, updates))
+	})
+	if err != nil {
 		return user, err
 	}
 
@@ -99,3 +157,52 @@ func (s *UserService) UpdateUser(userID string, input UpdateUserInput) (models.U
 	database.DB.Preload("Role").Omit("password").First(&user, "id = ?", userID)
 	return user, nil
 }
+
+// UnlockLoginInput - IP (opsional) yang ikut dibuka blokirnya
+type UnlockLoginInput struct {
+	IP string `json:"ip" binding:"omitempty,ip"`
+}
+
+// UnlockLoginResult - Status blokir sebelum di-unlock
+type UnlockLoginResult struct {
+	WasLocked bool `json:"was_locked"`
+	Failures  int  `json:"failures"`
+}
+
+// UnlockLogin - Admin membuka kunci / backoff login akun (dan IP jika diisi), dicatat di audit log
+func (s *UserService) UnlockLogin(userID string, audit *AuditContext, input UnlockLoginInput) (UnlockLoginResult, error) {
+	var user models.User
+	if err := database.DB.First(&user, "id = ?", userID).Error; err != nil {
+		return UnlockLoginResult{}, err
+	}
+	if _, err := audit.actor(); err != nil {
+		return UnlockLoginResult{}, err
+	}
+
+	now := time.Now()
+	keys := []string{loginKey("account", user.Email)}
+	if input.IP != "" {
+		keys = append(keys, loginKey("ip", input.IP))
+	}
+
+	var result UnlockLoginResult
+	for _, key := range keys {
+		state, err := loginAttemptStore().Get(key)
+		if err != nil {
+			return result, err
+		}
+		result.WasLocked = result.WasLocked || now.Before(state.BlockedUntil)
+		result.Failures = max(result.Failures, state.Failures)
+		if err := loginAttemptStore().Reset(key); err != nil {
+			return result, err
+		}
+	}
+
+	err := audit.Record(database.DB, models.AuditLoginUnlock, "user", &user.ID, map[string]interface{}{
+		"email":      user.Email,
+		"ip":         input.IP,
+		"was_locked": result.WasLocked,
+		"failures":   result.Failures,
+	})
+	return result, err
+}
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WarehouseService menangani data gudang, stok per gudang, dan transfer antar gudang
+type WarehouseService struct{}
+
+// ErrWarehouseInUse - Gudang masih menyimpan stok atau merupakan gudang default (HTTP 409)
+var ErrWarehouseInUse = errors.New("warehouse is still in use")
+
+// WarehouseInput - Input create/update gudang
+type WarehouseInput struct {
+	Code      string   `json:"code" binding:"required,max=20"`
+	Name      string   `json:"name" binding:"required"`
+	Address   string   `json:"address"`
+	City      string   `json:"city"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	IsDefault bool     `json:"is_default"`
+	IsActive  *bool    `json:"is_active"`
+}
+
+func (s *WarehouseService) GetAll() ([]models.Warehouse, error) {
+	var warehouses []models.Warehouse
+	err := database.DB.Order("is_default DESC, name ASC").Find(&warehouses).Error
+	return warehouses, err
+}
+
+func (s *WarehouseService) Create(input WarehouseInput) (models.Warehouse, error) {
+	warehouse := models.Warehouse{
+		Code:      strings.ToUpper(input.Code),
+		Name:      input.Name,
+		Address:   input.Address,
+		City:      input.City,
+		Latitude:  input.Latitude,
+		Longitude: input.Longitude,
+		IsDefault: input.IsDefault,
+		IsActive:  input.IsActive == nil || *input.IsActive,
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if warehouse.IsDefault {
+			if err := tx.Model(&models.Warehouse{}).Where("is_default = ?", true).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&warehouse).Error
+	})
+	return warehouse, err
+}
+
+func (s *WarehouseService) Update(id string, input WarehouseInput) (models.Warehouse, error) {
+	var warehouse models.Warehouse
+	if err := database.DB.First(&warehouse, "id = ?", id).Error; err != nil {
+		return warehouse, err
+	}
+
+	if warehouse.IsDefault && !input.IsDefault {
+		return warehouse, errors.New("set another warehouse as default instead of unsetting the current default")
+	}
+	if warehouse.IsDefault && input.IsActive != nil && !*input.IsActive {
+		return warehouse, errors.New("default warehouse cannot be deactivated")
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if input.IsDefault && !warehouse.IsDefault {
+			if err := tx.Model(&models.Warehouse{}).Where("is_default = ?", true).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+
+		updates := map[string]interface{}{
+			"code":       strings.ToUpper(input.Code),
+			"name":       input.Name,
+			"address":    input.Address,
+			"city":       input.City,
+			"latitude":   input.Latitude,
+			"longitude":  input.Longitude,
+			"is_default": input.IsDefault,
+		}
+		if input.IsActive != nil {
+			updates["is_active"] = *input.IsActive
+		}
+		return tx.Model(&warehouse).Updates(updates).Error
+	})
+	if err != nil {
+		return warehouse, err
+	}
+
+	database.DB.First(&warehouse, "id = ?", id)
+	return warehouse, nil
+}
+
+// Delete - Soft delete gudang yang sudah kosong
+func (s *WarehouseService) Delete(id string) error {
+	var warehouse models.Warehouse
+	if err := database.DB.First(&warehouse, "id = ?", id).Error; err != nil {
+		return err
+	}
+	if warehouse.IsDefault {
+		return fmt.Errorf("%w: default warehouse cannot be deleted", ErrWarehouseInUse)
+	}
+
+	var remaining int64
+	database.DB.Model(&models.WarehouseStock{}).
+		Where("warehouse_id = ? AND quantity > 0", warehouse.ID).
+		Count(&remaining)
+	if remaining > 0 {
+		return fmt.Errorf("%w: %d product(s) still have stock here", ErrWarehouseInUse, remaining)
+	}
+
+	return database.DB.Delete(&warehouse).Error
+}
+
+// WarehouseStockLevel - Stok satu produk di satu gudang
+type WarehouseStockLevel struct {
+	WarehouseID   string `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+	WarehouseName string `json:"warehouse_name"`
+	ProductID     string `json:"product_id,omitempty"`
+	ProductName   string `json:"product_name,omitempty"`
+	Quantity      int    `json:"quantity"`
+}
+
+// GetStock - Daftar stok semua produk di satu gudang
+func (s *WarehouseService) GetStock(warehouseID string) ([]WarehouseStockLevel, error) {
+	var warehouse models.Warehouse
+	if err := database.DB.First(&warehouse, "id = ?", warehouseID).Error; err != nil {
+		return nil, err
+	}
+
+	var levels []models.WarehouseStock
+	err := database.DB.Preload("Product").
+		Joins("JOIN products ON products.id = warehouse_stocks.product_id AND products.deleted_at IS NULL").
+		Where("warehouse_stocks.warehouse_id = ?", warehouse.ID).
+		Order("products.name ASC").
+		Find(&levels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := []WarehouseStockLevel{}
+	for _, level := range levels {
+		result = append(result, WarehouseStockLevel{
+			WarehouseID:   warehouse.ID.String(),
+			WarehouseCode: warehouse.Code,
+			WarehouseName: warehouse.Name,
+			ProductID:     level.ProductID.String(),
+			ProductName:   level.Product.Name,
+			Quantity:      level.Quantity,
+		})
+	}
+	return result, nil
+}
+
+// getStockBreakdown - Stok per gudang untuk sekumpulan produk, dikelompokkan per product ID
+func getStockBreakdown(productIDs []uuid.UUID) (map[uuid.UUID][]WarehouseStockLevel, error) {
+	breakdown := make(map[uuid.UUID][]WarehouseStockLevel)
+	if len(productIDs) == 0 {
+		return breakdown, nil
+	}
+
+	var levels []models.WarehouseStock
+	err := database.DB.Preload("Warehouse").
+		Joins("JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id AND warehouses.deleted_at IS NULL").
+		Where("warehouse_stocks.product_id IN ?", productIDs).
+		Order("warehouses.name ASC").
+		Find(&levels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, level := range levels {
+		breakdown[level.ProductID] = append(breakdown[level.ProductID], WarehouseStockLevel{
+			WarehouseID:   level.WarehouseID.String(),
+			WarehouseCode: level.Warehouse.Code,
+			WarehouseName: level.Warehouse.Name,
+			Quantity:      level.Quantity,
+		})
+	}
+	return breakdown, nil
+}
+
+// TransferInput - Input perpindahan stok antar gudang
+type TransferInput struct {
+	FromWarehouseID string `json:"from_warehouse_id" binding:"required"`
+	ToWarehouseID   string `json:"to_warehouse_id" binding:"required"`
+	ProductID       string `json:"product_id" binding:"required"`
+	Quantity        int    `json:"quantity" binding:"required,min=1"`
+	Note            string `json:"note"`
+}
+
+// Transfer - Pindahkan stok antar gudang
+// Alur: Validasi gudang -> Buat dokumen transfer -> Movement TRANSFER_OUT -> Movement TRANSFER_IN
+// Total Product.Stock tidak berubah, hanya distribusi per gudang
+func (s *WarehouseService) Transfer(actorID string, input TransferInput) (models.StockTransfer, error) {
+	if input.FromWarehouseID == input.ToWarehouseID {
+		return models.StockTransfer{}, errors.New("source and destination warehouse must be different")
+	}
+
+	var transfer models.StockTransfer
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var from, to models.Warehouse
+		if err := tx.First(&from, "id = ?", input.FromWarehouseID).Error; err != nil {
+			return errors.New("source warehouse not found")
+		}
+		if err := tx.First(&to, "id = ?", input.ToWarehouseID).Error; err != nil {
+			return errors.New("destination warehouse not found")
+		}
+		if !to.IsActive {
+			return errors.New("destination warehouse is not active")
+		}
+
+		var product models.Product
+		if err := tx.First(&product, "id = ?", input.ProductID).Error; err != nil {
+			return errors.New("product not found")
+		}
+
+		transfer = models.StockTransfer{
+			FromWarehouseID: from.ID,
+			ToWarehouseID:   to.ID,
+			ProductID:       product.ID,
+			Quantity:        input.Quantity,
+			Note:            input.Note,
+			ActorID:         parseOptionalUUID(actorID),
+		}
+		if err := tx.Create(&transfer).Error; err != nil {
+			return err
+		}
+
+		reason := fmt.Sprintf("Transfer %s -> %s", from.Code, to.Code)
+		if input.Note != "" {
+			reason += ": " + input.Note
+		}
+		if _, err := applyStockMovement(tx, stockChange{
+			ProductID:   product.ID,
+			WarehouseID: from.ID,
+			Delta:       -input.Quantity,
+			Type:        models.MovementTransferOut,
+			Reason:      reason,
+			ActorID:     transfer.ActorID,
+			ReferenceID: &transfer.ID,
+		}); err != nil {
+			return err
+		}
+		_, err := applyStockMovement(tx, stockChange{
+			ProductID:   product.ID,
+			WarehouseID: to.ID,
+			Delta:       input.Quantity,
+			Type:        models.MovementTransferIn,
+			Reason:      reason,
+			ActorID:     transfer.ActorID,
+			ReferenceID: &transfer.ID,
+		})
+		return err
+	})
+	if err != nil {
+		return models.StockTransfer{}, err
+	}
+
+	database.DB.Preload("FromWarehouse").Preload("ToWarehouse").Preload("Product").First(&transfer, "id = ?", transfer.ID)
+	return transfer, nil
+}
+
+// GetTransfers - Histori transfer antar gudang (terbaru dulu)
+func (s *WarehouseService) GetTransfers(warehouseID string, productID string) ([]models.StockTransfer, error) {
+	query := database.DB.Preload("FromWarehouse").Preload("ToWarehouse").Preload("Product")
+	if warehouseID != "" {
+		query = query.Where("from_warehouse_id = ? OR to_warehouse_id = ?", warehouseID, warehouseID)
+	}
+	if productID != "" {
+		query = query.Where("product_id = ?", productID)
+	}
+
+	var transfers []models.StockTransfer
+	err := query.Order("created_at DESC").Find(&transfers).Error
+	return transfers, err
+}
+
+// stockAllocation - Jumlah yang diambil dari satu gudang untuk satu order
+type stockAllocation struct {
+	WarehouseID uuid.UUID
+	Quantity    int
+}
+
+// allocateStock - Strategi pemilihan gudang saat ConfirmOrder
+// Urutan kandidat: gudang aktif terdekat dari alamat pembeli (koordinat, lalu kota yang sama),
+// jika jarak sama pilih stok terbanyak. Gudang pertama yang bisa memenuhi seluruh quantity
+// dipakai sendiri; jika tidak ada, order dipecah mengikuti urutan yang sama.
+func allocateStock(tx *gorm.DB, productID uuid.UUID, quantity int, buyer models.User) ([]stockAllocation, error) {
+	var levels []models.WarehouseStock
+	err := tx.Preload("Warehouse").
+		Joins("JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id AND warehouses.deleted_at IS NULL").
+		Where("warehouse_stocks.product_id = ? AND warehouse_stocks.quantity > 0 AND warehouses.is_active = ?", productID, true).
+		Find(&levels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		level    models.WarehouseStock
+		distance float64
+	}
+	candidates := make([]candidate, 0, len(levels))
+	for _, level := range levels {
+		candidates = append(candidates, candidate{level: level, distance: warehouseDistance(level.Warehouse, buyer)})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].level.Quantity > candidates[j].level.Quantity
+	})
+
+	// 1. Satu gudang yang bisa memenuhi seluruh order (hindari split pengiriman)
+	for _, c := range candidates {
+		if c.level.Quantity >= quantity {
+			return []stockAllocation{{WarehouseID: c.level.WarehouseID, Quantity: quantity}}, nil
+		}
+	}
+
+	// 2. Pecah order ke beberapa gudang
+	var allocations []stockAllocation
+	remaining := quantity
+	for _, c := range candidates {
+		if remaining == 0 {
+			break
+		}
+		take := c.level.Quantity
+		if take > remaining {
+			take = remaining
+		}
+		allocations = append(allocations, stockAllocation{WarehouseID: c.level.WarehouseID, Quantity: take})
+		remaining -= take
+	}
+	if remaining > 0 {
+		return nil, fmt.Errorf("%w: only %d available in active warehouses", ErrInsufficientStock, quantity-remaining)
+	}
+	return allocations, nil
+}
+
+// warehouseDistance - Jarak gudang ke pembeli dalam km
+// Tanpa koordinat: kota sama = 0, selain itu dianggap sangat jauh
+func warehouseDistance(warehouse models.Warehouse, buyer models.User) float64 {
+	const unknown = math.MaxFloat64
+	if warehouse.Latitude != nil && warehouse.Longitude != nil && buyer.Latitude != nil && buyer.Longitude != nil {
+		return haversineKm(*buyer.Latitude, *buyer.Longitude, *warehouse.Latitude, *warehouse.Longitude)
+	}
+	if buyer.City != "" && strings.EqualFold(strings.TrimSpace(buyer.City), strings.TrimSpace(warehouse.City)) {
+		return 0
+	}
+	return unknown
+}
+
+// haversineKm - Jarak great-circle antara dua koordinat
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
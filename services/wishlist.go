@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"technical-test-backend/database"
+	"technical-test-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WishlistService menangani wishlist pembeli dan notifikasi harga turun / stok kembali
+type WishlistService struct{}
+
+// AddToWishlistInput - Etalase seller yang disimpan
+type AddToWishlistInput struct {
+	SellerProductID string `json:"seller_product_id" binding:"required"`
+}
+
+// WishlistItemDetail - Response wishlist: kondisi etalase sekarang dibanding saat disimpan
+type WishlistItemDetail struct {
+	ID             string          `json:"id"`
+	AddedAt        time.Time       `json:"added_at"`
+	PriceWhenAdded float64         `json:"price_when_added"`
+	PriceDropped   bool            `json:"price_dropped"` // Harga efektif sekarang < harga saat disimpan
+	IsActive       bool            `json:"is_active"`     // false = seller menonaktifkan etalase
+	Listing        MarketplaceItem `json:"listing"`
+}
+
+// GetWishlist - Wishlist pembeli (terbaru dulu), termasuk etalase yang stoknya habis
+func (s *WishlistService) GetWishlist(userID string) ([]WishlistItemDetail, error) {
+	var entries []models.WishlistItem
+	if err := database.DB.Preload("SellerProduct.Product.ProductType").Preload("SellerProduct.Seller").
+		Where("user_id = ?", userID).Order("created_at DESC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	// Etalase yang sudah dihapus seller tidak ikut ditampilkan
+	visible := entries[:0]
+	listings := make([]models.SellerProduct, 0, len(entries))
+	for _, entry := range entries {
+		if entry.SellerProduct.ID == uuid.Nil {
+			continue
+		}
+		visible = append(visible, entry)
+		listings = append(listings, entry.SellerProduct)
+	}
+	entries = visible
+
+	items, err := toMarketplaceItems(listings)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]WishlistItemDetail, 0, len(entries))
+	for i, entry := range entries {
+		result = append(result, WishlistItemDetail{
+			ID:             entry.ID.String(),
+			AddedAt:        entry.CreatedAt,
+			PriceWhenAdded: entry.PriceWhenAdded,
+			PriceDropped:   items[i].EffectivePrice < entry.PriceWhenAdded,
+			IsActive:       entry.SellerProduct.IsActive,
+			Listing:        items[i],
+		})
+	}
+	return result, nil
+}
+
+// Add - Simpan etalase aktif ke wishlist. Jika sudah ada, entri lama dikembalikan (created = false).
+func (s *WishlistService) Add(userID string, input AddToWishlistInput) (models.WishlistItem, bool, error) {
+	uUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return models.WishlistItem{}, false, errors.New("invalid user ID")
+	}
+
+	var listing models.SellerProduct
+	if err := database.DB.First(&listing, "id = ? AND is_active = ?", input.SellerProductID, true).Error; err != nil {
+		return models.WishlistItem{}, false, errors.New("seller product not found or inactive")
+	}
+
+	entry := models.WishlistItem{
+		UserID:          uUUID,
+		SellerProductID: listing.ID,
+		PriceWhenAdded:  listing.SellingPrice,
+	}
+	result := database.DB.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}, {Name: "seller_product_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(&entry)
+	if result.Error != nil {
+		return models.WishlistItem{}, false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return entry, true, nil
+	}
+
+	entry = models.WishlistItem{}
+	if err := database.DB.First(&entry, "user_id = ? AND seller_product_id = ?", uUUID, listing.ID).Error; err != nil {
+		return models.WishlistItem{}, false, err
+	}
+	return entry, false, nil
+}
+
+// Remove - Hapus etalase dari wishlist pembeli
+func (s *WishlistService) Remove(userID string, sellerProductID string) error {
+	result := database.DB.Where("user_id = ? AND seller_product_id = ?", userID, sellerProductID).
+		Delete(&models.WishlistItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// notifyWishlistPriceDrop - Notifikasi ke pembeli yang menyimpan etalase saat harga jualnya turun.
+// Dipanggil dari changeSellingPrice, di dalam transaksi yang sama.
+func notifyWishlistPriceDrop(tx *gorm.DB, listing models.SellerProduct, oldPrice float64, newPrice float64) error {
+	if newPrice >= oldPrice || !listing.IsActive {
+		return nil
+	}
+
+	var userIDs []uuid.UUID
+	if err := tx.Model(&models.WishlistItem{}).Where("seller_product_id = ?", listing.ID).
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	productName := listing.Product.Name
+	if productName == "" {
+		if err := tx.Model(&models.Product{}).Where("id = ?", listing.ProductID).
+			Pluck("name", &productName).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, userID := range userIDs {
+		err := createNotification(tx, userID, models.NotificationWishlistPriceDrop,
+			"Harga turun: "+productName,
+			fmt.Sprintf("Harga %s di wishlist Anda turun dari %.2f menjadi %.2f", productName, oldPrice, newPrice),
+			map[string]interface{}{
+				"seller_product_id": listing.ID,
+				"old_price":         oldPrice,
+				"new_price":         newPrice,
+				"discount_percent":  discountPercent(oldPrice, newPrice),
+			})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyWishlistBackInStock - Notifikasi ke pembeli yang menyimpan etalase aktif dari produk
+// yang stoknya berubah dari habis menjadi tersedia. Dipanggil dari applyStockMovement.
+func notifyWishlistBackInStock(tx *gorm.DB, product models.Product) error {
+	var rows []struct {
+		UserID          uuid.UUID
+		SellerProductID uuid.UUID
+		SellingPrice    float64
+	}
+	err := tx.Table("wishlist_items").
+		Select("wishlist_items.user_id, wishlist_items.seller_product_id, seller_products.selling_price").
+		Joins("JOIN seller_products ON seller_products.id = wishlist_items.seller_product_id AND seller_products.deleted_at IS NULL").
+		Where("wishlist_items.deleted_at IS NULL").
+		Where("seller_products.product_id = ? AND seller_products.is_active = ?", product.ID, true).
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		err := createNotification(tx, row.UserID, models.NotificationWishlistBackInStock,
+			product.Name+" tersedia kembali",
+			fmt.Sprintf("%s di wishlist Anda sudah tersedia kembali", product.Name),
+			map[string]interface{}{
+				"seller_product_id": row.SellerProductID,
+				"product_id":        product.ID,
+				"selling_price":     row.SellingPrice,
+			})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// ValidateGTIN - Validasi barcode EAN-8, UPC-A (12 digit), atau EAN-13 beserta check digit GS1
+func ValidateGTIN(code string) error {
+	switch len(code) {
+	case 8, 12, 13:
+	default:
+		return errors.New("barcode must be EAN-8, UPC-A (12 digits) or EAN-13")
+	}
+	for _, ch := range code {
+		if ch < '0' || ch > '9' {
+			return errors.New("barcode must contain digits only")
+		}
+	}
+	expected := GTINCheckDigit(code[:len(code)-1])
+	if int(code[len(code)-1]-'0') != expected {
+		return fmt.Errorf("invalid barcode check digit, expected %d", expected)
+	}
+	return nil
+}
+
+// GTINCheckDigit - Hitung check digit GS1 (mod 10) dari digit tanpa check digit
+// Bobot 3 dan 1 bergantian dimulai dari digit paling kanan
+func GTINCheckDigit(digits string) int {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			sum += d * 3
+		} else {
+			sum += d
+		}
+	}
+	return (10 - sum%10) % 10
+}
+
+// SKUPrefix - 3 huruf pertama nama kategori, contoh "Elektronik" -> "ELE"
+func SKUPrefix(categoryName string) string {
+	var sb strings.Builder
+	for _, ch := range strings.ToUpper(categoryName) {
+		if unicode.IsLetter(ch) || unicode.IsDigit(ch) {
+			sb.WriteRune(ch)
+			if sb.Len() == 3 {
+				break
+			}
+		}
+	}
+	if sb.Len() == 0 {
+		return "PRD"
+	}
+	return sb.String()
+}
+
+// GenerateSKU - SKU acak berformat PREFIX-XXXXXX (hex uppercase). Keunikan dicek oleh pemanggil.
+func GenerateSKU(prefix string) string {
+	buf := make([]byte, 3)
+	rand.Read(buf)
+	return prefix + "-" + strings.ToUpper(hex.EncodeToString(buf))
+}
+
+// code128Patterns - Pola bar/space Code 128 (nilai 0-105), 1 = bar, 0 = space
+var code128Patterns = [106]string{
+	"11011001100", "11001101100", "11001100110", "10010011000", "10010001100",
+	"10001001100", "10011001000", "10011000100", "10001100100", "11001001000",
+	"11001000100", "11000100100", "10110011100", "10011011100", "10011001110",
+	"10111001100", "10011101100", "10011100110", "11001110010", "11001011100",
+	"11001001110", "11011100100", "11001110100", "11101101110", "11101001100",
+	"11100101100", "11100100110", "11101100100", "11100110100", "11100110010",
+	"11011011000", "11011000110", "11000110110", "10100011000", "10001011000",
+	"10001000110", "10110001000", "10001101000", "10001100010", "11010001000",
+	"11000101000", "11000100010", "10110111000", "10110001110", "10001101110",
+	"10111011000", "10111000110", "10001110110", "11101110110", "11010001110",
+	"11000101110", "11011101000", "11011100010", "11011101110", "11101011000",
+	"11101000110", "11100010110", "11101101000", "11101100010", "11100011010",
+	"11101111010", "11001000010", "11110001010", "10100110000", "10100001100",
+	"10010110000", "10010000110", "10000101100", "10000100110", "10110010000",
+	"10110000100", "10011010000", "10011000010", "10000110100", "10000110010",
+	"11000010010", "11001010000", "11110111010", "11000010100", "10001111010",
+	"10100111100", "10010111100", "10010011110", "10111100100", "10011110100",
+	"10011110010", "11110100100", "11110010100", "11110010010", "11011011110",
+	"11011110110", "11110110110", "10101111000", "10100011110", "10001011110",
+	"10111101000", "10111100010", "11110101000", "11110100010", "10111011110",
+	"10111101110", "11101011110", "11110101110", "11010000100", "11010010000",
+	"11010011100",
+}
+
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = "1100011101011"
+)
+
+// EncodeCode128 - Encode teks ASCII (32-126) ke modul Code 128.
+// Deretan digit genap (>= 4) dikompres dengan Code Set C, selebihnya Code Set B.
+func EncodeCode128(data string) ([]bool, error) {
+	if data == "" {
+		return nil, errors.New("code128: data is empty")
+	}
+	for _, ch := range data {
+		if ch < 32 || ch > 126 {
+			return nil, fmt.Errorf("code128: unsupported character %q", ch)
+		}
+	}
+
+	digitRun := func(from int) int {
+		n := 0
+		for from+n < len(data) && data[from+n] >= '0' && data[from+n] <= '9' {
+			n++
+		}
+		return n
+	}
+
+	var values []int
+	useC := digitRun(0) >= 4 && digitRun(0)%2 == 0 || digitRun(0) == len(data) && len(data)%2 == 0
+	if useC {
+		values = append(values, code128StartC)
+	} else {
+		values = append(values, code128StartB)
+	}
+
+	for i := 0; i < len(data); {
+		if useC {
+			if run := digitRun(i); run >= 2 {
+				values = append(values, int(data[i]-'0')*10+int(data[i+1]-'0'))
+				i += 2
+				continue
+			}
+			values = append(values, code128CodeB)
+			useC = false
+		}
+		if run := digitRun(i); run >= 4 && run%2 == 0 {
+			values = append(values, code128CodeC)
+			useC = true
+			continue
+		}
+		values = append(values, int(data[i])-32)
+		i++
+	}
+
+	checksum := values[0]
+	for i := 1; i < len(values); i++ {
+		checksum += values[i] * i
+	}
+	values = append(values, checksum%103)
+
+	var sb strings.Builder
+	for _, v := range values {
+		sb.WriteString(code128Patterns[v])
+	}
+	sb.WriteString(code128Stop)
+	return patternToModules(sb.String()), nil
+}
+
+// Pola digit EAN-13, set G dan R diturunkan dari set L
+var (
+	eanL      = [10]string{"0001101", "0011001", "0010011", "0111101", "0100011", "0110001", "0101111", "0111011", "0110111", "0001011"}
+	eanParity = [10]string{"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG", "LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL"}
+)
+
+// EncodeEAN13 - Encode 13 digit EAN-13 (check digit divalidasi) ke 95 modul
+func EncodeEAN13(code string) ([]bool, error) {
+	if len(code) != 13 {
+		return nil, errors.New("ean13: code must be 13 digits")
+	}
+	if err := ValidateGTIN(code); err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("101")
+	parity := eanParity[code[0]-'0']
+	for i := 1; i <= 6; i++ {
+		l := eanL[code[i]-'0']
+		if parity[i-1] == 'G' {
+			sb.WriteString(reverseString(invertPattern(l)))
+		} else {
+			sb.WriteString(l)
+		}
+	}
+	sb.WriteString("01010")
+	for i := 7; i <= 12; i++ {
+		sb.WriteString(invertPattern(eanL[code[i]-'0']))
+	}
+	sb.WriteString("101")
+	return patternToModules(sb.String()), nil
+}
+
+func patternToModules(pattern string) []bool {
+	modules := make([]bool, len(pattern))
+	for i, ch := range pattern {
+		modules[i] = ch == '1'
+	}
+	return modules
+}
+
+func invertPattern(p string) string {
+	b := []byte(p)
+	for i := range b {
+		if b[i] == '0' {
+			b[i] = '1'
+		} else {
+			b[i] = '0'
+		}
+	}
+	return string(b)
+}
+
+func reverseString(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+// barcodeQuietZone - Ruang kosong kiri/kanan (dalam modul) agar bisa di-scan
+const barcodeQuietZone = 10
+
+// RenderBarcodePNG - Gambar barcode hitam putih. scale = lebar 1 modul dalam pixel.
+func RenderBarcodePNG(w io.Writer, modules []bool, scale int, height int) error {
+	if scale <= 0 {
+		scale = 2
+	}
+	width := (len(modules) + 2*barcodeQuietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	for i, bar := range modules {
+		if !bar {
+			continue
+		}
+		x0 := (i + barcodeQuietZone) * scale
+		for x := x0; x < x0+scale; x++ {
+			for y := 0; y < height; y++ {
+				img.SetGray(x, y, color.Gray{Y: 0})
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// RenderBarcodeSVG - Barcode vektor dengan teks yang bisa dibaca manusia di bawahnya
+func RenderBarcodeSVG(w io.Writer, modules []bool, scale int, height int, text string) error {
+	if scale <= 0 {
+		scale = 2
+	}
+	width := (len(modules) + 2*barcodeQuietZone) * scale
+	textHeight := 0
+	if text != "" {
+		textHeight = 8 * scale
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		width, height+textHeight, width, height+textHeight)
+	fmt.Fprintf(&sb, `<rect width="100%%" height="100%%" fill="#fff"/>`)
+	for i := 0; i < len(modules); {
+		if !modules[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < len(modules) && modules[i] {
+			i++
+		}
+		fmt.Fprintf(&sb, `<rect x="%d" y="0" width="%d" height="%d" fill="#000"/>`,
+			(start+barcodeQuietZone)*scale, (i-start)*scale, height)
+	}
+	if text != "" {
+		fmt.Fprintf(&sb, `<text x="%d" y="%d" font-family="monospace" font-size="%d" text-anchor="middle">%s</text>`,
+			width/2, height+textHeight-scale, 6*scale, escapeSVGText(text))
+	}
+	sb.WriteString(`</svg>`)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func escapeSVGText(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
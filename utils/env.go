@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// EnvInt - Baca environment variable sebagai int, pakai fallback jika kosong / tidak valid
+func EnvInt(key string, fallback int) int {
+	if val, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return val
+	}
+	return fallback
+}
+
+// EnvFloat - Baca environment variable sebagai float64
+func EnvFloat(key string, fallback float64) float64 {
+	if val, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return val
+	}
+	return fallback
+}
+
+// EnvDuration - Baca environment variable sebagai time.Duration (contoh: "24h", "30m")
+func EnvDuration(key string, fallback time.Duration) time.Duration {
+	if val, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return val
+	}
+	return fallback
+}
+
+// EnvString - Baca environment variable, pakai fallback jika kosong
+func EnvString(key string, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
+
+// EnvBool - Baca environment variable sebagai bool ("true", "1", "false", "0", ...)
+func EnvBool(key string, fallback bool) bool {
+	if val, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return val
+	}
+	return fallback
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SlugMaxLength - Panjang maksimal slug (sesuai kolom seller_profiles.slug)
+const SlugMaxLength = 120
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Slugify - Ubah teks bebas menjadi slug URL, contoh "Toko Elektronik Jaya!" -> "toko-elektronik-jaya"
+// Hasil kosong jika teks tidak mengandung huruf / angka ASCII.
+func Slugify(text string) string {
+	var sb strings.Builder
+	dash := false
+	for _, ch := range strings.ToLower(text) {
+		if ch < unicode.MaxASCII && (unicode.IsLetter(ch) || unicode.IsDigit(ch)) {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(ch)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	slug := sb.String()
+	if len(slug) > SlugMaxLength-10 {
+		// Sisakan ruang untuk suffix "-N" dari UniqueSlug
+		slug = strings.TrimRight(slug[:SlugMaxLength-10], "-")
+	}
+	return slug
+}
+
+// ValidSlug - Slug hanya huruf kecil, angka, dan tanda hubung tunggal di tengah
+func ValidSlug(slug string) bool {
+	return len(slug) <= SlugMaxLength && slugPattern.MatchString(slug)
+}
+
+// UniqueSlug - base, lalu base-2, base-3, ... sampai exists mengembalikan false
+func UniqueSlug(base string, exists func(slug string) bool) string {
+	slug := base
+	for i := 2; exists(slug); i++ {
+		slug = base + "-" + strconv.Itoa(i)
+	}
+	return slug
+}